@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/example/LottoSmash/internal/notification"
+)
+
+// Web Push용 VAPID 키 쌍 생성 도구
+// 출력된 값을 config.json의 webPush.vapidPublicKey / vapidPrivateKey에 설정한다.
+// 키를 바꾸면 기존 브라우저 구독은 모두 무효가 되므로 한 번만 생성해 보관한다.
+func main() {
+	publicKey, privateKey, err := notification.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatalf("failed to generate VAPID keys: %v", err)
+	}
+	fmt.Printf("vapidPublicKey:  %s\n", publicKey)
+	fmt.Printf("vapidPrivateKey: %s\n", privateKey)
+}
@@ -11,6 +11,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/example/LottoSmash/internal/auth"
 	"github.com/example/LottoSmash/internal/config"
 	"github.com/example/LottoSmash/internal/database"
 	"github.com/example/LottoSmash/internal/logger"
@@ -97,14 +98,15 @@ func main() {
 		dbCfg.Password = pass
 	}
 
-	db, err := database.New(database.Config{
+	dbConn := database.Config{
 		Host:     dbCfg.Host,
 		Port:     dbCfg.Port,
 		User:     dbCfg.User,
 		Password: dbCfg.Password,
 		DBName:   dbCfg.DBName,
 		SSLMode:  dbCfg.SSLMode,
-	})
+	}
+	db, err := database.New(dbConn)
 	if err != nil {
 		lg.Errorf("failed to connect to database: %v", err)
 	} else {
@@ -131,13 +133,20 @@ func main() {
 		lottoRepo := lotto.NewRepository(db)
 		lottoClient := lotto.NewClient()
 		lottoAnalyzer := lotto.NewAnalyzer(lottoRepo, lg)
+		lottoAnalyzer.DecayHalfLives = cfgMgr.Config().Analysis.DecayHalfLives
 		lottoSvc = lotto.NewService(lottoRepo, lottoClient, lottoAnalyzer, lg)
+		lottoSvc.SetJobPool(pools.MainInput)
 
 		// config에서 크롤러 설정 주입
 		crawlerCfg := cfgMgr.Config().Crawler
 		lottoSvc.CrawlerBatchSize = crawlerCfg.BatchSize
 		lottoSvc.CrawlerBatchDelay = time.Duration(crawlerCfg.BatchDelayMs) * time.Millisecond
 
+		// 분석 기법/조합 방법 레지스트리 로드 (실패해도 첫 요청 시 다시 시도)
+		if _, err := lottoSvc.ReloadMethodRegistry(ctx); err != nil {
+			lg.Errorf("failed to load method registry: %v", err)
+		}
+
 		// 서버 시작 시 당첨번호 초기화
 		docsPath := cfgMgr.ResolvePath("docs")
 		if err := lottoSvc.InitializeDraws(ctx, docsPath); err != nil {
@@ -149,8 +158,87 @@ func main() {
 	var notifSvc *notification.Service
 	if db != nil && lottoSvc != nil {
 		notifRepo := notification.NewRepository(db)
-		pushSender := notification.NewNoopPushSender(lg)
-		notifSvc = notification.NewService(notifRepo, lottoSvc, pushSender, lg)
+		var pushSender notification.PushSender = notification.NewNoopPushSender(lg)
+		if fcmCfg := cfgMgr.Config().FCM; fcmCfg.Enabled {
+			fcmSender, err := notification.NewFCMSenderFromFile(cfgMgr.ResolvePath(fcmCfg.CredentialsFile), fcmCfg.BaseURL)
+			if err != nil {
+				lg.Errorf("failed to initialize FCM sender, push disabled: %v", err)
+			} else {
+				pushSender = fcmSender
+				lg.Infof("FCM push sender initialized")
+			}
+		}
+		router := notification.NewPlatformRouter(pushSender)
+		if apnsCfg := cfgMgr.Config().APNs; apnsCfg.Enabled {
+			apnsSender, err := notification.NewAPNsSenderFromFile(cfgMgr.ResolvePath(apnsCfg.KeyFile), notification.APNsOptions{
+				KeyID:      apnsCfg.KeyID,
+				TeamID:     apnsCfg.TeamID,
+				Topic:      apnsCfg.Topic,
+				Production: apnsCfg.Production,
+				Endpoint:   apnsCfg.Endpoint,
+			})
+			if err != nil {
+				lg.Errorf("failed to initialize APNs sender, iOS uses default sender: %v", err)
+			} else {
+				router.Route(notification.PlatformIOS, apnsSender)
+				lg.Infof("APNs push sender initialized (production=%v)", apnsCfg.Production)
+			}
+		}
+		var webPushKey string
+		if webCfg := cfgMgr.Config().WebPush; webCfg.Enabled {
+			webSender, err := notification.NewWebPushSender(webCfg.VAPIDPublicKey, webCfg.VAPIDPrivateKey, webCfg.Subject)
+			if err != nil {
+				lg.Errorf("failed to initialize web push sender: %v", err)
+			} else {
+				router.Route(notification.PlatformWeb, webSender)
+				webPushKey = webSender.PublicKey()
+				lg.Infof("web push sender initialized")
+			}
+		}
+		queueCfg := cfgMgr.Config().PushQueue
+		dispatcher := notification.NewDispatcher(notifRepo, router, lg, notification.DispatcherOptions{
+			Interval:       time.Duration(queueCfg.IntervalSec) * time.Second,
+			BatchSize:      queueCfg.BatchSize,
+			MaxAttempts:    queueCfg.MaxAttempts,
+			RetryBaseDelay: time.Duration(queueCfg.RetryBaseSec) * time.Second,
+			RetryMaxDelay:  time.Duration(queueCfg.RetryMaxSec) * time.Second,
+		})
+		var unsubscribeTokens *notification.UnsubscribeTokens
+		if emailCfg := cfgMgr.Config().EmailNotifications; emailCfg.Enabled {
+			var emailSender notification.EmailSender = auth.NewNoopEmailSender()
+			if smtpCfg := cfgMgr.Config().SMTP; smtpCfg.Enabled {
+				emailSender = auth.NewSMTPEmailSender(auth.SMTPConfig{
+					Host:     smtpCfg.Host,
+					Port:     smtpCfg.Port,
+					Username: smtpCfg.Username,
+					Password: smtpCfg.Password,
+					From:     smtpCfg.From,
+				})
+			}
+			unsubscribeTokens = notification.NewUnsubscribeTokens(emailCfg.UnsubscribeSecret)
+			dispatcher.SetEmailSender(emailSender, notification.NewEmailRenderer(emailCfg.BaseURL, unsubscribeTokens))
+			lg.Infof("email notification channel initialized")
+		}
+		go dispatcher.Start(ctx)
+		notifSvc = notification.NewService(notifRepo, lottoSvc, dispatcher, lg)
+		if unsubscribeTokens != nil {
+			notifSvc.SetEmailChannel(unsubscribeTokens)
+		}
+		if streamCfg := cfgMgr.Config().Stream; streamCfg.Enabled {
+			hub := notification.NewHub(lg)
+			if streamCfg.Backplane {
+				backplane := notification.NewPGBackplane(db, dbConn.DSN(), hub, lg)
+				hub.SetBackplane(backplane)
+				go backplane.Start(ctx)
+			}
+			notifSvc.SetStreamHub(hub)
+			lottoSvc.SetJobNotifier(notifSvc)
+			lg.Infof("notification stream initialized (backplane=%v)", streamCfg.Backplane)
+		}
+		notifSvc.SetWebPushPublicKey(webPushKey)
+		notifSvc.SetJobPool(pools.MainInput)
+		notifSvc.BroadcastBatchSize = cfgMgr.Config().DrawNotifications.BatchSize
+		lottoSvc.SetWinningChecker(notifSvc)
 		lg.Infof("notification service initialized")
 	}
 
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/example/LottoSmash/internal/config"
+	"github.com/example/LottoSmash/internal/database"
+	"github.com/example/LottoSmash/internal/logger"
+	"github.com/example/LottoSmash/internal/lotto"
+)
+
+// ML_MODEL 분석 방법용 모델 오프라인 학습 도구
+// 학습된 모델은 lotto_ml_models 테이블에 새 버전으로 저장되며,
+// 활성화된 경우 실행 중인 서버가 다음 추천 요청 시 자동으로 로드한다.
+func main() {
+	cfgPath := flag.String("config", "config/config.json", "설정 파일 경로")
+	epochs := flag.Int("epochs", 0, "학습 반복 횟수 (0이면 기본값)")
+	lr := flag.Float64("lr", 0, "학습률 (0이면 기본값)")
+	l2 := flag.Float64("l2", 0, "L2 정규화 계수 (0이면 기본값)")
+	holdout := flag.Float64("holdout", 0, "검증용 최근 회차 비율 (0이면 기본값)")
+	seed := flag.Int64("seed", 0, "난수 시드 (0이면 현재 시각)")
+	activate := flag.Bool("activate", true, "학습 후 바로 활성화 여부")
+	flag.Parse()
+
+	log.SetOutput(os.Stdout)
+	log.SetFlags(log.Ltime | log.Lmicroseconds)
+
+	cfgMgr, err := config.NewManager(*cfgPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	if err := cfgMgr.EnsureLogDir(); err != nil {
+		log.Fatalf("failed to create log dir: %v", err)
+	}
+
+	lg, err := logger.New(cfgMgr.Config().Logging.Dir, cfgMgr.Config().Logging.Level)
+	if err != nil {
+		log.Fatalf("failed to init logger: %v", err)
+	}
+	defer lg.Close()
+
+	// Docker 환경변수 오버라이드 (설정 파일보다 우선)
+	dbCfg := cfgMgr.Config().Database
+	if host := os.Getenv("DB_HOST"); host != "" {
+		dbCfg.Host = host
+	}
+	if port := os.Getenv("DB_PORT"); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			dbCfg.Port = p
+		}
+	}
+	if user := os.Getenv("DB_USER"); user != "" {
+		dbCfg.User = user
+	}
+	if pass := os.Getenv("DB_PASSWORD"); pass != "" {
+		dbCfg.Password = pass
+	}
+
+	db, err := database.New(database.Config{
+		Host:     dbCfg.Host,
+		Port:     dbCfg.Port,
+		User:     dbCfg.User,
+		Password: dbCfg.Password,
+		DBName:   dbCfg.DBName,
+		SSLMode:  dbCfg.SSLMode,
+	})
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	repo := lotto.NewRepository(db)
+	analyzer := lotto.NewAnalyzer(repo, lg)
+	svc := lotto.NewService(repo, lotto.NewClient(), analyzer, lg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	model, err := svc.TrainMLModel(ctx, lotto.MLTrainConfig{
+		Epochs:       *epochs,
+		LearningRate: *lr,
+		L2:           *l2,
+		HoldoutRatio: *holdout,
+		Seed:         *seed,
+	}, *activate)
+	if err != nil {
+		log.Fatalf("❌ 학습 실패: %v", err)
+	}
+
+	log.Printf("✅ 모델 v%d 학습 완료 (소요시간: %v)", model.Version, time.Since(start))
+	log.Printf("   학습 샘플: %d, 검증 샘플: %d, 반복: %d",
+		model.Metrics.SampleCount, model.Metrics.HoldoutCount, model.Metrics.Epochs)
+	log.Printf("   검증 log loss: %.5f (기준: %.5f)", model.Metrics.LogLoss, model.Metrics.BaselineLogLoss)
+	log.Printf("   학습 마지막 회차: %d, 활성화: %v", model.TrainedDrawNo, *activate)
+	for i, name := range model.FeatureNames {
+		log.Printf("   %-14s %+.5f", name, model.Weights[i])
+	}
+}
@@ -2,7 +2,10 @@ package auth
 
 import (
 	"fmt"
+	"mime"
 	"net/smtp"
+	"sort"
+	"strings"
 )
 
 type SMTPConfig struct {
@@ -59,12 +62,34 @@ LottoSmash 팀
 	return s.sendEmail(email, subject, body)
 }
 
-func (s *SMTPEmailSender) sendEmail(to, subject, body string) error {
-	auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+// SendHTMLEmail HTML 본문 이메일 발송 (알림 메일 등). headers는 List-Unsubscribe 같은 추가 헤더.
+func (s *SMTPEmailSender) SendHTMLEmail(to, subject, htmlBody string, headers map[string]string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\nTo: %s\r\nSubject: %s\r\n", s.config.From, to, mime.BEncoding.Encode("UTF-8", subject))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n")
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, headers[k])
+	}
+	b.WriteString("\r\n")
+	b.WriteString(htmlBody)
+
+	return s.send(to, []byte(b.String()))
+}
 
+func (s *SMTPEmailSender) sendEmail(to, subject, body string) error {
 	msg := []byte(fmt.Sprintf("To: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
 		to, subject, body))
+	return s.send(to, msg)
+}
 
+func (s *SMTPEmailSender) send(to string, msg []byte) error {
+	auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
 	return smtp.SendMail(addr, auth, s.config.From, []string{to}, msg)
 }
@@ -85,3 +110,8 @@ func (n *NoopEmailSender) SendPasswordResetEmail(email, code string) error {
 	fmt.Printf("[DEV] Password reset email to %s: code=%s\n", email, code)
 	return nil
 }
+
+func (n *NoopEmailSender) SendHTMLEmail(email, subject, htmlBody string, headers map[string]string) error {
+	fmt.Printf("[DEV] Notification email to %s: subject=%q (%d bytes)\n", email, subject, len(htmlBody))
+	return nil
+}
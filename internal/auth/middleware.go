@@ -18,13 +18,22 @@ const (
 )
 
 type Middleware struct {
-	jwt *JWTManager
+	jwt      *JWTManager
+	adminIDs map[int64]bool
 }
 
 func NewMiddleware(jwt *JWTManager) *Middleware {
 	return &Middleware{jwt: jwt}
 }
 
+// SetAdminUserIDs 관리자 API 접근을 허용할 사용자 설정 (설정하지 않으면 RequireAdmin은 모두 거부)
+func (m *Middleware) SetAdminUserIDs(ids []int64) {
+	m.adminIDs = make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		m.adminIDs[id] = true
+	}
+}
+
 // RequireAuth 인증 필수 미들웨어
 func (m *Middleware) RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -39,6 +48,26 @@ func (m *Middleware) RequireAuth(next http.Handler) http.Handler {
 	})
 }
 
+// RequireAuthStream 스트림(SSE) 연결용 인증 미들웨어
+// 브라우저 EventSource는 헤더를 설정할 수 없으므로 access_token 쿼리 파라미터도 허용한다.
+func (m *Middleware) RequireAuthStream(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := m.extractClaims(r)
+		if err != nil {
+			if token := r.URL.Query().Get("access_token"); token != "" {
+				claims, err = m.jwt.ValidateAccessToken(token)
+			}
+		}
+		if err != nil {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+
+		ctx := m.setClaimsToContext(r.Context(), claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // RequireMember 정회원 이상 전용 미들웨어
 func (m *Middleware) RequireMember(next http.Handler) http.Handler {
 	return m.RequireTierLevel(constants.MemberLevel, next)
@@ -73,6 +102,25 @@ func (m *Middleware) RequireTierLevel(requiredLevel TierLevel, next http.Handler
 	})
 }
 
+// RequireAdmin 관리자 전용 미들웨어 (SetAdminUserIDs로 허용한 사용자만)
+func (m *Middleware) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := m.extractClaims(r)
+		if err != nil {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+
+		if !m.adminIDs[claims.UserID] {
+			http.Error(w, `{"error":"admin only"}`, http.StatusForbidden)
+			return
+		}
+
+		ctx := m.setClaimsToContext(r.Context(), claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // OptionalAuth 인증 선택적 미들웨어 (비회원도 접근 가능하지만 토큰이 있으면 파싱)
 func (m *Middleware) OptionalAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
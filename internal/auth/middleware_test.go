@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	jwtManager := NewJWTManager(JWTConfig{SecretKey: "test-secret"})
+	m := NewMiddleware(jwtManager)
+	handler := m.RequireAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(userID int64) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/lotto/ml/train", nil)
+		if userID > 0 {
+			token, err := jwtManager.GenerateAccessToken(&User{ID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// 관리자 미설정: 인증된 사용자도 모두 거부
+	if code := serve(1); code != http.StatusForbidden {
+		t.Errorf("no admins configured: status = %d, want 403", code)
+	}
+
+	m.SetAdminUserIDs([]int64{1})
+	tests := []struct {
+		userID int64
+		want   int
+	}{
+		{0, http.StatusUnauthorized},
+		{1, http.StatusNoContent},
+		{2, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if code := serve(tt.userID); code != tt.want {
+			t.Errorf("user %d: status = %d, want %d", tt.userID, code, tt.want)
+		}
+	}
+}
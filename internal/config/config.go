@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/example/LottoSmash/internal/constants"
 )
 
 type ServerConfig struct {
@@ -70,14 +72,69 @@ type SMTPConfig struct {
 	From     string `json:"from"`
 }
 
+type EmailNotificationConfig struct {
+	Enabled           bool   `json:"enabled"`           // 이메일 알림 채널 (SMTP가 꺼져 있으면 발송하지 않고 로그만 남김)
+	BaseURL           string `json:"baseUrl"`           // 메일 속 앱/수신 거부 링크에 쓰는 서비스 주소
+	UnsubscribeSecret string `json:"unsubscribeSecret"` // 수신 거부 링크 서명 키 (비우면 JWT 키 사용)
+}
+
+type StreamConfig struct {
+	Enabled   bool `json:"enabled"`   // 실시간 알림 스트림 (SSE, /api/notifications/stream)
+	Backplane bool `json:"backplane"` // 여러 서버 인스턴스 간 이벤트 전달 (Postgres LISTEN/NOTIFY)
+}
+
+// AdminConfig 관리자 API(/api/admin/...) 접근 허용 사용자
+type AdminConfig struct {
+	UserIDs []int64 `json:"userIds"` // 비어 있으면 관리자 API는 모두 거부
+}
+
 type CrawlerConfig struct {
 	BatchSize    int `json:"batchSize"`    // 배치당 크롤링할 회차 수
 	BatchDelayMs int `json:"batchDelayMs"` // 배치 간 딜레이 (밀리초)
 }
 
+type AnalysisConfig struct {
+	DecayHalfLives []int `json:"decayHalfLives"` // 시간 감쇠 빈도를 사전 계산할 반감기 목록 (회차 수)
+}
+
 type FCMConfig struct {
 	Enabled         bool   `json:"enabled"`
-	CredentialsFile string `json:"credentialsFile"`
+	CredentialsFile string `json:"credentialsFile"` // 서비스 계정 키 JSON 경로
+	BaseURL         string `json:"baseUrl"`         // FCM API 주소 (비우면 https://fcm.googleapis.com, 테스트 시 스텁 서버)
+}
+
+type APNsConfig struct {
+	Enabled    bool   `json:"enabled"`
+	KeyFile    string `json:"keyFile"`    // 토큰 인증 키(.p8) 경로
+	KeyID      string `json:"keyId"`      // 키 ID (Apple Developer 콘솔)
+	TeamID     string `json:"teamId"`     // 팀 ID
+	Topic      string `json:"topic"`      // 앱 번들 ID
+	Production bool   `json:"production"` // false면 sandbox 서버로 발송
+	Endpoint   string `json:"endpoint"`   // APNs 주소 직접 지정 (테스트 시 로컬 HTTP/2 스텁, 비우면 production 값에 따름)
+}
+
+type WebPushConfig struct {
+	Enabled         bool   `json:"enabled"`
+	VAPIDPublicKey  string `json:"vapidPublicKey"`  // base64url 공개키 (make gen-vapid로 생성)
+	VAPIDPrivateKey string `json:"vapidPrivateKey"` // base64url 개인키
+	Subject         string `json:"subject"`         // 발신자 연락처 (mailto: 또는 https:)
+}
+
+type PushQueueConfig struct {
+	IntervalSec  int `json:"intervalSec"`  // 발송 큐 확인 주기 (초)
+	BatchSize    int `json:"batchSize"`    // 한 번에 동시 발송할 작업 수
+	MaxAttempts  int `json:"maxAttempts"`  // 이 횟수만큼 실패하면 dead 처리
+	RetryBaseSec int `json:"retryBaseSec"` // 첫 재시도 대기 (초, 이후 2배씩 증가)
+	RetryMaxSec  int `json:"retryMaxSec"`  // 재시도 대기 상한 (초)
+
+	TokenExpiryDays int `json:"tokenExpiryDays"` // 이 기간 동안 다시 등록되지 않은 디바이스 토큰 비활성화 (일)
+}
+
+type DrawNotificationConfig struct {
+	BroadcastEnabled      bool `json:"broadcastEnabled"`      // 새 회차 당첨번호 발표 알림
+	ReminderEnabled       bool `json:"reminderEnabled"`       // 토요일 판매 마감 전 리마인더
+	ReminderMinutesBefore int  `json:"reminderMinutesBefore"` // 판매 마감 몇 분 전에 리마인더를 보낼지
+	BatchSize             int  `json:"batchSize"`             // 워커 풀 작업 하나가 처리할 사용자 수
 }
 
 type Config struct {
@@ -85,12 +142,21 @@ type Config struct {
 	Logging      LoggingConfig      `json:"logging"`
 	Concurrency  ConcurrencyConfig  `json:"concurrency"`
 	Crawler      CrawlerConfig      `json:"crawler"`
+	Analysis     AnalysisConfig     `json:"analysis"`
 	Scheduler    SchedulerConfig    `json:"scheduler"`
 	ConfigReload ConfigReloadConfig `json:"configReload"`
 	Database     DatabaseConfig     `json:"database"`
 	JWT          JWTConfig          `json:"jwt"`
 	SMTP         SMTPConfig         `json:"smtp"`
 	FCM          FCMConfig          `json:"fcm"`
+	APNs         APNsConfig         `json:"apns"`
+	WebPush      WebPushConfig      `json:"webPush"`
+	PushQueue    PushQueueConfig    `json:"pushQueue"`
+
+	DrawNotifications  DrawNotificationConfig  `json:"drawNotifications"`
+	EmailNotifications EmailNotificationConfig `json:"emailNotifications"`
+	Stream             StreamConfig            `json:"stream"`
+	Admin              AdminConfig             `json:"admin"`
 }
 
 type HotConfig struct {
@@ -204,12 +270,47 @@ func validate(c *Config) error {
 	if c.Crawler.BatchDelayMs <= 0 {
 		c.Crawler.BatchDelayMs = 2000
 	}
+	if len(c.Analysis.DecayHalfLives) == 0 {
+		c.Analysis.DecayHalfLives = []int{26, 52, 104}
+	}
+	for _, hl := range c.Analysis.DecayHalfLives {
+		if hl < 1 || hl > constants.MaxDecayHalfLife {
+			return fmt.Errorf("analysis.decayHalfLives must be between 1 and %d", constants.MaxDecayHalfLife)
+		}
+	}
 	if c.Scheduler.Timezone == "" {
 		c.Scheduler.Timezone = "Asia/Seoul"
 	}
 	if c.ConfigReload.IntervalMinutes <= 0 {
 		c.ConfigReload.IntervalMinutes = 10
 	}
+	if c.PushQueue.IntervalSec <= 0 {
+		c.PushQueue.IntervalSec = 5
+	}
+	if c.PushQueue.BatchSize <= 0 {
+		c.PushQueue.BatchSize = 50
+	}
+	if c.PushQueue.MaxAttempts <= 0 {
+		c.PushQueue.MaxAttempts = 6
+	}
+	if c.PushQueue.RetryBaseSec <= 0 {
+		c.PushQueue.RetryBaseSec = 30
+	}
+	if c.PushQueue.RetryMaxSec <= 0 {
+		c.PushQueue.RetryMaxSec = 3600
+	}
+	if c.PushQueue.TokenExpiryDays <= 0 {
+		c.PushQueue.TokenExpiryDays = 60
+	}
+	if c.DrawNotifications.ReminderMinutesBefore <= 0 {
+		c.DrawNotifications.ReminderMinutesBefore = 120
+	}
+	if c.DrawNotifications.BatchSize <= 0 {
+		c.DrawNotifications.BatchSize = 500
+	}
+	if c.EmailNotifications.UnsubscribeSecret == "" {
+		c.EmailNotifications.UnsubscribeSecret = c.JWT.SecretKey
+	}
 	return nil
 }
 
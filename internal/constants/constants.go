@@ -18,6 +18,11 @@ const (
 	FirstDrawDay   = 7
 )
 
+// 시간 감쇠 빈도 관련 상수
+const (
+	MaxDecayHalfLife = 1000 // 최대 반감기 (회차 수)
+)
+
 // 동행복권 URL 및 클라이언트 설정
 const (
 	DHLotteryBaseURL   = "https://www.dhlottery.co.kr/common.do?method=getLottoNumber&drwNo=%d"
@@ -16,17 +16,19 @@ type Config struct {
 	SSLMode  string
 }
 
-func New(cfg Config) (*sql.DB, error) {
+// DSN lib/pq 접속 문자열 (LISTEN 전용 연결 등에도 사용)
+func (cfg Config) DSN() string {
 	if cfg.SSLMode == "" {
 		cfg.SSLMode = "disable"
 	}
-
-	dsn := fmt.Sprintf(
+	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
+}
 
-	db, err := sql.Open("postgres", dsn)
+func New(cfg Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
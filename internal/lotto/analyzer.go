@@ -3,7 +3,9 @@ package lotto
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
+	"sync"
 
 	"github.com/example/LottoSmash/internal/constants"
 	"github.com/example/LottoSmash/internal/logger"
@@ -12,6 +14,19 @@ import (
 type Analyzer struct {
 	repo *Repository
 	log  *logger.Logger
+
+	// 시간 감쇠 빈도를 사전 계산할 반감기 목록 (비어있으면 기본 반감기만 계산)
+	DecayHalfLives []int
+
+	decayMu    sync.Mutex
+	decayCache adhocDecayCache // 사전 계산 대상이 아닌 반감기의 즉석 계산 결과
+}
+
+// adhocDecayCache 최신 회차 기준 즉석 계산 감쇠 통계 (새 회차가 들어오면 비움)
+type adhocDecayCache struct {
+	drawNo int
+	draws  []*LottoDraw
+	stats  map[int][]DecayStat
 }
 
 func NewAnalyzer(repo *Repository, log *logger.Logger) *Analyzer {
@@ -720,6 +735,12 @@ func (a *Analyzer) RunFullAnalysis(ctx context.Context) error {
 		return err
 	}
 
+	// 시간 감쇠 빈도 통계 계산 및 저장 (점진적 업데이트)
+	if err := a.CalculateDecayStats(ctx); err != nil {
+		a.log.Errorf("RunFullAnalysis: failed to calculate decay stats: %v", err)
+		return err
+	}
+
 	a.log.Infof("RunFullAnalysis: completed successfully")
 	return nil
 }
@@ -2500,3 +2521,202 @@ func (a *Analyzer) FixZeroHighLowProbability(ctx context.Context) (int, error) {
 	a.log.Infof("FixZeroHighLowProbability: updated %d rows successfully", len(zeroStats))
 	return len(zeroStats), nil
 }
+
+// ========================================
+// 시간 감쇠 빈도 (DECAY_FREQUENCY)
+// ========================================
+
+// decayFactor 반감기(회차 수)에 해당하는 회차당 감쇠 계수
+func decayFactor(halfLife int) float64 {
+	return math.Pow(0.5, 1/float64(halfLife))
+}
+
+// nextDecayStats 이전 회차 감쇠 통계에 새 회차 당첨번호를 반영
+// prev가 비어있으면 초기 상태(가중치 0)에서 시작한다.
+// 중간 회차가 비어있으면 회차 간격만큼 감쇠시킨다.
+func nextDecayStats(prev map[int]DecayStat, draw *LottoDraw, halfLife int) []DecayStat {
+	appeared := make(map[int]bool, NumbersPerDraw)
+	for _, n := range draw.Numbers() {
+		appeared[n] = true
+	}
+
+	factor := decayFactor(halfLife)
+	stats := make([]DecayStat, 0, TotalNumbers)
+	for num := 1; num <= TotalNumbers; num++ {
+		p, ok := prev[num]
+
+		decay := 1.0
+		if ok && p.DrawNo > 0 {
+			decay = math.Pow(factor, float64(draw.DrawNo-p.DrawNo))
+		}
+
+		weightedCount := p.WeightedCount * decay
+		if appeared[num] {
+			weightedCount++
+		}
+		weightSum := p.WeightSum*decay + 1
+
+		stats = append(stats, DecayStat{
+			HalfLife:      halfLife,
+			DrawNo:        draw.DrawNo,
+			Number:        num,
+			WeightedCount: weightedCount,
+			WeightSum:     weightSum,
+			Score:         weightedCount / (weightSum * NumbersPerDraw),
+			Appeared:      appeared[num],
+		})
+	}
+	return stats
+}
+
+// computeDecayStats 전체 회차(오름차순)로 마지막 회차의 감쇠 통계 계산
+func computeDecayStats(draws []*LottoDraw, halfLife int) []DecayStat {
+	prev := make(map[int]DecayStat, TotalNumbers)
+	var stats []DecayStat
+	for _, draw := range draws {
+		stats = nextDecayStats(prev, draw, halfLife)
+		for _, st := range stats {
+			prev[st.Number] = st
+		}
+	}
+	return stats
+}
+
+// decayHalfLives 사전 계산 대상 반감기 목록
+func (a *Analyzer) decayHalfLives() []int {
+	if len(a.DecayHalfLives) == 0 {
+		return []int{DefaultDecayHalfLife}
+	}
+	return a.DecayHalfLives
+}
+
+// isPrecomputedHalfLife 사전 계산 대상 반감기 여부
+func (a *Analyzer) isPrecomputedHalfLife(halfLife int) bool {
+	for _, hl := range a.decayHalfLives() {
+		if hl == halfLife {
+			return true
+		}
+	}
+	return false
+}
+
+// CalculateDecayStats 시간 감쇠 빈도 통계 계산 (점진적 업데이트)
+// 설정된 반감기마다 마지막 계산 회차 이후의 회차만 반영한다.
+func (a *Analyzer) CalculateDecayStats(ctx context.Context) error {
+	a.log.Infof("CalculateDecayStats: starting")
+
+	latestDrawNo, err := a.repo.GetLatestDrawNo(ctx)
+	if err != nil {
+		a.log.Errorf("CalculateDecayStats: failed to get latest draw no: %v", err)
+		return err
+	}
+	if latestDrawNo == 0 {
+		a.log.Infof("CalculateDecayStats: no draws found, skipping")
+		return nil
+	}
+
+	var draws []*LottoDraw
+	for _, halfLife := range a.decayHalfLives() {
+		lastCalcDrawNo, err := a.repo.GetLatestDecayDrawNo(ctx, halfLife)
+		if err != nil {
+			a.log.Errorf("CalculateDecayStats: failed to get latest decay draw no (half_life=%d): %v", halfLife, err)
+			return err
+		}
+
+		if lastCalcDrawNo >= latestDrawNo {
+			a.log.Infof("CalculateDecayStats: half_life=%d already up to date (draw %d)", halfLife, lastCalcDrawNo)
+			continue
+		}
+
+		// 회차 데이터는 반감기 간 공유
+		if draws == nil {
+			draws, err = a.repo.GetAllDraws(ctx)
+			if err != nil {
+				a.log.Errorf("CalculateDecayStats: failed to get all draws: %v", err)
+				return err
+			}
+		}
+
+		// 이전 회차 통계 (없으면 1회차부터 전체 계산)
+		prev := make(map[int]DecayStat, TotalNumbers)
+		if lastCalcDrawNo > 0 {
+			prevStats, err := a.repo.GetDecayStatsByDrawNo(ctx, halfLife, lastCalcDrawNo)
+			if err != nil {
+				a.log.Errorf("CalculateDecayStats: failed to get prev stats: %v", err)
+				return err
+			}
+			for _, st := range prevStats {
+				prev[st.Number] = st
+			}
+		}
+
+		a.log.Infof("CalculateDecayStats: half_life=%d updating from draw %d to %d", halfLife, lastCalcDrawNo+1, latestDrawNo)
+
+		for _, draw := range draws {
+			if draw.DrawNo <= lastCalcDrawNo {
+				continue
+			}
+
+			newStats := nextDecayStats(prev, draw, halfLife)
+			if err := a.repo.UpsertDecayStats(ctx, newStats); err != nil {
+				a.log.Errorf("CalculateDecayStats: failed to upsert stats for draw %d: %v", draw.DrawNo, err)
+				return err
+			}
+			for _, st := range newStats {
+				prev[st.Number] = st
+			}
+		}
+	}
+
+	a.log.Infof("CalculateDecayStats: completed successfully")
+	return nil
+}
+
+// GetDecayStats 반감기별 최신 회차 감쇠 빈도 조회 (번호 오름차순)
+// 사전 계산된 반감기면 DB에서 조회하고, 아니면 전체 회차로 즉석 계산한다 (회차별 캐시).
+func (a *Analyzer) GetDecayStats(ctx context.Context, halfLife int) ([]DecayStat, error) {
+	if halfLife <= 0 {
+		halfLife = DefaultDecayHalfLife
+	}
+
+	if a.isPrecomputedHalfLife(halfLife) {
+		stats, err := a.repo.GetLatestDecayStats(ctx, halfLife)
+		if err != nil {
+			return nil, err
+		}
+		if len(stats) > 0 {
+			return stats, nil
+		}
+	}
+
+	return a.adhocDecayStats(ctx, halfLife)
+}
+
+// adhocDecayStats 사전 계산 대상이 아닌 반감기의 감쇠 통계
+// 전체 회차 조회는 새 회차마다 한 번만 하고, 반감기별 결과도 같은 회차 동안 재사용한다.
+func (a *Analyzer) adhocDecayStats(ctx context.Context, halfLife int) ([]DecayStat, error) {
+	latestDrawNo, err := a.repo.GetLatestDrawNo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.decayMu.Lock()
+	defer a.decayMu.Unlock()
+
+	c := &a.decayCache
+	if c.drawNo != latestDrawNo || c.draws == nil {
+		draws, err := a.repo.GetAllDraws(ctx)
+		if err != nil {
+			return nil, err
+		}
+		*c = adhocDecayCache{drawNo: latestDrawNo, draws: draws, stats: make(map[int][]DecayStat)}
+	}
+
+	stats, ok := c.stats[halfLife]
+	if !ok {
+		stats = computeDecayStats(c.draws, halfLife)
+		c.stats[halfLife] = stats
+	}
+	// 호출자가 정렬해도 캐시가 바뀌지 않도록 복사본 반환
+	return append([]DecayStat(nil), stats...), nil
+}
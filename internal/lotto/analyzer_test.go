@@ -0,0 +1,113 @@
+package lotto
+
+import (
+	"math"
+	"testing"
+)
+
+// 테스트용 회차 생성
+func makeTestDraw(drawNo int, nums ...int) *LottoDraw {
+	return &LottoDraw{
+		DrawNo: drawNo,
+		Num1:   nums[0], Num2: nums[1], Num3: nums[2],
+		Num4: nums[3], Num5: nums[4], Num6: nums[5],
+	}
+}
+
+func TestDecayFactor(t *testing.T) {
+	for _, hl := range []int{1, 26, 52, 104} {
+		f := decayFactor(hl)
+		// 반감기만큼 지나면 가중치가 절반이 되어야 함
+		if got := math.Pow(f, float64(hl)); math.Abs(got-0.5) > 1e-9 {
+			t.Errorf("half_life %d: factor^half_life = %.6f, want 0.5", hl, got)
+		}
+	}
+}
+
+func TestNextDecayStats(t *testing.T) {
+	draws := []*LottoDraw{
+		makeTestDraw(1, 1, 2, 3, 4, 5, 6),
+		makeTestDraw(2, 1, 7, 8, 9, 10, 11),
+	}
+
+	stats := computeDecayStats(draws, 1)
+	if len(stats) != TotalNumbers {
+		t.Fatalf("expected %d stats, got %d", TotalNumbers, len(stats))
+	}
+
+	// half_life=1: 1회차 가중치 0.5, 2회차 가중치 1
+	byNum := make(map[int]DecayStat)
+	sum := 0.0
+	for _, s := range stats {
+		byNum[s.Number] = s
+		sum += s.Score
+	}
+
+	if math.Abs(sum-1.0) > 1e-9 {
+		t.Errorf("scores should sum to 1, got %.6f", sum)
+	}
+	if got := byNum[1].WeightedCount; math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("number 1 weighted count = %.4f, want 1.5", got)
+	}
+	if got := byNum[2].WeightedCount; math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("number 2 weighted count = %.4f, want 0.5", got)
+	}
+	if got := byNum[7].WeightedCount; math.Abs(got-1.0) > 1e-9 {
+		t.Errorf("number 7 weighted count = %.4f, want 1.0", got)
+	}
+	if !byNum[7].Appeared || byNum[2].Appeared {
+		t.Errorf("appeared flags incorrect: 7=%v, 2=%v", byNum[7].Appeared, byNum[2].Appeared)
+	}
+
+	// 최근 출현 번호(7)가 과거 출현 번호(2)보다 높은 점수
+	if byNum[7].Score <= byNum[2].Score {
+		t.Errorf("recent number score %.4f should exceed older %.4f", byNum[7].Score, byNum[2].Score)
+	}
+}
+
+func TestNextDecayStats_IncrementalMatchesFull(t *testing.T) {
+	draws := []*LottoDraw{
+		makeTestDraw(1, 1, 2, 3, 4, 5, 6),
+		makeTestDraw(2, 10, 20, 30, 40, 41, 42),
+		makeTestDraw(4, 1, 10, 15, 25, 35, 45), // 3회차 누락
+		makeTestDraw(5, 2, 4, 6, 8, 10, 12),
+	}
+	const halfLife = 3
+
+	full := computeDecayStats(draws, halfLife)
+
+	// 2회차까지 계산 후 이어서 계산
+	prev := make(map[int]DecayStat)
+	for _, s := range computeDecayStats(draws[:2], halfLife) {
+		prev[s.Number] = s
+	}
+	var incremental []DecayStat
+	for _, d := range draws[2:] {
+		incremental = nextDecayStats(prev, d, halfLife)
+		for _, s := range incremental {
+			prev[s.Number] = s
+		}
+	}
+
+	for i := range full {
+		if math.Abs(full[i].Score-incremental[i].Score) > 1e-12 {
+			t.Errorf("number %d: full %.8f != incremental %.8f", full[i].Number, full[i].Score, incremental[i].Score)
+		}
+	}
+
+	// 누락 회차도 간격만큼 감쇠: 가중치 합 = 1 + f + f^3 + f^4
+	f := decayFactor(halfLife)
+	want := 1 + f + math.Pow(f, 3) + math.Pow(f, 4)
+	if got := full[0].WeightSum; math.Abs(got-want) > 1e-9 {
+		t.Errorf("weight sum = %.6f, want %.6f", got, want)
+	}
+}
+
+func TestDecayHalfLifeOrDefault(t *testing.T) {
+	if got := decayHalfLifeOrDefault(0); got != DefaultDecayHalfLife {
+		t.Errorf("got %d, want default %d", got, DefaultDecayHalfLife)
+	}
+	if got := decayHalfLifeOrDefault(10); got != 10 {
+		t.Errorf("got %d, want 10", got)
+	}
+}
@@ -0,0 +1,551 @@
+package lotto
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/example/LottoSmash/internal/logger"
+)
+
+const (
+	MaxBulkSets        = 500            // 대량 작업 1건당 최대 세트 수
+	MaxBulkJobsPerUser = 2              // 사용자당 동시(대기+실행) 작업 수
+	MinWheelNumbers    = 7              // 휠 번호 최소 개수
+	MaxWheelNumbers    = 11             // 휠 번호 최대 개수 (C(11,6) = 462세트)
+	BulkJobRetention   = 24 * time.Hour // 종료된 작업 결과 보관 기간
+)
+
+// 대량 작업 종류
+const (
+	BulkJobTypeRecommend = "RECOMMEND" // 추천 세트 대량 생성 (TOP / OPTIMIZE)
+	BulkJobTypeWheel     = "WHEEL"     // 선택 번호의 모든 6개 조합 (풀 휠)
+)
+
+// 대량 작업 상태
+const (
+	BulkJobQueued    = "QUEUED"
+	BulkJobRunning   = "RUNNING"
+	BulkJobCompleted = "COMPLETED"
+	BulkJobFailed    = "FAILED"
+	BulkJobCancelled = "CANCELLED"
+)
+
+var (
+	ErrBulkJobNotFound      = errors.New("bulk job not found")
+	ErrBulkJobLimitExceeded = errors.New("bulk job limit exceeded")
+	ErrBulkJobQueueFull     = errors.New("bulk job queue is full")
+	ErrBulkJobNotReady      = errors.New("bulk job result not ready")
+	ErrBulkJobsUnavailable  = errors.New("bulk jobs are not available")
+)
+
+// BulkJobRequest 대량 작업 요청
+type BulkJobRequest struct {
+	Type         string           `json:"type"`                    // RECOMMEND(기본) / WHEEL
+	Recommend    RecommendRequest `json:"recommend"`               // RECOMMEND: count 최대 500
+	WheelNumbers []int            `json:"wheel_numbers,omitempty"` // WHEEL: 7~11개 번호
+}
+
+// JobNotifier 대량 작업 종료 알림 (notification.Service가 구현)
+type JobNotifier interface {
+	NotifyJobFinished(userID int64, job BulkJob)
+}
+
+// BulkJob 대량 작업 상태
+type BulkJob struct {
+	ID         string     `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Type       string     `json:"type"`
+	Status     string     `json:"status"`    // QUEUED / RUNNING / COMPLETED / FAILED / CANCELLED
+	Total      int        `json:"total"`     // 생성할 세트 수
+	Completed  int        `json:"completed"` // 생성 완료 세트 수
+	Progress   float64    `json:"progress"`  // 0~1
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// BulkJobResult 대량 작업 결과
+// 대량 세트는 사용자 추천 기록(lotto_recommendations)에 저장하지 않는다.
+type BulkJobResult struct {
+	JobID           string           `json:"job_id"`
+	Type            string           `json:"type"`
+	TargetDrawNo    int              `json:"target_draw_no"`
+	Recommendations []Recommendation `json:"recommendations"`
+	GeneratedAt     time.Time        `json:"generated_at"`
+}
+
+// BulkJobListResponse 대량 작업 목록 응답
+type BulkJobListResponse struct {
+	Jobs       []BulkJob `json:"jobs"`
+	TotalCount int       `json:"total_count"`
+}
+
+// validateBulkJobRequest 작업 종류별 유효성 검사 (기본값 설정)
+func validateBulkJobRequest(req *BulkJobRequest) error {
+	switch req.Type {
+	case "":
+		req.Type = BulkJobTypeRecommend
+		fallthrough
+	case BulkJobTypeRecommend:
+		if req.Recommend.Count <= 0 {
+			req.Recommend.Count = 1
+		}
+		if req.Recommend.Count > MaxBulkSets {
+			return fmt.Errorf("count must be between 1 and %d", MaxBulkSets)
+		}
+		return validateRecommendOptions(&req.Recommend)
+	case BulkJobTypeWheel:
+		if len(req.WheelNumbers) < MinWheelNumbers || len(req.WheelNumbers) > MaxWheelNumbers {
+			return fmt.Errorf("wheel_numbers must contain %d to %d numbers", MinWheelNumbers, MaxWheelNumbers)
+		}
+		seen := make(map[int]bool, len(req.WheelNumbers))
+		for _, n := range req.WheelNumbers {
+			if n < 1 || n > TotalNumbers {
+				return fmt.Errorf("wheel number %d out of range 1-%d", n, TotalNumbers)
+			}
+			if seen[n] {
+				return fmt.Errorf("duplicate wheel number %d", n)
+			}
+			seen[n] = true
+		}
+		return nil
+	default:
+		return errors.New("type must be RECOMMEND or WHEEL")
+	}
+}
+
+// bulkJobTotal 작업이 생성할 세트 수
+func bulkJobTotal(req BulkJobRequest) int {
+	if req.Type == BulkJobTypeWheel {
+		return binomial(len(req.WheelNumbers), NumbersPerDraw)
+	}
+	return req.Recommend.Count
+}
+
+// FullWheel 번호 목록의 모든 6개 조합 (사전순)
+func FullWheel(numbers []int) [][]int {
+	pool := append([]int(nil), numbers...)
+	sort.Ints(pool)
+	if len(pool) < NumbersPerDraw {
+		return nil
+	}
+
+	combos := make([][]int, 0, binomial(len(pool), NumbersPerDraw))
+	idx := []int{0, 1, 2, 3, 4, 5}
+	for {
+		combo := make([]int, NumbersPerDraw)
+		for i, p := range idx {
+			combo[i] = pool[p]
+		}
+		combos = append(combos, combo)
+
+		i := NumbersPerDraw - 1
+		for i >= 0 && idx[i] == len(pool)-NumbersPerDraw+i {
+			i--
+		}
+		if i < 0 {
+			return combos
+		}
+		idx[i]++
+		for j := i + 1; j < NumbersPerDraw; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+// binomial 조합 수 C(n, k)
+func binomial(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+	}
+	return result
+}
+
+// ========================================
+// 작업 저장소
+// ========================================
+
+const (
+	bulkJobDBTimeout        = 5 * time.Second  // 작업 테이블 쿼리 제한 시간
+	bulkJobProgressInterval = 5 * time.Second  // 진행률 테이블 기록 간격
+	bulkJobStaleAfter       = 15 * time.Minute // 진행 기록이 이보다 오래 없으면 실행 서버 중단으로 보고 실패 처리
+)
+
+// bulkJobStore 대량 작업 상태와 결과 보관
+// 작업은 제출한 서버의 워커 풀에서 실행되며, 실행 중인 작업은 메모리에서 관리한다.
+// repo가 설정되면 상태와 결과를 lotto_bulk_jobs 테이블에도 기록하여 재시작 후나 다른 서버에서도
+// 조회/취소할 수 있고, 사용자 동시 작업 수 제한도 테이블 기준으로 적용한다.
+// 종료 후 BulkJobRetention이 지나면 정리된다.
+type bulkJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*bulkJobEntry
+	now  func() time.Time
+	repo *Repository    // nil이면 메모리에만 보관 (단일 서버)
+	log  *logger.Logger // repo 기록 실패 로그
+}
+
+type bulkJobEntry struct {
+	job     BulkJob
+	result  *BulkJobResult
+	cancel  context.CancelFunc
+	savedAt time.Time // 마지막 진행률 테이블 기록 시각
+}
+
+func newBulkJobStore(repo *Repository, log *logger.Logger) *bulkJobStore {
+	return &bulkJobStore{
+		jobs: make(map[string]*bulkJobEntry),
+		now:  time.Now,
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *bulkJobStore) dbContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), bulkJobDBTimeout)
+}
+
+// add 사용자 동시 작업 수 제한 확인 후 대기 상태로 등록
+func (s *bulkJobStore) add(job BulkJob, cancel context.CancelFunc) (BulkJob, error) {
+	job.Status = BulkJobQueued
+	job.CreatedAt = s.now()
+
+	if s.repo != nil {
+		ctx, done := s.dbContext()
+		defer done()
+		if err := s.repo.PruneBulkJobs(ctx, BulkJobRetention, bulkJobStaleAfter); err != nil {
+			s.log.Warnf("failed to prune bulk jobs: %v", err)
+		}
+		if err := s.repo.InsertBulkJob(ctx, job, MaxBulkJobsPerUser); err != nil {
+			if errors.Is(err, ErrBulkJobLimitExceeded) {
+				return BulkJob{}, err
+			}
+			return BulkJob{}, fmt.Errorf("failed to save bulk job: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+
+	if s.repo == nil {
+		active := 0
+		for _, e := range s.jobs {
+			if e.job.UserID == job.UserID && !bulkJobFinished(e.job.Status) {
+				active++
+			}
+		}
+		if active >= MaxBulkJobsPerUser {
+			return BulkJob{}, ErrBulkJobLimitExceeded
+		}
+	}
+
+	s.jobs[job.ID] = &bulkJobEntry{job: job, cancel: cancel, savedAt: job.CreatedAt}
+	return job, nil
+}
+
+// remove 큐에 넣지 못한 작업 삭제
+func (s *bulkJobStore) remove(id string) {
+	s.mu.Lock()
+	delete(s.jobs, id)
+	s.mu.Unlock()
+
+	if s.repo != nil {
+		ctx, done := s.dbContext()
+		defer done()
+		if err := s.repo.DeleteBulkJob(ctx, id); err != nil {
+			s.log.Warnf("failed to delete bulk job %s: %v", id, err)
+		}
+	}
+}
+
+// get 사용자 본인 작업 조회 (이 서버에서 제출한 작업)
+func (s *bulkJobStore) get(userID int64, id string) (*bulkJobEntry, error) {
+	e, ok := s.jobs[id]
+	if !ok || e.job.UserID != userID {
+		return nil, ErrBulkJobNotFound
+	}
+	return e, nil
+}
+
+// snapshot 작업 상태 복사본 (다른 서버에서 제출한 작업은 테이블에서 조회)
+func (s *bulkJobStore) snapshot(userID int64, id string) (BulkJob, error) {
+	s.mu.Lock()
+	e, err := s.get(userID, id)
+	var job BulkJob
+	if err == nil {
+		job = e.job
+	}
+	s.mu.Unlock()
+
+	if err == nil || s.repo == nil {
+		return job, err
+	}
+	ctx, done := s.dbContext()
+	defer done()
+	return s.repo.GetBulkJob(ctx, userID, id)
+}
+
+// list 사용자 작업 목록 (최신순)
+func (s *bulkJobStore) list(userID int64) ([]BulkJob, error) {
+	s.mu.Lock()
+	s.pruneLocked()
+	local := make(map[string]BulkJob)
+	for id, e := range s.jobs {
+		if e.job.UserID == userID {
+			local[id] = e.job
+		}
+	}
+	cutoff := s.now().Add(-BulkJobRetention)
+	s.mu.Unlock()
+
+	jobs := make([]BulkJob, 0, len(local))
+	if s.repo != nil {
+		ctx, done := s.dbContext()
+		defer done()
+		stored, err := s.repo.GetBulkJobsByUserID(ctx, userID, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		// 이 서버에서 실행 중인 작업은 메모리 상태가 최신
+		for _, job := range stored {
+			if _, ok := local[job.ID]; !ok {
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	for _, job := range local {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// result 완료된 작업 결과 조회
+func (s *bulkJobStore) result(userID int64, id string) (*BulkJobResult, error) {
+	s.mu.Lock()
+	e, err := s.get(userID, id)
+	var result *BulkJobResult
+	if err == nil {
+		result = e.result
+	}
+	s.mu.Unlock()
+
+	if err == nil {
+		if result == nil {
+			return nil, ErrBulkJobNotReady
+		}
+		return result, nil
+	}
+	if s.repo == nil {
+		return nil, err
+	}
+	ctx, done := s.dbContext()
+	defer done()
+	return s.repo.GetBulkJobResult(ctx, userID, id)
+}
+
+// cancelJob 대기/실행 중인 작업 취소 요청 (이미 종료된 작업은 그대로 반환)
+// 다른 서버에서 실행 중인 작업은 테이블에 취소 요청을 남기고, 실행 서버가 진행률을 기록할 때 중단한다.
+func (s *bulkJobStore) cancelJob(userID int64, id string) (BulkJob, error) {
+	s.mu.Lock()
+	e, err := s.get(userID, id)
+	if err != nil {
+		s.mu.Unlock()
+		if s.repo == nil {
+			return BulkJob{}, err
+		}
+		ctx, done := s.dbContext()
+		defer done()
+		return s.repo.RequestBulkJobCancel(ctx, userID, id)
+	}
+	if bulkJobFinished(e.job.Status) {
+		job := e.job
+		s.mu.Unlock()
+		return job, nil
+	}
+
+	e.cancel()
+	// 대기 중인 작업은 워커가 꺼내기 전이므로 바로 취소 처리
+	finished := e.job.Status == BulkJobQueued
+	if finished {
+		s.finishLocked(e, nil, context.Canceled)
+	}
+	job := e.job
+	s.mu.Unlock()
+
+	if finished {
+		s.save(job, nil)
+	}
+	return job, nil
+}
+
+// start 실행 시작 기록 (이미 취소된 작업이면 false)
+func (s *bulkJobStore) start(id string) bool {
+	s.mu.Lock()
+	e, ok := s.jobs[id]
+	if !ok || e.job.Status != BulkJobQueued {
+		s.mu.Unlock()
+		return false
+	}
+	now := s.now()
+	e.job.Status = BulkJobRunning
+	e.job.StartedAt = &now
+	e.savedAt = now
+	s.mu.Unlock()
+
+	if s.repo == nil {
+		return true
+	}
+	ctx, done := s.dbContext()
+	defer done()
+	started, err := s.repo.MarkBulkJobStarted(ctx, id, now)
+	if err != nil {
+		// 기록 실패는 실행을 막지 않음 (종료 시 다시 기록)
+		s.log.Warnf("failed to save bulk job %s start: %v", id, err)
+		return true
+	}
+	if !started {
+		// 대기 중 다른 서버에서 취소됨
+		s.finish(id, nil, context.Canceled)
+		return false
+	}
+	return true
+}
+
+// progress 진행률 갱신
+// 테이블에는 bulkJobProgressInterval마다 기록하며, 그 사이 다른 서버에서 취소 요청이 있었으면 작업을 중단한다.
+func (s *bulkJobStore) progress(id string, done int) {
+	s.mu.Lock()
+	e, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	e.job.Completed = done
+	if e.job.Total > 0 {
+		e.job.Progress = float64(done) / float64(e.job.Total)
+	}
+	persist := s.repo != nil && s.now().Sub(e.savedAt) >= bulkJobProgressInterval
+	if persist {
+		e.savedAt = s.now()
+	}
+	cancel := e.cancel
+	s.mu.Unlock()
+
+	if !persist {
+		return
+	}
+	ctx, dbDone := s.dbContext()
+	defer dbDone()
+	stop, err := s.repo.UpdateBulkJobProgress(ctx, id, done)
+	if err != nil {
+		s.log.Warnf("failed to save bulk job %s progress: %v", id, err)
+		return
+	}
+	if stop {
+		cancel()
+	}
+}
+
+// finish 작업 종료 기록 (err가 취소면 CANCELLED, 그 외 오류면 FAILED)
+func (s *bulkJobStore) finish(id string, result *BulkJobResult, err error) {
+	s.mu.Lock()
+	e, ok := s.jobs[id]
+	if !ok || bulkJobFinished(e.job.Status) {
+		s.mu.Unlock()
+		return
+	}
+	s.finishLocked(e, result, err)
+	job, saved := e.job, e.result
+	s.mu.Unlock()
+
+	s.save(job, saved)
+}
+
+func (s *bulkJobStore) finishLocked(e *bulkJobEntry, result *BulkJobResult, err error) {
+	now := s.now()
+	e.job.FinishedAt = &now
+	e.cancel()
+
+	switch {
+	case err == nil:
+		e.job.Status = BulkJobCompleted
+		e.job.Completed = e.job.Total
+		e.job.Progress = 1
+		e.result = result
+	case errors.Is(err, context.Canceled):
+		e.job.Status = BulkJobCancelled
+	default:
+		e.job.Status = BulkJobFailed
+		e.job.Error = err.Error()
+	}
+}
+
+// save 종료된 작업 상태와 결과를 테이블에 기록
+func (s *bulkJobStore) save(job BulkJob, result *BulkJobResult) {
+	if s.repo == nil {
+		return
+	}
+	ctx, done := s.dbContext()
+	defer done()
+	if err := s.repo.FinishBulkJob(ctx, job, result); err != nil {
+		s.log.Errorf("failed to save bulk job %s result: %v", job.ID, err)
+	}
+}
+
+// pruneLocked 보관 기간이 지난 종료 작업 정리
+func (s *bulkJobStore) pruneLocked() {
+	cutoff := s.now().Add(-BulkJobRetention)
+	for id, e := range s.jobs {
+		if e.job.FinishedAt != nil && e.job.FinishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func bulkJobFinished(status string) bool {
+	return status == BulkJobCompleted || status == BulkJobFailed || status == BulkJobCancelled
+}
+
+// writeBulkJobCSV 작업 결과를 CSV로 출력 (세트 번호, 번호 6개, 보너스, 신뢰도, 중복 사용자 수)
+func writeBulkJobCSV(w io.Writer, result *BulkJobResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"set", "n1", "n2", "n3", "n4", "n5", "n6", "bonus", "confidence", "shared_by", "nearly_shared_by"}); err != nil {
+		return err
+	}
+	for i, rec := range result.Recommendations {
+		row := make([]string, 0, 11)
+		row = append(row, strconv.Itoa(i+1))
+		for _, n := range rec.Numbers {
+			row = append(row, strconv.Itoa(n))
+		}
+		bonus := ""
+		if rec.Bonus != nil {
+			bonus = strconv.Itoa(*rec.Bonus)
+		}
+		row = append(row, bonus,
+			strconv.FormatFloat(rec.Confidence, 'f', 4, 64),
+			strconv.Itoa(rec.SharedBy),
+			strconv.Itoa(rec.NearlySharedBy))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,287 @@
+package lotto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/example/LottoSmash/internal/logger"
+)
+
+func TestFullWheel(t *testing.T) {
+	combos := FullWheel([]int{9, 1, 5, 3, 7, 11, 13})
+	if len(combos) != 7 {
+		t.Fatalf("len = %d, want 7", len(combos))
+	}
+	if got := comboKey(combos[0]); got != comboKey([]int{1, 3, 5, 7, 9, 11}) {
+		t.Errorf("first combo = %v", combos[0])
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range FullWheel([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}) {
+		key := comboKey(c)
+		if seen[key] {
+			t.Fatalf("duplicate combo %v", c)
+		}
+		seen[key] = true
+	}
+	if len(seen) != binomial(MaxWheelNumbers, NumbersPerDraw) || len(seen) > MaxBulkSets {
+		t.Errorf("wheel of %d numbers produced %d combos", MaxWheelNumbers, len(seen))
+	}
+}
+
+func TestValidateBulkJobRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     BulkJobRequest
+		wantErr bool
+	}{
+		{"default recommend", BulkJobRequest{Recommend: RecommendRequest{MethodCodes: []string{"BAYESIAN"}, Count: 300}}, false},
+		{"too many sets", BulkJobRequest{Recommend: RecommendRequest{MethodCodes: []string{"BAYESIAN"}, Count: MaxBulkSets + 1}}, true},
+		{"missing methods", BulkJobRequest{Type: BulkJobTypeRecommend}, true},
+		{"wheel", BulkJobRequest{Type: BulkJobTypeWheel, WheelNumbers: []int{1, 2, 3, 4, 5, 6, 7}}, false},
+		{"wheel too small", BulkJobRequest{Type: BulkJobTypeWheel, WheelNumbers: []int{1, 2, 3, 4, 5, 6}}, true},
+		{"wheel duplicate", BulkJobRequest{Type: BulkJobTypeWheel, WheelNumbers: []int{1, 2, 3, 4, 5, 6, 6}}, true},
+		{"wheel out of range", BulkJobRequest{Type: BulkJobTypeWheel, WheelNumbers: []int{1, 2, 3, 4, 5, 6, 46}}, true},
+		{"unknown type", BulkJobRequest{Type: "LOOP"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBulkJobRequest(&tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBulkJobStoreLifecycle(t *testing.T) {
+	store := newBulkJobStore(nil, nil)
+	noop := func() {}
+
+	for i, id := range []string{"a", "b"} {
+		if _, err := store.add(BulkJob{ID: id, UserID: 1, Total: 10}, noop); err != nil {
+			t.Fatalf("add %d: %v", i, err)
+		}
+	}
+	if _, err := store.add(BulkJob{ID: "c", UserID: 1, Total: 10}, noop); !errors.Is(err, ErrBulkJobLimitExceeded) {
+		t.Fatalf("third active job err = %v, want limit exceeded", err)
+	}
+	if _, err := store.add(BulkJob{ID: "d", UserID: 2, Total: 10}, noop); err != nil {
+		t.Fatalf("other user should not be limited: %v", err)
+	}
+
+	// 실행 및 진행률
+	if !store.start("a") {
+		t.Fatal("start a")
+	}
+	store.progress("a", 5)
+	job, _ := store.snapshot(1, "a")
+	if job.Status != BulkJobRunning || job.Progress != 0.5 {
+		t.Errorf("job = %+v", job)
+	}
+	if _, err := store.result(1, "a"); !errors.Is(err, ErrBulkJobNotReady) {
+		t.Errorf("result before completion err = %v", err)
+	}
+
+	store.finish("a", &BulkJobResult{JobID: "a"}, nil)
+	if res, err := store.result(1, "a"); err != nil || res.JobID != "a" {
+		t.Errorf("result = %v, %v", res, err)
+	}
+	if _, err := store.snapshot(2, "a"); !errors.Is(err, ErrBulkJobNotFound) {
+		t.Errorf("other user's job should not be visible")
+	}
+
+	// 완료되면 새 작업 등록 가능
+	if _, err := store.add(BulkJob{ID: "c", UserID: 1, Total: 10}, noop); err != nil {
+		t.Fatalf("add after completion: %v", err)
+	}
+	if got := len(mustList(t, store, 1)); got != 3 {
+		t.Errorf("list len = %d, want 3", got)
+	}
+}
+
+func TestBulkJobStoreCancel(t *testing.T) {
+	store := newBulkJobStore(nil, nil)
+
+	// 대기 중 취소: 워커가 꺼내도 실행하지 않음
+	ctx, cancel := context.WithCancel(context.Background())
+	store.add(BulkJob{ID: "q", UserID: 1}, cancel)
+	job, err := store.cancelJob(1, "q")
+	if err != nil || job.Status != BulkJobCancelled {
+		t.Fatalf("cancel queued = %+v, %v", job, err)
+	}
+	if ctx.Err() == nil {
+		t.Error("job context should be cancelled")
+	}
+	if store.start("q") {
+		t.Error("cancelled job should not start")
+	}
+
+	// 실행 중 취소: 작업이 취소 오류로 종료되면 CANCELLED
+	ctx, cancel = context.WithCancel(context.Background())
+	store.add(BulkJob{ID: "r", UserID: 1}, cancel)
+	store.start("r")
+	if job, _ := store.cancelJob(1, "r"); job.Status != BulkJobRunning {
+		t.Errorf("running job status = %s until worker stops", job.Status)
+	}
+	store.finish("r", nil, ctx.Err())
+	if job, _ := store.snapshot(1, "r"); job.Status != BulkJobCancelled {
+		t.Errorf("status = %s, want CANCELLED", job.Status)
+	}
+
+	// 실패
+	store.add(BulkJob{ID: "f", UserID: 1}, func() {})
+	store.start("f")
+	store.finish("f", nil, errors.New("boom"))
+	if job, _ := store.snapshot(1, "f"); job.Status != BulkJobFailed || job.Error != "boom" {
+		t.Errorf("failed job = %+v", job)
+	}
+}
+
+func TestBulkJobStorePrune(t *testing.T) {
+	store := newBulkJobStore(nil, nil)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	store.add(BulkJob{ID: "old", UserID: 1}, func() {})
+	store.start("old")
+	store.finish("old", &BulkJobResult{}, nil)
+
+	now = now.Add(BulkJobRetention + time.Minute)
+	if got := len(mustList(t, store, 1)); got != 0 {
+		t.Errorf("expired job not pruned: %d jobs", got)
+	}
+}
+
+func TestExecuteBulkJobPanicReleasesSlot(t *testing.T) {
+	lg, err := logger.New(t.TempDir(), "debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Service{log: lg, bulkJobs: newBulkJobStore(nil, nil)}
+
+	for _, id := range []string{"p1", "p2"} {
+		if _, err := s.bulkJobs.add(BulkJob{ID: id, UserID: 1, Total: 10}, func() {}); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+		s.executeBulkJob(context.Background(), id, 1, func(context.Context) (*BulkJobResult, error) {
+			panic("generator exploded")
+		})
+		job, err := s.bulkJobs.snapshot(1, id)
+		if err != nil || job.Status != BulkJobFailed || job.FinishedAt == nil {
+			t.Fatalf("panicked job = %+v, %v", job, err)
+		}
+		if !strings.Contains(job.Error, "generator exploded") {
+			t.Errorf("error = %q", job.Error)
+		}
+	}
+
+	// 두 번 panic한 뒤에도 사용자 작업 한도가 남아 있어야 함
+	if _, err := s.bulkJobs.add(BulkJob{ID: "next", UserID: 1, Total: 10}, func() {}); err != nil {
+		t.Errorf("slot not released after panic: %v", err)
+	}
+}
+
+func TestWriteBulkJobCSV(t *testing.T) {
+	bonus := 7
+	result := &BulkJobResult{
+		JobID: "x",
+		Recommendations: []Recommendation{
+			{Numbers: []int{1, 2, 3, 4, 5, 6}, Bonus: &bonus, Confidence: 0.5, SharedBy: 2},
+			{Numbers: []int{10, 20, 30, 40, 41, 42}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeBulkJobCSV(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %d, want 3", len(lines))
+	}
+	if lines[1] != "1,1,2,3,4,5,6,7,0.5000,2,0" {
+		t.Errorf("row = %q", lines[1])
+	}
+	if lines[2] != "2,10,20,30,40,41,42,,0.0000,0,0" {
+		t.Errorf("row = %q", lines[2])
+	}
+}
+
+// 대량 추천 작업 여러 개와 HTTP 추천이 동시에 세트를 생성해도 난수 소스를 안전하게 쓰는지 확인 (go test -race)
+// RecommendBulk의 DB 조회(세션 구성) 이후 단계인 세트 생성 루프를 병렬로 실행한다.
+func TestRecommendBulkConcurrentSessions(t *testing.T) {
+	lg, err := logger.New(t.TempDir(), "debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRecommender(nil, nil, lg)
+
+	stats := make([]AnalysisStat, 0, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		stats = append(stats, AnalysisStat{Number: n, TotalProb: float64(n%7 + 1), BayesianPost: float64(n%5 + 1)})
+	}
+	snap := &probabilitySnapshot{stats: stats}
+	draws := makeRandomDraws(300, 7)
+	req := RecommendRequest{
+		MethodCodes: []string{"NUMBER_FREQUENCY", "BAYESIAN"},
+		CombineCode: CombineSimpleAvg,
+		Mode:        RecommendModeOptimize,
+		Temperature: 1,
+		Count:       20,
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]Recommendation, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			collisions := newCollisionIndex(nil, nil, false, true)
+			objective := newComboObjective(draws, nil, DefaultOptimizeWeights)
+			objective.reject = collisions.rejected
+			session := &recommendSession{
+				snapshot:   snap,
+				objective:  objective,
+				collisions: collisions,
+				distinct:   true,
+				rng:        rand.New(rand.NewSource(r.rng.Int63())),
+			}
+			results[i], errs[i] = r.generate(context.Background(), req, session, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, recs := range results {
+		if errs[i] != nil {
+			t.Fatalf("session %d: %v", i, errs[i])
+		}
+		if len(recs) != req.Count {
+			t.Fatalf("session %d: %d sets, want %d", i, len(recs), req.Count)
+		}
+		seen := make(map[uint64]bool)
+		for _, rec := range recs {
+			mask := numbersMask(rec.Numbers)
+			if len(rec.Numbers) != NumbersPerDraw || seen[mask] {
+				t.Errorf("session %d: invalid or duplicate set %v", i, rec.Numbers)
+			}
+			seen[mask] = true
+		}
+	}
+}
+
+func mustList(t *testing.T, store *bulkJobStore, userID int64) []BulkJob {
+	t.Helper()
+	jobs, err := store.list(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jobs
+}
@@ -0,0 +1,282 @@
+package lotto
+
+import (
+	"context"
+	"math/bits"
+	"sort"
+	"sync"
+)
+
+// collisionPoolSize 대체 조합 탐색 시 사용할 점수 상위 번호 수 (C(14,6) = 3003개 조합)
+const collisionPoolSize = 14
+
+// RecommendationHolder 대상 회차 추천 조합과 보유자
+type RecommendationHolder struct {
+	HolderID int64 // user_id (비로그인 추천은 -추천 ID)
+	Numbers  []int
+}
+
+// holderIndexOverlap 갱신 시 마지막 추천 ID 이전부터 다시 읽는 범위
+// (ID 순서와 커밋 순서가 달라 늦게 보이는 추천 보완, 다시 읽은 추천은 add에서 중복 제거)
+const holderIndexOverlap = 100
+
+// holderIndex 대상 회차 추천 조합과 보유자 색인 (요청 간 공유)
+// 요청마다 회차 전체를 다시 읽지 않고, 마지막으로 읽은 추천 ID 이후의 추천만 추가한다.
+type holderIndex struct {
+	refreshMu sync.Mutex   // 갱신 직렬화
+	mu        sync.RWMutex // holders 읽기/추가
+	drawNo    int
+	lastID    int64
+	holders   map[uint64][]int64 // 조합 -> 보유자 목록 (중복 없음)
+}
+
+func newHolderIndex() *holderIndex {
+	return &holderIndex{holders: make(map[uint64][]int64)}
+}
+
+// refresh 대상 회차의 새 추천 반영 (대상 회차가 바뀌면 처음부터 다시 읽음)
+func (h *holderIndex) refresh(ctx context.Context, repo *Repository, drawNo int) error {
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+
+	var afterID int64
+	if h.drawNo == drawNo {
+		afterID = max(h.lastID-holderIndexOverlap, 0)
+	}
+	holders, lastID, err := repo.GetRecommendationHoldersAfter(ctx, drawNo, afterID)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.drawNo != drawNo {
+		h.drawNo, h.lastID, h.holders = drawNo, 0, make(map[uint64][]int64)
+	}
+	for _, holder := range holders {
+		h.addLocked(holder)
+	}
+	h.lastID = max(h.lastID, lastID)
+	return nil
+}
+
+// addLocked 보유자 추가 (mu 쓰기 잠금 상태에서 호출)
+func (h *holderIndex) addLocked(holder RecommendationHolder) {
+	mask := numbersMask(holder.Numbers)
+	for _, id := range h.holders[mask] {
+		if id == holder.HolderID {
+			return
+		}
+	}
+	h.holders[mask] = append(h.holders[mask], holder.HolderID)
+}
+
+// collisionIndex 대상 회차 다른 사용자 추천과 역대 1등 조합 색인
+// 조합은 번호 비트마스크(numbersMask)로 저장한다.
+type collisionIndex struct {
+	holders     *holderIndex   // 대상 회차 보유자 (요청 간 공유)
+	exclude     int64          // 보유자에서 제외할 본인 user_id (0 = 비로그인)
+	pastWinners map[uint64]int // 1등 조합 -> 회차 (스냅샷 공유, 읽기 전용)
+	avoidShared bool           // 다른 사용자와 같거나 5개 일치 조합 제외
+	allowPast   bool           // 역대 1등 조합 허용
+}
+
+// loadCollisionIndex 대상 회차 다른 사용자 추천 갱신 후 스냅샷의 역대 1등 조합과 함께 색인 구성
+func (r *Recommender) loadCollisionIndex(ctx context.Context, snap *probabilitySnapshot, req RecommendRequest, userID *int64, targetDrawNo int) (*collisionIndex, error) {
+	if err := r.holders.refresh(ctx, r.repo, targetDrawNo); err != nil {
+		return nil, err
+	}
+	c := &collisionIndex{
+		holders:     r.holders,
+		pastWinners: snap.winners,
+		avoidShared: req.AvoidShared,
+		allowPast:   req.AllowPastWinners,
+	}
+	if userID != nil {
+		c.exclude = *userID
+	}
+	return c, nil
+}
+
+// collisionIndexForDraw 추천 세션 없이 조합만 표시할 때 사용할 색인 (풀 휠 등)
+func (r *Recommender) collisionIndexForDraw(ctx context.Context, req RecommendRequest, userID *int64, targetDrawNo int) (*collisionIndex, error) {
+	latestDrawNo, err := r.repo.GetLatestDrawNo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := r.currentSnapshot(ctx, latestDrawNo, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.loadCollisionIndex(ctx, snap, req, userID, targetDrawNo)
+}
+
+// newCollisionIndex 추천 보유자 목록과 1등 조합(회차 -> 번호)으로 독립된 색인 구성
+func newCollisionIndex(holders []RecommendationHolder, winners map[int][]int, avoidShared, allowPast bool) *collisionIndex {
+	index := newHolderIndex()
+	for _, h := range holders {
+		index.addLocked(h)
+	}
+	return &collisionIndex{
+		holders:     index,
+		pastWinners: winnerMasks(winners),
+		avoidShared: avoidShared,
+		allowPast:   allowPast,
+	}
+}
+
+// winnerMasks 1등 조합(회차 -> 번호)을 조합 비트마스크 -> 회차로 변환
+func winnerMasks(winners map[int][]int) map[uint64]int {
+	masks := make(map[uint64]int, len(winners))
+	for drawNo, nums := range winners {
+		masks[numbersMask(nums)] = drawNo
+	}
+	return masks
+}
+
+// others 본인을 제외한 보유자 수
+func (c *collisionIndex) others(holders []int64) int {
+	n := 0
+	for _, h := range holders {
+		if h != c.exclude {
+			n++
+		}
+	}
+	return n
+}
+
+// sharedBy 같은 조합 / 5개 번호가 같은 조합을 받은 다른 보유자 수
+func (c *collisionIndex) sharedBy(nums []int) (exact, near int) {
+	c.holders.mu.RLock()
+	defer c.holders.mu.RUnlock()
+
+	mask := numbersMask(nums)
+	exact = c.others(c.holders.holders[mask])
+
+	seen := make(map[int64]bool)
+	c.eachNear(mask, func(holders []int64) bool {
+		for _, h := range holders {
+			if h != c.exclude {
+				seen[h] = true
+			}
+		}
+		return true
+	})
+	return exact, len(seen)
+}
+
+// pastWinner 역대 1등 조합이면 해당 회차
+func (c *collisionIndex) pastWinner(nums []int) (int, bool) {
+	drawNo, ok := c.pastWinners[numbersMask(nums)]
+	return drawNo, ok
+}
+
+// rejected 요청 옵션상 추천할 수 없는 조합인지 확인
+func (c *collisionIndex) rejected(nums []int) bool {
+	c.holders.mu.RLock()
+	defer c.holders.mu.RUnlock()
+	return c.rejectedLocked(nums)
+}
+
+// rejectedLocked rejected (holders.mu 읽기 잠금 상태에서 호출)
+func (c *collisionIndex) rejectedLocked(nums []int) bool {
+	mask := numbersMask(nums)
+	if _, ok := c.pastWinners[mask]; ok && !c.allowPast {
+		return true
+	}
+	if !c.avoidShared || len(c.holders.holders) == 0 {
+		return false
+	}
+	if c.others(c.holders.holders[mask]) > 0 {
+		return true
+	}
+	near := false
+	c.eachNear(mask, func(holders []int64) bool {
+		near = c.others(holders) > 0
+		return !near
+	})
+	return near
+}
+
+// eachNear 번호 하나만 다른 조합(6 × 39개)의 보유자 목록 순회 (fn이 false를 반환하면 중단)
+// holders.mu 읽기 잠금 상태에서 호출한다.
+func (c *collisionIndex) eachNear(mask uint64, fn func(holders []int64) bool) {
+	if len(c.holders.holders) == 0 {
+		return
+	}
+	for rest := mask; rest != 0; rest &= rest - 1 {
+		base := mask &^ (uint64(1) << uint(bits.TrailingZeros64(rest)))
+		for n := 1; n <= TotalNumbers; n++ {
+			bit := uint64(1) << uint(n)
+			if mask&bit != 0 {
+				continue
+			}
+			if holders, ok := c.holders.holders[base|bit]; ok && !fn(holders) {
+				return
+			}
+		}
+	}
+}
+
+// alternative 제외 대상이 아닌 조합 중 점수 합이 가장 큰 조합 탐색
+// 점수 상위 collisionPoolSize개 번호의 모든 6개 조합을 비교하며, 모두 제외 대상이면 nil을 반환한다.
+func (c *collisionIndex) alternative(scores map[int]float64, exclude [][]int) []int {
+	c.holders.mu.RLock()
+	defer c.holders.mu.RUnlock()
+
+	pool := make([]numberScore, 0, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		pool = append(pool, numberScore{Number: n, Score: scores[n]})
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].Score > pool[j].Score
+	})
+	pool = pool[:collisionPoolSize]
+
+	excluded := make(map[uint64]bool, len(exclude))
+	for _, nums := range exclude {
+		excluded[numbersMask(nums)] = true
+	}
+
+	var best []int
+	bestScore := 0.0
+	idx := []int{0, 1, 2, 3, 4, 5}
+	nums := make([]int, NumbersPerDraw)
+	for {
+		score := 0.0
+		for i, p := range idx {
+			nums[i] = pool[p].Number
+			score += pool[p].Score
+		}
+		if (best == nil || score > bestScore) && !excluded[numbersMask(nums)] && !c.rejectedLocked(nums) {
+			best = append(best[:0], nums...)
+			bestScore = score
+		}
+
+		// 다음 조합 (사전순)
+		i := NumbersPerDraw - 1
+		for i >= 0 && idx[i] == collisionPoolSize-NumbersPerDraw+i {
+			i--
+		}
+		if i < 0 {
+			break
+		}
+		idx[i]++
+		for j := i + 1; j < NumbersPerDraw; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+
+	if best != nil {
+		sort.Ints(best)
+	}
+	return best
+}
+
+// annotate 추천 결과에 중복 사용자 수와 1등 조합 회차 기록
+func (c *collisionIndex) annotate(rec *Recommendation) {
+	rec.SharedBy, rec.NearlySharedBy = c.sharedBy(rec.Numbers)
+	if drawNo, ok := c.pastWinner(rec.Numbers); ok {
+		rec.PastWinnerDrawNo = &drawNo
+	}
+}
@@ -0,0 +1,145 @@
+package lotto
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCollisionIndexSharedBy(t *testing.T) {
+	holders := []RecommendationHolder{
+		{HolderID: 1, Numbers: []int{1, 2, 3, 4, 5, 6}},
+		{HolderID: 2, Numbers: []int{1, 2, 3, 4, 5, 6}},
+		{HolderID: 2, Numbers: []int{1, 2, 3, 4, 5, 7}},    // 5개 일치
+		{HolderID: 3, Numbers: []int{1, 2, 3, 4, 8, 9}},    // 4개 일치
+		{HolderID: -10, Numbers: []int{2, 3, 4, 5, 6, 45}}, // 비로그인, 5개 일치
+	}
+	c := newCollisionIndex(holders, nil, false, false)
+
+	exact, near := c.sharedBy([]int{6, 5, 4, 3, 2, 1})
+	if exact != 2 {
+		t.Errorf("exact = %d, want 2", exact)
+	}
+	if near != 2 {
+		t.Errorf("near = %d, want 2", near)
+	}
+
+	exact, near = c.sharedBy([]int{10, 11, 12, 13, 14, 15})
+	if exact != 0 || near != 0 {
+		t.Errorf("unrelated combination shared by %d/%d", exact, near)
+	}
+}
+
+func TestCollisionIndexRejected(t *testing.T) {
+	holders := []RecommendationHolder{{HolderID: 1, Numbers: []int{1, 2, 3, 4, 5, 6}}}
+	winners := map[int][]int{100: {10, 20, 30, 40, 41, 42}}
+
+	tests := []struct {
+		name        string
+		avoidShared bool
+		allowPast   bool
+		nums        []int
+		want        bool
+	}{
+		{"past winner refused by default", false, false, []int{10, 20, 30, 40, 41, 42}, true},
+		{"past winner allowed", false, true, []int{10, 20, 30, 40, 41, 42}, false},
+		{"shared allowed by default", false, false, []int{1, 2, 3, 4, 5, 6}, false},
+		{"shared avoided", true, false, []int{1, 2, 3, 4, 5, 6}, true},
+		{"nearly shared avoided", true, false, []int{1, 2, 3, 4, 5, 45}, true},
+		{"four matches allowed", true, false, []int{1, 2, 3, 4, 44, 45}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCollisionIndex(holders, winners, tt.avoidShared, tt.allowPast)
+			if got := c.rejected(tt.nums); got != tt.want {
+				t.Errorf("rejected(%v) = %v, want %v", tt.nums, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollisionIndexAlternative(t *testing.T) {
+	scores := make(map[int]float64, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		scores[n] = float64(n)
+	}
+	top := []int{40, 41, 42, 43, 44, 45}
+
+	// 상위 조합이 역대 1등이면 점수 손실이 가장 작은 조합(최하위 번호 교체)으로 대체
+	c := newCollisionIndex(nil, map[int][]int{1: top}, false, false)
+	got := c.alternative(scores, nil)
+	want := []int{39, 41, 42, 43, 44, 45}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("alternative = %v, want %v", got, want)
+	}
+
+	// 같은 응답의 이전 세트도 제외
+	got = c.alternative(scores, [][]int{want})
+	if reflect.DeepEqual(got, want) || reflect.DeepEqual(got, top) {
+		t.Errorf("alternative returned excluded combination %v", got)
+	}
+
+	// 다른 사용자와 5개 이상 겹치는 조합 회피
+	shared := newCollisionIndex([]RecommendationHolder{{HolderID: 1, Numbers: top}}, nil, true, false)
+	got = shared.alternative(scores, nil)
+	if got == nil {
+		t.Fatal("expected alternative combination")
+	}
+	if shared.rejected(got) {
+		t.Errorf("alternative %v still collides", got)
+	}
+	if exact, near := shared.sharedBy(got); exact != 0 || near != 0 {
+		t.Errorf("alternative %v shared by %d/%d", got, exact, near)
+	}
+}
+
+func TestComboObjectiveOptimize_Reject(t *testing.T) {
+	objective := newComboObjective(makeRandomDraws(100, 3), nil, OptimizeWeights{Score: 1})
+	c := newCollisionIndex([]RecommendationHolder{{HolderID: 1, Numbers: []int{40, 41, 42, 43, 44, 45}}}, nil, true, false)
+	objective.reject = c.rejected
+
+	scores := make(map[int]float64, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		scores[n] = float64(n)
+	}
+	start := []int{40, 41, 42, 43, 44, 45}
+
+	rng := rand.New(rand.NewSource(1))
+	nums, _ := objective.optimize(rng, scores, start, nil, 2000)
+	if c.rejected(nums) {
+		t.Errorf("rejected combination returned: %v", nums)
+	}
+}
+
+func TestCollisionIndexSharedHolders(t *testing.T) {
+	index := newHolderIndex()
+	mine := []int{1, 2, 3, 4, 5, 6}
+	other := []int{10, 11, 12, 13, 14, 15}
+	for _, h := range []RecommendationHolder{
+		{HolderID: 1, Numbers: mine},
+		{HolderID: 2, Numbers: other},
+		{HolderID: 2, Numbers: other}, // 갱신 범위가 겹쳐 다시 읽은 추천
+	} {
+		index.addLocked(h)
+	}
+
+	// 같은 색인을 여러 요청이 공유하되 본인 추천은 요청마다 제외
+	c := &collisionIndex{holders: index, exclude: 1, avoidShared: true}
+	if c.rejected(mine) {
+		t.Error("own combination should not be rejected")
+	}
+	if exact, _ := c.sharedBy(mine); exact != 0 {
+		t.Errorf("own combination shared by %d", exact)
+	}
+	if exact, _ := c.sharedBy(other); exact != 1 {
+		t.Errorf("re-read holder counted twice: exact = %d", exact)
+	}
+	if !c.rejected(other) {
+		t.Error("other user's combination should be rejected")
+	}
+
+	anonymous := &collisionIndex{holders: index, avoidShared: true}
+	if !anonymous.rejected(mine) {
+		t.Error("anonymous request should see every holder")
+	}
+}
@@ -0,0 +1,41 @@
+package lotto
+
+import (
+	"time"
+
+	"github.com/example/LottoSmash/internal/constants"
+)
+
+// SalesCutoffHour 추첨일(토요일) 판매 마감 시각 (KST, 20:00)
+const SalesCutoffHour = 20
+
+// drawInterval 회차 간격 (매주)
+const drawInterval = 7 * 24 * time.Hour
+
+// kstLocation 한국 표준시
+var kstLocation = loadKST()
+
+func loadKST() *time.Location {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		return time.FixedZone("KST", 9*60*60)
+	}
+	return loc
+}
+
+// DrawSalesCutoff 회차별 판매 마감 시각 (1회차: 2002-12-07 20:00 KST)
+func DrawSalesCutoff(drawNo int) time.Time {
+	first := time.Date(constants.FirstDrawYear, constants.FirstDrawMonth, constants.FirstDrawDay,
+		SalesCutoffHour, 0, 0, 0, kstLocation)
+	return first.Add(time.Duration(drawNo-1) * drawInterval)
+}
+
+// NextDrawNo t 시점에 구매한 번호가 참여하는 회차
+// 토요일 판매 마감(20:00) 이후에는 다음 주 회차가 대상이다.
+func NextDrawNo(t time.Time) int {
+	first := DrawSalesCutoff(1)
+	if t.Before(first) {
+		return 1
+	}
+	return int(t.Sub(first)/drawInterval) + 2
+}
@@ -0,0 +1,48 @@
+package lotto
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDrawSalesCutoff(t *testing.T) {
+	got := DrawSalesCutoff(1)
+	want := time.Date(2002, 12, 7, 20, 0, 0, 0, kstLocation)
+	if !got.Equal(want) {
+		t.Errorf("cutoff(1) = %v, want %v", got, want)
+	}
+
+	// 회차는 매주 토요일
+	for _, drawNo := range []int{2, 100, 1150} {
+		c := DrawSalesCutoff(drawNo).In(kstLocation)
+		if c.Weekday() != time.Saturday || c.Hour() != SalesCutoffHour {
+			t.Errorf("cutoff(%d) = %v, want Saturday %d:00", drawNo, c, SalesCutoffHour)
+		}
+	}
+}
+
+func TestNextDrawNo(t *testing.T) {
+	cutoff := DrawSalesCutoff(1150)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{"before first draw", time.Date(2002, 11, 1, 0, 0, 0, 0, kstLocation), 1},
+		{"saturday before cutoff", cutoff.Add(-time.Minute), 1150},
+		{"at cutoff", cutoff, 1151},
+		{"saturday evening after draw", cutoff.Add(90 * time.Minute), 1151},
+		{"next sunday", cutoff.Add(20 * time.Hour), 1151},
+		{"previous sunday", cutoff.Add(-6 * 24 * time.Hour), 1150},
+		{"utc input", cutoff.Add(-time.Second).UTC(), 1150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextDrawNo(tt.at); got != tt.want {
+				t.Errorf("NextDrawNo(%v) = %d, want %d", tt.at, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,435 @@
+package lotto
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 실험 상태 (DRAFT → RUNNING → STOPPED)
+const (
+	ExperimentDraft   = "DRAFT"
+	ExperimentRunning = "RUNNING"
+	ExperimentStopped = "STOPPED"
+)
+
+const (
+	MinExperimentVariants = 2
+	MaxExperimentVariants = 10
+
+	// ExperimentCacheTTL 진행 중 실험 캐시 유지 시간
+	ExperimentCacheTTL = 30 * time.Second
+
+	// ExperimentSignificanceLevel 유의 수준 (p < 0.05)
+	ExperimentSignificanceLevel = 0.05
+)
+
+var (
+	ErrExperimentNotFound  = errors.New("experiment not found")
+	ErrExperimentDuplicate = errors.New("experiment key already exists")
+	ErrExperimentConflict  = errors.New("experiment state conflict")
+)
+
+var experimentKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// Experiment 추천 A/B 실험
+// 진행 중(RUNNING) 실험은 동시에 하나만 둘 수 있으며, 로그인 사용자는 키와 사용자 ID 해시로 변형에 고정 배정된다.
+type Experiment struct {
+	ID          int64               `json:"id"`
+	Key         string              `json:"key"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Status      string              `json:"status"`
+	Variants    []ExperimentVariant `json:"variants"` // 첫 번째 변형이 대조군
+	StartedAt   *time.Time          `json:"started_at,omitempty"`
+	StoppedAt   *time.Time          `json:"stopped_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// ExperimentVariant 실험 변형
+type ExperimentVariant struct {
+	Code   string        `json:"code"`
+	Name   string        `json:"name,omitempty"`
+	Weight int           `json:"weight"` // 트래픽 비중 (합계 대비)
+	Config VariantConfig `json:"config"`
+}
+
+// VariantConfig 변형별 추천 기본값과 UI 플래그 (요청에서 지정한 값이 우선)
+type VariantConfig struct {
+	MethodCodes []string        `json:"method_codes,omitempty"` // 기본 분석 기법
+	CombineCode string          `json:"combine_code,omitempty"` // 기본 조합 방법
+	Temperature float64         `json:"temperature,omitempty"`  // 기본 샘플링 온도 (0 = 상위 번호 고정)
+	Flags       map[string]bool `json:"flags,omitempty"`        // 클라이언트 UI 플래그 (예: {"explanation_ui": true})
+}
+
+// ExperimentRequest 실험 생성/수정 요청
+type ExperimentRequest struct {
+	Key         string              `json:"key"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Variants    []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentAssignment 사용자에게 배정된 실험 변형
+type ExperimentAssignment struct {
+	ExperimentID  int64           `json:"experiment_id"`
+	ExperimentKey string          `json:"experiment_key"`
+	Variant       string          `json:"variant"`
+	Flags         map[string]bool `json:"flags,omitempty"`
+
+	config VariantConfig
+}
+
+// experimentCache 진행 중 실험 조회 결과 캐시 (experiment가 nil이면 진행 중 실험 없음)
+type experimentCache struct {
+	experiment *Experiment
+	loadedAt   time.Time
+}
+
+// validateExperimentRequest 실험 요청 유효성 검사 (키/코드 정규화)
+func validateExperimentRequest(req *ExperimentRequest) error {
+	req.Key = strings.ToLower(strings.TrimSpace(req.Key))
+	req.Name = strings.TrimSpace(req.Name)
+	if !experimentKeyPattern.MatchString(req.Key) {
+		return errors.New("key must be 1-50 characters of lowercase letters, digits, '-' or '_'")
+	}
+	if req.Name == "" || len([]rune(req.Name)) > 100 {
+		return errors.New("name is required (max 100 characters)")
+	}
+	if len(req.Variants) < MinExperimentVariants || len(req.Variants) > MaxExperimentVariants {
+		return fmt.Errorf("between %d and %d variants are required", MinExperimentVariants, MaxExperimentVariants)
+	}
+
+	seen := make(map[string]bool, len(req.Variants))
+	totalWeight := 0
+	for i := range req.Variants {
+		v := &req.Variants[i]
+		v.Code = strings.ToUpper(strings.TrimSpace(v.Code))
+		if v.Code == "" || len(v.Code) > 30 {
+			return fmt.Errorf("variant %d: code is required (max 30 characters)", i+1)
+		}
+		if seen[v.Code] {
+			return fmt.Errorf("duplicate variant code: %s", v.Code)
+		}
+		seen[v.Code] = true
+		if v.Weight < 0 {
+			return fmt.Errorf("variant %s: weight must not be negative", v.Code)
+		}
+		totalWeight += v.Weight
+		if err := validateVariantConfig(v.Config); err != nil {
+			return fmt.Errorf("variant %s: %w", v.Code, err)
+		}
+	}
+	if totalWeight == 0 {
+		return errors.New("sum of variant weights must be positive")
+	}
+	return nil
+}
+
+// validateVariantConfig 변형 기본값 검사
+func validateVariantConfig(c VariantConfig) error {
+	if len(c.MethodCodes) > MaxMethodCodes {
+		return fmt.Errorf("maximum %d method_codes allowed", MaxMethodCodes)
+	}
+	if c.Temperature < 0 || c.Temperature > MaxSamplingTemperature {
+		return fmt.Errorf("temperature must be between 0 and %g", MaxSamplingTemperature)
+	}
+	return nil
+}
+
+// assign 사용자 ID로 변형 배정 (같은 실험이면 항상 같은 변형)
+func (e *Experiment) assign(userID int64) *ExperimentAssignment {
+	totalWeight := 0
+	for _, v := range e.Variants {
+		totalWeight += v.Weight
+	}
+	if totalWeight <= 0 {
+		return nil
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s:%d", e.Key, userID)
+	bucket := int(h.Sum64() % uint64(totalWeight))
+
+	for _, v := range e.Variants {
+		if bucket < v.Weight {
+			return &ExperimentAssignment{
+				ExperimentID:  e.ID,
+				ExperimentKey: e.Key,
+				Variant:       v.Code,
+				Flags:         v.Config.Flags,
+				config:        v.Config,
+			}
+		}
+		bucket -= v.Weight
+	}
+	return nil
+}
+
+// applyDefaults 요청에서 생략한 기법/조합 방법/온도를 변형 기본값으로 채움 (nil이면 무시)
+func (a *ExperimentAssignment) applyDefaults(req *RecommendRequest) {
+	if a == nil {
+		return
+	}
+	if len(req.MethodCodes) == 0 && len(a.config.MethodCodes) > 0 {
+		req.MethodCodes = append([]string(nil), a.config.MethodCodes...)
+	}
+	if req.CombineCode == "" {
+		req.CombineCode = a.config.CombineCode
+	}
+	if req.Temperature == 0 {
+		req.Temperature = a.config.Temperature
+	}
+}
+
+// ========================================
+// 실험 결과 리포트
+// ========================================
+
+// VariantMetrics 변형별 원시 집계 (리포트 계산용)
+type VariantMetrics struct {
+	Code            string
+	Users           int     // 추천을 받은 사용자 수
+	Recommendations int     // 추천 세트 수
+	Checked         int     // 당첨 확인이 끝난 추천 수
+	MatchMean       float64 // 확인된 추천의 평균 일치 개수
+	MatchVariance   float64 // 일치 개수 표본 분산
+	Winners         int     // 5등 이상 당첨 추천 수
+	RepeatUsers     int     // 2개 이상 회차에 추천을 받은 사용자 수
+	ZamSpent        int64   // 실험 기간 중 사용자 Zam 사용량 합계
+	ZamMean         float64 // 사용자당 평균 Zam 사용량
+	ZamVariance     float64 // 사용자당 Zam 사용량 표본 분산
+}
+
+// ExperimentReport 실험 변형별 성과 리포트
+type ExperimentReport struct {
+	Experiment  Experiment      `json:"experiment"`
+	Control     string          `json:"control"`
+	Variants    []VariantReport `json:"variants"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// VariantReport 변형별 성과와 대조군 대비 유의성 검정
+type VariantReport struct {
+	Code            string             `json:"code"`
+	Name            string             `json:"name,omitempty"`
+	Users           int                `json:"users"`
+	Recommendations int                `json:"recommendations"`
+	Checked         int                `json:"checked"`
+	AvgMatchCount   float64            `json:"avg_match_count"`
+	WinRate         float64            `json:"win_rate"`              // 확인된 추천 중 5등 이상 비율
+	RepeatRate      float64            `json:"repeat_rate"`           // 2개 이상 회차에 다시 추천을 받은 사용자 비율
+	ZamSpent        int64              `json:"zam_spent"`             // 실험 기간 중 Zam 사용량 합계
+	ZamSpentPerUser float64            `json:"zam_spent_per_user"`    // 사용자당 평균 Zam 사용량
+	Comparisons     []MetricComparison `json:"comparisons,omitempty"` // 대조군 대비 (대조군 자신은 생략)
+}
+
+// MetricComparison 지표별 대조군 대비 검정 결과
+type MetricComparison struct {
+	Metric      string  `json:"metric"`
+	Test        string  `json:"test"` // welch_t / two_proportion_z
+	Control     float64 `json:"control"`
+	Variant     float64 `json:"variant"`
+	Diff        float64 `json:"diff"`
+	Statistic   float64 `json:"statistic"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
+}
+
+// buildExperimentReport 변형 순서대로 리포트 구성 (첫 번째 변형이 대조군)
+// 추천 기록이 없는 변형도 0으로 포함한다.
+func buildExperimentReport(exp Experiment, metrics []VariantMetrics, now time.Time) *ExperimentReport {
+	byCode := make(map[string]VariantMetrics, len(metrics))
+	for _, m := range metrics {
+		byCode[m.Code] = m
+	}
+
+	report := &ExperimentReport{
+		Experiment:  exp,
+		Variants:    make([]VariantReport, 0, len(exp.Variants)),
+		GeneratedAt: now,
+	}
+	if len(exp.Variants) == 0 {
+		return report
+	}
+	report.Control = exp.Variants[0].Code
+	control := byCode[report.Control]
+
+	for i, v := range exp.Variants {
+		m := byCode[v.Code]
+		vr := VariantReport{
+			Code:            v.Code,
+			Name:            v.Name,
+			Users:           m.Users,
+			Recommendations: m.Recommendations,
+			Checked:         m.Checked,
+			AvgMatchCount:   m.MatchMean,
+			WinRate:         ratio(m.Winners, m.Checked),
+			RepeatRate:      ratio(m.RepeatUsers, m.Users),
+			ZamSpent:        m.ZamSpent,
+			ZamSpentPerUser: m.ZamMean,
+		}
+		if i > 0 {
+			vr.Comparisons = compareVariant(control, m)
+		}
+		report.Variants = append(report.Variants, vr)
+	}
+	return report
+}
+
+// compareVariant 지표별 대조군 대비 검정
+func compareVariant(control, variant VariantMetrics) []MetricComparison {
+	comparisons := make([]MetricComparison, 0, 4)
+
+	t, p := welchTTest(control.MatchMean, control.MatchVariance, control.Checked, variant.MatchMean, variant.MatchVariance, variant.Checked)
+	comparisons = append(comparisons, newComparison("avg_match_count", "welch_t", control.MatchMean, variant.MatchMean, t, p))
+
+	z, p := twoProportionZTest(control.Winners, control.Checked, variant.Winners, variant.Checked)
+	comparisons = append(comparisons, newComparison("win_rate", "two_proportion_z",
+		ratio(control.Winners, control.Checked), ratio(variant.Winners, variant.Checked), z, p))
+
+	z, p = twoProportionZTest(control.RepeatUsers, control.Users, variant.RepeatUsers, variant.Users)
+	comparisons = append(comparisons, newComparison("repeat_rate", "two_proportion_z",
+		ratio(control.RepeatUsers, control.Users), ratio(variant.RepeatUsers, variant.Users), z, p))
+
+	t, p = welchTTest(control.ZamMean, control.ZamVariance, control.Users, variant.ZamMean, variant.ZamVariance, variant.Users)
+	comparisons = append(comparisons, newComparison("zam_spent_per_user", "welch_t", control.ZamMean, variant.ZamMean, t, p))
+
+	return comparisons
+}
+
+func newComparison(metric, test string, control, variant, statistic, pValue float64) MetricComparison {
+	return MetricComparison{
+		Metric:      metric,
+		Test:        test,
+		Control:     control,
+		Variant:     variant,
+		Diff:        variant - control,
+		Statistic:   statistic,
+		PValue:      pValue,
+		Significant: pValue < ExperimentSignificanceLevel,
+	}
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// ========================================
+// 유의성 검정
+// ========================================
+
+// welchTTest 두 표본 평균 차이의 Welch t-검정 (양측), 표본이 부족하거나 분산이 0이면 p=1
+func welchTTest(mean1, var1 float64, n1 int, mean2, var2 float64, n2 int) (t, p float64) {
+	if n1 < 2 || n2 < 2 {
+		return 0, 1
+	}
+	se1 := var1 / float64(n1)
+	se2 := var2 / float64(n2)
+	se := se1 + se2
+	if se <= 0 {
+		return 0, 1
+	}
+	t = (mean2 - mean1) / math.Sqrt(se)
+	df := se * se / (se1*se1/float64(n1-1) + se2*se2/float64(n2-1))
+	return t, studentTTwoTailed(t, df)
+}
+
+// twoProportionZTest 두 비율 차이의 z-검정 (합동 비율, 양측)
+func twoProportionZTest(x1, n1, x2, n2 int) (z, p float64) {
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+	pooled := float64(x1+x2) / float64(n1+n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return 0, 1
+	}
+	z = (ratio(x2, n2) - ratio(x1, n1)) / se
+	return z, math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// studentTTwoTailed 자유도 df인 t 분포의 양측 p-value: I_{df/(df+t²)}(df/2, 1/2)
+func studentTTwoTailed(t, df float64) float64 {
+	if math.IsInf(df, 1) || df > 1e7 {
+		return math.Erfc(math.Abs(t) / math.Sqrt2)
+	}
+	return regIncBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// regIncBeta 정규화 불완전 베타 함수 I_x(a, b) (연분수 전개)
+func regIncBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// 수렴이 빠른 쪽으로 계산
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// betaContinuedFraction 불완전 베타 함수 연분수 (modified Lentz)
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIter = 200
+		eps     = 1e-14
+		tiny    = 1e-300
+	)
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		aa := fm * (b - fm) * x / ((a + m2 - 1) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + fm) * (a + b + fm) * x / ((a + m2) * (a + m2 + 1))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return h
+}
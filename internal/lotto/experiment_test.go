@@ -0,0 +1,235 @@
+package lotto
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func testExperiment() *Experiment {
+	return &Experiment{
+		ID:  7,
+		Key: "default-methods",
+		Variants: []ExperimentVariant{
+			{Code: "CONTROL", Weight: 1},
+			{Code: "DECAY", Weight: 1, Config: VariantConfig{
+				MethodCodes: []string{MethodDecayFrequency},
+				CombineCode: CombineBayesian,
+				Temperature: 0.5,
+				Flags:       map[string]bool{"explanation_ui": true},
+			}},
+		},
+	}
+}
+
+func TestValidateExperimentRequest(t *testing.T) {
+	valid := func() ExperimentRequest {
+		return ExperimentRequest{
+			Key:  " Temp-Test ",
+			Name: "온도 실험",
+			Variants: []ExperimentVariant{
+				{Code: "control", Weight: 50},
+				{Code: "hot", Weight: 50, Config: VariantConfig{Temperature: 1}},
+			},
+		}
+	}
+
+	req := valid()
+	if err := validateExperimentRequest(&req); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if req.Key != "temp-test" || req.Variants[0].Code != "CONTROL" {
+		t.Errorf("not normalized: key=%q code=%q", req.Key, req.Variants[0].Code)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*ExperimentRequest)
+	}{
+		{"bad key", func(r *ExperimentRequest) { r.Key = "has space" }},
+		{"no name", func(r *ExperimentRequest) { r.Name = " " }},
+		{"single variant", func(r *ExperimentRequest) { r.Variants = r.Variants[:1] }},
+		{"duplicate code", func(r *ExperimentRequest) { r.Variants[1].Code = "CONTROL" }},
+		{"zero weights", func(r *ExperimentRequest) { r.Variants[0].Weight, r.Variants[1].Weight = 0, 0 }},
+		{"negative weight", func(r *ExperimentRequest) { r.Variants[0].Weight = -1 }},
+		{"temperature", func(r *ExperimentRequest) { r.Variants[1].Config.Temperature = MaxSamplingTemperature + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.mutate(&req)
+			if err := validateExperimentRequest(&req); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestExperimentAssignConsistent(t *testing.T) {
+	exp := testExperiment()
+
+	counts := make(map[string]int)
+	for userID := int64(1); userID <= 2000; userID++ {
+		a := exp.assign(userID)
+		if a == nil {
+			t.Fatalf("user %d not assigned", userID)
+		}
+		if again := exp.assign(userID); again.Variant != a.Variant {
+			t.Fatalf("user %d assignment changed: %s -> %s", userID, a.Variant, again.Variant)
+		}
+		counts[a.Variant]++
+	}
+	// 1:1 비중이면 대략 절반씩
+	if counts["CONTROL"] < 800 || counts["DECAY"] < 800 {
+		t.Errorf("unbalanced assignment: %v", counts)
+	}
+
+	// 비중 0인 변형은 배정되지 않음
+	exp.Variants[1].Weight = 0
+	for userID := int64(1); userID <= 100; userID++ {
+		if a := exp.assign(userID); a.Variant != "CONTROL" {
+			t.Fatalf("zero-weight variant assigned to user %d", userID)
+		}
+	}
+}
+
+func TestExperimentApplyDefaults(t *testing.T) {
+	exp := testExperiment()
+	a := &ExperimentAssignment{Variant: "DECAY", config: exp.Variants[1].Config}
+
+	req := RecommendRequest{}
+	a.applyDefaults(&req)
+	if len(req.MethodCodes) != 1 || req.CombineCode != CombineBayesian || req.Temperature != 0.5 {
+		t.Errorf("defaults not applied: %+v", req)
+	}
+
+	// 요청 값이 우선
+	req = RecommendRequest{MethodCodes: []string{"BAYESIAN"}, CombineCode: CombineSimpleAvg, Temperature: 2}
+	a.applyDefaults(&req)
+	if req.MethodCodes[0] != "BAYESIAN" || req.CombineCode != CombineSimpleAvg || req.Temperature != 2 {
+		t.Errorf("request values overridden: %+v", req)
+	}
+
+	var none *ExperimentAssignment
+	none.applyDefaults(&req) // nil이면 무시
+}
+
+func TestStudentTTwoTailed(t *testing.T) {
+	tests := []struct {
+		t, df, want float64
+	}{
+		{2.0, 10, 0.07339},
+		{2.228, 10, 0.05},
+		{1.0, 1, 0.5},
+		{0, 5, 1},
+	}
+	for _, tt := range tests {
+		if got := studentTTwoTailed(tt.t, tt.df); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("p(t=%v, df=%v) = %.5f, want %.5f", tt.t, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestSignificanceTests(t *testing.T) {
+	// 동일한 분포면 유의하지 않음
+	if _, p := welchTTest(1, 1, 100, 1, 1, 100); p != 1 {
+		t.Errorf("equal means p = %v, want 1", p)
+	}
+	// 표본 부족
+	if _, p := welchTTest(1, 1, 1, 5, 1, 100); p != 1 {
+		t.Errorf("small sample p = %v, want 1", p)
+	}
+	// 큰 차이는 유의
+	if tStat, p := welchTTest(1.0, 0.5, 500, 1.3, 0.5, 500); tStat <= 0 || p >= 0.001 {
+		t.Errorf("welch t = %v, p = %v", tStat, p)
+	}
+
+	// 10% vs 15% (각 1000명): z ≈ 3.38
+	z, p := twoProportionZTest(100, 1000, 150, 1000)
+	if math.Abs(z-3.38) > 0.01 || p >= 0.001 {
+		t.Errorf("z = %v, p = %v", z, p)
+	}
+	if _, p := twoProportionZTest(0, 0, 1, 10); p != 1 {
+		t.Errorf("empty control p = %v", p)
+	}
+}
+
+func TestBuildExperimentReport(t *testing.T) {
+	exp := testExperiment()
+	metrics := []VariantMetrics{
+		{Code: "DECAY", Users: 1000, Recommendations: 3000, Checked: 2000, MatchMean: 0.85, MatchVariance: 0.6, Winners: 60, RepeatUsers: 500},
+		{Code: "CONTROL", Users: 1000, Recommendations: 2800, Checked: 2000, MatchMean: 0.8, MatchVariance: 0.6, Winners: 40, RepeatUsers: 400},
+		{Code: "UNKNOWN", Users: 3},
+	}
+
+	report := buildExperimentReport(*exp, metrics, time.Now())
+	if report.Control != "CONTROL" || len(report.Variants) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	control, variant := report.Variants[0], report.Variants[1]
+	if control.Comparisons != nil {
+		t.Error("control should not be compared with itself")
+	}
+	if variant.WinRate != 0.03 || variant.RepeatRate != 0.5 {
+		t.Errorf("variant rates = %v, %v", variant.WinRate, variant.RepeatRate)
+	}
+	if len(variant.Comparisons) != 4 {
+		t.Fatalf("comparisons = %d, want 4", len(variant.Comparisons))
+	}
+	repeat := variant.Comparisons[2]
+	if repeat.Metric != "repeat_rate" || !repeat.Significant || math.Abs(repeat.Diff-0.1) > 1e-9 {
+		t.Errorf("repeat comparison = %+v", repeat)
+	}
+	if zam := variant.Comparisons[3]; zam.Significant {
+		t.Errorf("no zam data should not be significant: %+v", zam)
+	}
+
+	// 추천 기록이 없는 변형도 0으로 포함
+	empty := buildExperimentReport(*exp, nil, time.Now())
+	if len(empty.Variants) != 2 || empty.Variants[1].Users != 0 {
+		t.Errorf("empty report = %+v", empty)
+	}
+}
+
+func TestSampleNumbers(t *testing.T) {
+	r := &Recommender{rng: rand.New(rand.NewSource(1))}
+	scores := make(map[int]float64, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		scores[n] = float64(n)
+	}
+
+	// 중복 없이 6개
+	for i := 0; i < 50; i++ {
+		nums := r.sampleNumbers(scores, NumbersPerDraw, 1)
+		if len(nums) != NumbersPerDraw {
+			t.Fatalf("len = %d", len(nums))
+		}
+		seen := make(map[int]bool)
+		for _, n := range nums {
+			if n < 1 || n > TotalNumbers || seen[n] {
+				t.Fatalf("invalid sample %v", nums)
+			}
+			seen[n] = true
+		}
+	}
+
+	// 낮은 온도는 상위 번호에 집중
+	sumTop := 0
+	for i := 0; i < 200; i++ {
+		for _, n := range r.sampleNumbers(scores, NumbersPerDraw, 0.05) {
+			if n >= 35 {
+				sumTop++
+			}
+		}
+	}
+	if sumTop < 200*NumbersPerDraw*9/10 {
+		t.Errorf("low temperature picked top numbers %d times", sumTop)
+	}
+
+	// 점수가 있는 번호가 부족하면 나머지를 채움
+	nums := r.sampleNumbers(map[int]float64{1: 1, 2: 1}, NumbersPerDraw, 1)
+	if len(nums) != NumbersPerDraw {
+		t.Errorf("len = %d", len(nums))
+	}
+}
@@ -0,0 +1,180 @@
+package lotto
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// explainRecentWindow 최근 출현 횟수 집계 구간 (회차 수)
+const explainRecentWindow = 10
+
+// MethodProbability 분석기법별 번호 확률과 순위
+type MethodProbability struct {
+	Code        string  `json:"code"`
+	Probability float64 `json:"probability"`
+	Rank        int     `json:"rank"` // 해당 기법 내 순위 (1~45)
+}
+
+// NumberExplanation 추천 번호별 선택 근거
+type NumberExplanation struct {
+	Number        int                 `json:"number"`
+	Methods       []MethodProbability `json:"methods"`        // 선택한 기법별 확률
+	CombinedScore float64             `json:"combined_score"` // 조합 점수
+	Rank          int                 `json:"rank"`           // 조합 점수 순위 (1~45)
+	TotalCount    int                 `json:"total_count"`    // 역대 출현 횟수
+	RecentCount   int                 `json:"recent_count"`   // 최근 recent_window 회차 출현 횟수
+	RecentWindow  int                 `json:"recent_window"`
+	Gap           int                 `json:"gap"`     // 마지막 출현 이후 경과 회차 (미출현이면 -1)
+	AvgGap        float64             `json:"avg_gap"` // 평균 출현 간격 (회차)
+	Facts         []string            `json:"facts"`   // 사람이 읽을 수 있는 근거
+}
+
+// explainHistory 설명 생성에 필요한 최근 당첨 이력
+type explainHistory struct {
+	latestDrawNo int
+	recentWindow int
+	recentCount  map[int]int // 번호 -> 최근 구간 출현 횟수
+	lastAppear   map[int]int // 번호 -> 마지막 출현 회차
+}
+
+// loadExplainHistory 최근 당첨번호와 번호별 마지막 출현 회차 조회
+func (r *Recommender) loadExplainHistory(ctx context.Context) (*explainHistory, error) {
+	recent, err := r.repo.GetDraws(ctx, explainRecentWindow, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	lastAppear, err := r.repo.GetLastAppearDrawNos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newExplainHistory(recent, lastAppear), nil
+}
+
+// newExplainHistory 최근 당첨번호(최신순)로 설명용 이력 구성
+func newExplainHistory(recent []LottoDraw, lastAppear map[int]int) *explainHistory {
+	h := &explainHistory{
+		recentWindow: len(recent),
+		recentCount:  make(map[int]int, TotalNumbers),
+		lastAppear:   lastAppear,
+	}
+	for _, d := range recent {
+		if d.DrawNo > h.latestDrawNo {
+			h.latestDrawNo = d.DrawNo
+		}
+		for _, n := range d.Numbers() {
+			h.recentCount[n]++
+		}
+	}
+	return h
+}
+
+// buildExplanations 선택된 번호별 설명 생성
+func buildExplanations(numbers []int, codes []string, methodProbs map[string]map[int]float64,
+	scores map[int]float64, stats []AnalysisStat, history *explainHistory) []NumberExplanation {
+
+	statByNum := make(map[int]AnalysisStat, len(stats))
+	for _, s := range stats {
+		statByNum[s.Number] = s
+	}
+
+	scoreRanks := rankNumbers(scores)
+	methodRanks := make(map[string]map[int]int, len(methodProbs))
+	for code, probs := range methodProbs {
+		methodRanks[code] = rankNumbers(probs)
+	}
+
+	explanations := make([]NumberExplanation, 0, len(numbers))
+	for _, n := range numbers {
+		stat := statByNum[n]
+
+		e := NumberExplanation{
+			Number:        n,
+			Methods:       make([]MethodProbability, 0, len(codes)),
+			CombinedScore: scores[n],
+			Rank:          scoreRanks[n],
+			TotalCount:    stat.TotalCount,
+			RecentCount:   history.recentCount[n],
+			RecentWindow:  history.recentWindow,
+			Gap:           -1,
+		}
+
+		for _, code := range codes {
+			probs, ok := methodProbs[code]
+			if !ok {
+				continue
+			}
+			e.Methods = append(e.Methods, MethodProbability{
+				Code:        code,
+				Probability: probs[n],
+				Rank:        methodRanks[code][n],
+			})
+		}
+
+		if last := history.lastAppear[n]; last > 0 && history.latestDrawNo >= last {
+			e.Gap = history.latestDrawNo - last
+		}
+		if stat.TotalCount > 0 {
+			e.AvgGap = float64(stat.DrawNo) / float64(stat.TotalCount)
+		}
+
+		e.Facts = explanationFacts(e, stat.DrawNo)
+		explanations = append(explanations, e)
+	}
+
+	return explanations
+}
+
+// explanationFacts 설명 수치를 문장으로 변환
+func explanationFacts(e NumberExplanation, totalDraws int) []string {
+	facts := make([]string, 0, len(e.Methods)+4)
+
+	facts = append(facts, fmt.Sprintf("조합 점수 %d위 (45개 번호 중)", e.Rank))
+	for _, m := range e.Methods {
+		facts = append(facts, fmt.Sprintf("%s 확률 %.2f%% (%d위)", m.Code, m.Probability*100, m.Rank))
+	}
+
+	if e.RecentWindow > 0 {
+		facts = append(facts, fmt.Sprintf("최근 %d회 중 %d회 출현", e.RecentWindow, e.RecentCount))
+	}
+
+	switch {
+	case e.Gap < 0:
+		facts = append(facts, "아직 출현 기록 없음")
+	case e.Gap == 0:
+		facts = append(facts, "직전 회차에 출현")
+	case e.AvgGap > 0:
+		facts = append(facts, fmt.Sprintf("%d회차째 미출현 (평균 간격 %.1f회차)", e.Gap, e.AvgGap))
+	default:
+		facts = append(facts, fmt.Sprintf("%d회차째 미출현", e.Gap))
+	}
+
+	if totalDraws > 0 {
+		expected := float64(totalDraws*NumbersPerDraw) / TotalNumbers
+		facts = append(facts, fmt.Sprintf("역대 %d회 출현 (기대값 %.1f회)", e.TotalCount, expected))
+	}
+
+	return facts
+}
+
+// rankNumbers 점수 내림차순 순위 (동점은 작은 번호 우선)
+func rankNumbers(scores map[int]float64) map[int]int {
+	nums := make([]int, 0, len(scores))
+	for n := range scores {
+		nums = append(nums, n)
+	}
+	sort.Slice(nums, func(i, j int) bool {
+		if scores[nums[i]] == scores[nums[j]] {
+			return nums[i] < nums[j]
+		}
+		return scores[nums[i]] > scores[nums[j]]
+	})
+
+	ranks := make(map[int]int, len(nums))
+	for i, n := range nums {
+		ranks[n] = i + 1
+	}
+	return ranks
+}
@@ -0,0 +1,80 @@
+package lotto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRankNumbers(t *testing.T) {
+	ranks := rankNumbers(map[int]float64{1: 0.1, 2: 0.3, 3: 0.3, 4: 0.05})
+
+	want := map[int]int{2: 1, 3: 2, 1: 3, 4: 4}
+	for n, r := range want {
+		if ranks[n] != r {
+			t.Errorf("number %d: rank %d, want %d", n, ranks[n], r)
+		}
+	}
+}
+
+func TestBuildExplanations(t *testing.T) {
+	stats := makeTestStats()
+	for i := range stats {
+		stats[i].DrawNo = 100
+		stats[i].TotalCount = 10
+	}
+
+	recent := []LottoDraw{
+		*makeTestDraw(100, 7, 11, 20, 30, 40, 45),
+		*makeTestDraw(99, 7, 12, 21, 31, 41, 44),
+		*makeTestDraw(98, 1, 7, 22, 32, 42, 43),
+	}
+	history := newExplainHistory(recent, map[int]int{7: 100, 45: 86})
+
+	r := &Recommender{}
+	codes := []string{"NUMBER_FREQUENCY", "REAPPEAR_PROB"}
+	methodProbs := map[string]map[int]float64{
+		"NUMBER_FREQUENCY": r.getMethodProbabilities("NUMBER_FREQUENCY", stats),
+		"REAPPEAR_PROB":    r.getMethodProbabilities("REAPPEAR_PROB", stats),
+	}
+	scores := r.combineSimpleAverage([]map[int]float64{methodProbs["NUMBER_FREQUENCY"], methodProbs["REAPPEAR_PROB"]})
+
+	explanations := buildExplanations([]int{7, 45, 3}, codes, methodProbs, scores, stats, history)
+	if len(explanations) != 3 {
+		t.Fatalf("expected 3 explanations, got %d", len(explanations))
+	}
+
+	e7 := explanations[0]
+	if e7.RecentCount != 3 || e7.RecentWindow != 3 {
+		t.Errorf("number 7: recent %d/%d, want 3/3", e7.RecentCount, e7.RecentWindow)
+	}
+	if e7.Gap != 0 {
+		t.Errorf("number 7: gap %d, want 0", e7.Gap)
+	}
+	if len(e7.Methods) != 2 || e7.Methods[0].Code != "NUMBER_FREQUENCY" {
+		t.Errorf("number 7: unexpected methods %+v", e7.Methods)
+	}
+	if e7.Methods[1].Rank != 7 { // ReappearProb (46-n)/1000 내림차순 -> 7위
+		t.Errorf("number 7: REAPPEAR_PROB rank %d, want 7", e7.Methods[1].Rank)
+	}
+
+	e45 := explanations[1]
+	if e45.Gap != 14 {
+		t.Errorf("number 45: gap %d, want 14", e45.Gap)
+	}
+	if e45.AvgGap != 10 {
+		t.Errorf("number 45: avg gap %.1f, want 10", e45.AvgGap)
+	}
+	found := false
+	for _, f := range e45.Facts {
+		if strings.Contains(f, "14회차째 미출현") && strings.Contains(f, "10.0") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("number 45: gap fact missing: %v", e45.Facts)
+	}
+
+	if explanations[2].Gap != -1 {
+		t.Errorf("number 3: gap %d, want -1 (no record)", explanations[2].Gap)
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/example/LottoSmash/internal/auth"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -273,6 +276,27 @@ func (h *Handler) GetAnalysisStats(w http.ResponseWriter, r *http.Request) {
 	h.jsonResponse(w, http.StatusOK, stats)
 }
 
+// GetDecayStats GET /api/lotto/stats/decay?half_life=52
+func (h *Handler) GetDecayStats(w http.ResponseWriter, r *http.Request) {
+	halfLife := DefaultDecayHalfLife
+	if hl := r.URL.Query().Get("half_life"); hl != "" {
+		v, err := strconv.Atoi(hl)
+		if err != nil || v < 0 || v > MaxDecayHalfLife {
+			h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("half_life must be between 0 and %d (0 = default)", MaxDecayHalfLife))
+			return
+		}
+		halfLife = v
+	}
+
+	stats, err := h.service.GetDecayStats(r.Context(), halfLife)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, stats)
+}
+
 // GetAnalysisStatsByDrawNo GET /api/lotto/stats/analysis/{drawNo}
 func (h *Handler) GetAnalysisStatsByDrawNo(w http.ResponseWriter, r *http.Request) {
 	drawNoStr := r.PathValue("drawNo")
@@ -354,7 +378,7 @@ func (h *Handler) GetMethods(w http.ResponseWriter, r *http.Request) {
 	h.jsonResponse(w, http.StatusOK, resp)
 }
 
-// RecommendNumbers POST /api/lotto/recommend
+// RecommendNumbers POST /api/lotto/recommend?explain=true
 func (h *Handler) RecommendNumbers(w http.ResponseWriter, r *http.Request) {
 	var req RecommendRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -362,6 +386,101 @@ func (h *Handler) RecommendNumbers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// ?explain=true 쿼리로도 번호별 설명 요청 가능
+	if e := r.URL.Query().Get("explain"); e != "" {
+		if v, err := strconv.ParseBool(e); err == nil {
+			req.Explain = v
+		}
+	}
+
+	// 인증된 사용자인 경우 추천 기록을 사용자 기록으로 저장
+	var userID *int64
+	if id, ok := auth.GetUserID(r.Context()); ok {
+		userID = &id
+	}
+
+	// A/B 실험 변형 기본값을 먼저 채운 뒤 검증 (변형에 기본 기법이 있으면 method_codes 생략 가능)
+	assignment := h.service.AssignExperiment(r.Context(), userID)
+	assignment.applyDefaults(&req)
+
+	if err := validateRecommendRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.RecommendNumbers(r.Context(), req, userID, assignment)
+	if errors.Is(err, ErrInvalidMethodSelection) {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// GetRecommendationHistory GET /api/lotto/recommendations?from=2025-01-01&to=2025-01-31&method_code=BAYESIAN&combine_method=SIMPLE_AVG&limit=20&offset=0
+// from/to는 생성일 기준 (YYYY-MM-DD, to 포함)
+func (h *Handler) GetRecommendationHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := RecommendationFilter{
+		Limit:         20,
+		MethodCode:    strings.ToUpper(strings.TrimSpace(q.Get("method_code"))),
+		CombineMethod: strings.ToUpper(strings.TrimSpace(q.Get("combine_method"))),
+	}
+
+	if l := q.Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 100 {
+			filter.Limit = v
+		}
+	}
+	if o := q.Get("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
+			filter.Offset = v
+		}
+	}
+
+	if from := q.Get("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			h.errorResponse(w, http.StatusBadRequest, "from must be YYYY-MM-DD")
+			return
+		}
+		filter.From = &t
+	}
+	if to := q.Get("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			h.errorResponse(w, http.StatusBadRequest, "to must be YYYY-MM-DD")
+			return
+		}
+		t = t.AddDate(0, 0, 1)
+		filter.To = &t
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		h.errorResponse(w, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+
+	resp, err := h.service.GetRecommendationHistory(r.Context(), userID, filter)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// validateRecommendRequest 추천 요청 기본값 설정 및 유효성 검사
+func validateRecommendRequest(req *RecommendRequest) error {
 	// 기본값 설정
 	if req.Count <= 0 {
 		req.Count = 1
@@ -370,21 +489,22 @@ func (h *Handler) RecommendNumbers(w http.ResponseWriter, r *http.Request) {
 		req.Count = 10
 	}
 
-	// 유효성 검사
+	return validateRecommendOptions(req)
+}
+
+// validateRecommendOptions 세트 개수 외 추천 옵션 유효성 검사 (대량 작업과 공용)
+func validateRecommendOptions(req *RecommendRequest) error {
 	if len(req.MethodCodes) == 0 {
-		h.errorResponse(w, http.StatusBadRequest, "at least one method_code is required")
-		return
+		return errors.New("at least one method_code is required")
 	}
 	if len(req.MethodCodes) > MaxMethodCodes {
-		h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("maximum %d method_codes allowed", MaxMethodCodes))
-		return
+		return fmt.Errorf("maximum %d method_codes allowed", MaxMethodCodes)
 	}
 
 	// 가중 평균 선택 시 가중치 검증
 	if req.CombineCode == CombineWeightedAvg {
 		if len(req.Weights) == 0 {
-			h.errorResponse(w, http.StatusBadRequest, "weights are required for WEIGHTED_AVG combine method")
-			return
+			return errors.New("weights are required for WEIGHTED_AVG combine method")
 		}
 		// method_codes에 대응하는 가중치가 있는지 확인
 		methodSet := make(map[string]bool)
@@ -393,28 +513,107 @@ func (h *Handler) RecommendNumbers(w http.ResponseWriter, r *http.Request) {
 		}
 		for key, val := range req.Weights {
 			if !methodSet[key] {
-				h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("weight key '%s' does not match any method_code", key))
-				return
+				return fmt.Errorf("weight key '%s' does not match any method_code", key)
 			}
 			if val <= 0 {
-				h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("weight for '%s' must be greater than 0", key))
-				return
+				return fmt.Errorf("weight for '%s' must be greater than 0", key)
 			}
 		}
 	}
 
+	// 시간 감쇠 반감기 검증
+	if req.HalfLife < 0 || req.HalfLife > MaxDecayHalfLife {
+		return fmt.Errorf("half_life must be between 0 and %d (0 = default)", MaxDecayHalfLife)
+	}
+
+	// 추천 모드 검증
+	if req.Mode != "" && req.Mode != RecommendModeTop && req.Mode != RecommendModeOptimize {
+		return errors.New("mode must be 'TOP' or 'OPTIMIZE'")
+	}
+	if req.Objectives != nil && !req.Objectives.Validate() {
+		return errors.New("objectives weights must be non-negative with a positive sum")
+	}
+
+	// 샘플링 온도 검증
+	if req.Temperature < 0 || req.Temperature > MaxSamplingTemperature {
+		return fmt.Errorf("temperature must be between 0 and %g", MaxSamplingTemperature)
+	}
+
 	// 최대/최소 선택 시 모드 검증
 	if req.CombineCode == CombineMinMax && req.MinMaxMode != "" {
 		if req.MinMaxMode != "MAX" && req.MinMaxMode != "MIN" {
-			h.errorResponse(w, http.StatusBadRequest, "min_max_mode must be 'MAX' or 'MIN'")
+			return errors.New("min_max_mode must be 'MAX' or 'MIN'")
+		}
+	}
+
+	return nil
+}
+
+// ScoreTickets POST /api/lotto/score
+func (h *Handler) ScoreTickets(w http.ResponseWriter, r *http.Request) {
+	var req ScoreTicketsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(req.Tickets) == 0 {
+		h.errorResponse(w, http.StatusBadRequest, "at least one ticket is required")
+		return
+	}
+	if len(req.Tickets) > MaxScoreTickets {
+		h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("maximum %d tickets allowed", MaxScoreTickets))
+		return
+	}
+	for i, ticket := range req.Tickets {
+		if err := ValidateTicket(ticket); err != nil {
+			h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("ticket %d: %v", i+1, err))
+			return
+		}
+	}
+	if req.HalfLife < 0 || req.HalfLife > MaxDecayHalfLife {
+		h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("half_life must be between 0 and %d (0 = default)", MaxDecayHalfLife))
+		return
+	}
+
+	resp, err := h.service.ScoreTickets(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMethodSelection) {
+			h.errorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// ReplayTickets POST /api/lotto/replay
+// 조합별로 역대 전 회차 당첨 내역과 1회차부터 매주 구매했을 때의 수익률 반환
+func (h *Handler) ReplayTickets(w http.ResponseWriter, r *http.Request) {
+	var req ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
 	}
 
-	// TODO: 인증된 사용자인 경우 userID 추출
-	var userID *int64 = nil
+	if len(req.Tickets) == 0 {
+		h.errorResponse(w, http.StatusBadRequest, "at least one ticket is required")
+		return
+	}
+	if len(req.Tickets) > MaxReplayTickets {
+		h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("maximum %d tickets allowed", MaxReplayTickets))
+		return
+	}
+	for i, ticket := range req.Tickets {
+		if err := ValidateTicket(ticket); err != nil {
+			h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("ticket %d: %v", i+1, err))
+			return
+		}
+	}
 
-	resp, err := h.service.RecommendNumbers(r.Context(), req, userID)
+	resp, err := h.service.ReplayTickets(r.Context(), req)
 	if err != nil {
 		h.errorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -429,6 +628,775 @@ func (h *Handler) GetCombineMethods(w http.ResponseWriter, r *http.Request) {
 	h.jsonResponse(w, http.StatusOK, resp)
 }
 
+// TrainMLModel POST /api/admin/lotto/ml/train
+func (h *Handler) TrainMLModel(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MLTrainConfig
+		Activate *bool `json:"activate,omitempty"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	// 기본값: 학습 후 바로 활성화
+	activate := true
+	if req.Activate != nil {
+		activate = *req.Activate
+	}
+
+	model, err := h.service.TrainMLModel(r.Context(), req.MLTrainConfig, activate)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, model)
+}
+
+// GetMLModels GET /api/admin/lotto/ml/models?limit=20
+func (h *Handler) GetMLModels(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 100 {
+			limit = v
+		}
+	}
+
+	resp, err := h.service.GetMLModels(r.Context(), limit)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// ActivateMLModel POST /api/admin/lotto/ml/models/{version}/activate
+func (h *Handler) ActivateMLModel(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		h.errorResponse(w, http.StatusBadRequest, "invalid model version")
+		return
+	}
+
+	err = h.service.ActivateMLModel(r.Context(), version)
+	if errors.Is(err, ErrMLModelNotFound) {
+		h.errorResponse(w, http.StatusNotFound, "ml model not found")
+		return
+	}
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("ml model v%d activated", version),
+	})
+}
+
+// GetMethodRegistry GET /api/admin/lotto/methods
+func (h *Handler) GetMethodRegistry(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.service.GetMethodRegistry(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// ReloadMethodRegistry POST /api/admin/lotto/methods/reload
+func (h *Handler) ReloadMethodRegistry(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.service.ReloadMethodRegistry(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// CreateMethod POST /api/admin/lotto/methods/{kind}
+func (h *Handler) CreateMethod(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+
+	var req MethodCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateMethodCreateRequest(kind, &req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.CreateMethod(r.Context(), kind, req)
+	if err != nil {
+		h.methodError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusCreated, resp)
+}
+
+// UpdateMethod PUT /api/admin/lotto/methods/{kind}/{code}
+func (h *Handler) UpdateMethod(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	code := strings.ToUpper(chi.URLParam(r, "code"))
+
+	var req MethodUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateMethodUpdateRequest(kind, &req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.UpdateMethod(r.Context(), kind, code, req)
+	if err != nil {
+		h.methodError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// ReorderMethods PUT /api/admin/lotto/methods/{kind}/order
+func (h *Handler) ReorderMethods(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+
+	var req MethodOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateMethodOrderRequest(kind, &req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.ReorderMethods(r.Context(), kind, req)
+	if err != nil {
+		h.methodError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// DeleteMethod DELETE /api/admin/lotto/methods/{kind}/{code}
+func (h *Handler) DeleteMethod(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	if err := validateMethodKind(kind); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.DeleteMethod(r.Context(), kind, strings.ToUpper(chi.URLParam(r, "code")))
+	if err != nil {
+		h.methodError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// methodError 기법 관리 오류를 HTTP 상태로 변환
+func (h *Handler) methodError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrMethodNotFound):
+		h.errorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrMethodDuplicate):
+		h.errorResponse(w, http.StatusConflict, err.Error())
+	default:
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// ========================================
+// 추천 A/B 실험
+// ========================================
+
+// GetMyExperiment GET /api/lotto/experiment
+// 배정된 실험 변형과 UI 플래그 (진행 중 실험이 없으면 experiment: null)
+func (h *Handler) GetMyExperiment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"experiment": h.service.AssignExperiment(r.Context(), &userID),
+	})
+}
+
+// GetExperiments GET /api/admin/lotto/experiments
+func (h *Handler) GetExperiments(w http.ResponseWriter, r *http.Request) {
+	experiments, err := h.service.GetExperiments(r.Context())
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, experiments)
+}
+
+// CreateExperiment POST /api/admin/lotto/experiments
+func (h *Handler) CreateExperiment(w http.ResponseWriter, r *http.Request) {
+	var req ExperimentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateExperimentRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	exp, err := h.service.CreateExperiment(r.Context(), req)
+	if err != nil {
+		h.experimentError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusCreated, exp)
+}
+
+// GetExperiment GET /api/admin/lotto/experiments/{id}
+func (h *Handler) GetExperiment(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.experimentID(w, r)
+	if !ok {
+		return
+	}
+
+	exp, err := h.service.GetExperiment(r.Context(), id)
+	if err != nil {
+		h.experimentError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, exp)
+}
+
+// UpdateExperiment PUT /api/admin/lotto/experiments/{id} (DRAFT 상태만)
+func (h *Handler) UpdateExperiment(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.experimentID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ExperimentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateExperimentRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	exp, err := h.service.UpdateExperiment(r.Context(), id, req)
+	if err != nil {
+		h.experimentError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, exp)
+}
+
+// StartExperiment POST /api/admin/lotto/experiments/{id}/start
+func (h *Handler) StartExperiment(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.experimentID(w, r)
+	if !ok {
+		return
+	}
+
+	exp, err := h.service.StartExperiment(r.Context(), id)
+	if err != nil {
+		h.experimentError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, exp)
+}
+
+// StopExperiment POST /api/admin/lotto/experiments/{id}/stop
+func (h *Handler) StopExperiment(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.experimentID(w, r)
+	if !ok {
+		return
+	}
+
+	exp, err := h.service.StopExperiment(r.Context(), id)
+	if err != nil {
+		h.experimentError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, exp)
+}
+
+// GetExperimentReport GET /api/admin/lotto/experiments/{id}/report
+func (h *Handler) GetExperimentReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.experimentID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetExperimentReport(r.Context(), id)
+	if err != nil {
+		h.experimentError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, report)
+}
+
+// experimentID 실험 ID 경로 파라미터 추출 (실패 시 응답 작성)
+func (h *Handler) experimentID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id < 1 {
+		h.errorResponse(w, http.StatusBadRequest, "invalid experiment id")
+		return 0, false
+	}
+	return id, true
+}
+
+// experimentError 실험 오류를 HTTP 상태로 변환
+func (h *Handler) experimentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrExperimentNotFound):
+		h.errorResponse(w, http.StatusNotFound, "experiment not found")
+	case errors.Is(err, ErrExperimentDuplicate), errors.Is(err, ErrExperimentConflict):
+		h.errorResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidMethodSelection):
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// ========================================
+// 추천 전략 프리셋 (인증 필요)
+// ========================================
+
+// GetPresets GET /api/lotto/presets
+func (h *Handler) GetPresets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	presets, err := h.service.GetPresets(r.Context(), userID)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, presets)
+}
+
+// CreatePreset POST /api/lotto/presets
+func (h *Handler) CreatePreset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req PresetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validatePresetRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preset, err := h.service.CreatePreset(r.Context(), userID, req)
+	if errors.Is(err, ErrPresetLimitExceeded) {
+		h.errorResponse(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusCreated, preset)
+}
+
+// GetPreset GET /api/lotto/presets/{id}
+func (h *Handler) GetPreset(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.presetParams(w, r)
+	if !ok {
+		return
+	}
+
+	preset, err := h.service.GetPreset(r.Context(), userID, id)
+	if err != nil {
+		h.presetError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, preset)
+}
+
+// UpdatePreset PUT /api/lotto/presets/{id}
+func (h *Handler) UpdatePreset(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.presetParams(w, r)
+	if !ok {
+		return
+	}
+
+	var req PresetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validatePresetRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preset, err := h.service.UpdatePreset(r.Context(), userID, id, req)
+	if err != nil {
+		h.presetError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, preset)
+}
+
+// DeletePreset DELETE /api/lotto/presets/{id}
+func (h *Handler) DeletePreset(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.presetParams(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePreset(r.Context(), userID, id); err != nil {
+		h.presetError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, map[string]string{"message": "preset deleted"})
+}
+
+// RunPreset POST /api/lotto/presets/{id}/run
+func (h *Handler) RunPreset(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.presetParams(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.RunPreset(r.Context(), userID, id)
+	if err != nil {
+		h.presetError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// presetParams 인증 사용자 ID와 프리셋 ID 추출 (실패 시 응답 작성)
+func (h *Handler) presetParams(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id < 1 {
+		h.errorResponse(w, http.StatusBadRequest, "invalid preset id")
+		return 0, 0, false
+	}
+	return userID, id, true
+}
+
+func (h *Handler) presetError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrPresetNotFound) {
+		h.errorResponse(w, http.StatusNotFound, "preset not found")
+		return
+	}
+	if errors.Is(err, ErrInvalidMethodSelection) {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.errorResponse(w, http.StatusInternalServerError, err.Error())
+}
+
+// ========================================
+// 구매 용지 지갑 (인증 필요)
+// ========================================
+
+// GetTickets GET /api/lotto/tickets?limit=20&offset=0
+func (h *Handler) GetTickets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	limit := 20
+	offset := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 100 {
+			limit = v
+		}
+	}
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
+			offset = v
+		}
+	}
+
+	resp, err := h.service.GetTickets(r.Context(), userID, limit, offset)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// CreateTicket POST /api/lotto/tickets
+func (h *Handler) CreateTicket(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateTicketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateTicketGames(req.Games); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if nextDrawNo := NextDrawNo(time.Now()); req.DrawNo < 0 || req.DrawNo > nextDrawNo {
+		h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("draw_no must be between 0 (current) and %d", nextDrawNo))
+		return
+	}
+
+	ticket, err := h.service.CreateTicket(r.Context(), userID, req)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusCreated, ticket)
+}
+
+// ImportTicketQR POST /api/lotto/tickets/qr
+func (h *Handler) ImportTicketQR(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ImportTicketQRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.URL) == "" {
+		h.errorResponse(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	ticket, err := h.service.ImportTicketQR(r.Context(), userID, req.URL)
+	switch {
+	case errors.Is(err, ErrInvalidTicketQR):
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, ErrTicketDuplicate):
+		h.errorResponse(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusCreated, ticket)
+}
+
+// DeleteTicket DELETE /api/lotto/tickets/{id}
+func (h *Handler) DeleteTicket(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id < 1 {
+		h.errorResponse(w, http.StatusBadRequest, "invalid ticket id")
+		return
+	}
+
+	err = h.service.DeleteTicket(r.Context(), userID, id)
+	if errors.Is(err, ErrTicketNotFound) {
+		h.errorResponse(w, http.StatusNotFound, "ticket not found")
+		return
+	}
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, map[string]string{"message": "ticket deleted"})
+}
+
+// ========================================
+// 대량 추천 작업 (인증 필요)
+// ========================================
+
+// SubmitBulkJob POST /api/lotto/jobs
+func (h *Handler) SubmitBulkJob(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req BulkJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateBulkJobRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := h.service.SubmitBulkJob(r.Context(), userID, req)
+	if err != nil {
+		h.bulkJobError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusAccepted, job)
+}
+
+// GetBulkJobs GET /api/lotto/jobs
+func (h *Handler) GetBulkJobs(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	jobs, err := h.service.GetBulkJobs(userID)
+	if err != nil {
+		h.bulkJobError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, jobs)
+}
+
+// GetBulkJob GET /api/lotto/jobs/{id}
+func (h *Handler) GetBulkJob(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	job, err := h.service.GetBulkJob(userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.bulkJobError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, job)
+}
+
+// GetBulkJobResult GET /api/lotto/jobs/{id}/result?format=json|csv
+func (h *Handler) GetBulkJobResult(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		h.errorResponse(w, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	result, err := h.service.GetBulkJobResult(userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.bulkJobError(w, err)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="lotto-job-%s.csv"`, result.JobID))
+		w.WriteHeader(http.StatusOK)
+		// 헤더를 이미 보냈으므로 오류 응답 대신 기록만 남김
+		if err := writeBulkJobCSV(w, result); err != nil {
+			h.service.log.Warnf("failed to write bulk job %s csv: %v", result.JobID, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="lotto-job-%s.json"`, result.JobID))
+	h.jsonResponse(w, http.StatusOK, result)
+}
+
+// CancelBulkJob POST /api/lotto/jobs/{id}/cancel
+func (h *Handler) CancelBulkJob(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.GetUserID(r.Context())
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	job, err := h.service.CancelBulkJob(userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.bulkJobError(w, err)
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, job)
+}
+
+// bulkJobError 대량 작업 오류를 HTTP 상태로 변환
+func (h *Handler) bulkJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrBulkJobNotFound):
+		h.errorResponse(w, http.StatusNotFound, "job not found")
+	case errors.Is(err, ErrBulkJobNotReady):
+		h.errorResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrBulkJobLimitExceeded):
+		h.errorResponse(w, http.StatusTooManyRequests,
+			fmt.Sprintf("maximum %d active jobs per user", MaxBulkJobsPerUser))
+	case errors.Is(err, ErrBulkJobQueueFull), errors.Is(err, ErrBulkJobsUnavailable):
+		h.errorResponse(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, ErrInvalidMethodSelection):
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		h.errorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 func (h *Handler) jsonResponse(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
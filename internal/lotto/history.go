@@ -0,0 +1,93 @@
+package lotto
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 추천 결과 상태
+const (
+	RecommendationPending = "PENDING" // 추첨 전 (당첨 확인 전)
+	RecommendationSettled = "SETTLED" // 당첨 확인 완료
+)
+
+// RecommendationFilter 추천 기록 조회 조건
+type RecommendationFilter struct {
+	From          *time.Time // 생성일 시작 (포함)
+	To            *time.Time // 생성일 종료 (제외)
+	MethodCode    string     // 사용한 분석 기법 코드
+	CombineMethod string     // 조합 방법 코드
+	Limit         int
+	Offset        int
+}
+
+// RecommendationOutcome 추천 번호의 당첨 확인 결과
+type RecommendationOutcome struct {
+	DrawNo         int       `json:"draw_no"`
+	MatchedNumbers []int     `json:"matched_numbers"`
+	MatchedCount   int       `json:"matched_count"`
+	BonusMatched   bool      `json:"bonus_matched"`
+	PrizeRank      *int      `json:"prize_rank,omitempty"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// RecommendationHistoryItem 추천 기록과 당첨 결과
+type RecommendationHistoryItem struct {
+	LottoRecommendation
+	Status  string                 `json:"status"`            // PENDING / SETTLED
+	Outcome *RecommendationOutcome `json:"outcome,omitempty"` // 당첨 확인 후에만 포함
+}
+
+// RecommendationHistoryResponse 추천 기록 목록 응답
+type RecommendationHistoryResponse struct {
+	Recommendations []RecommendationHistoryItem `json:"recommendations"`
+	TotalCount      int                         `json:"total_count"`
+}
+
+// newRecommendationOutcome winning_checks LEFT JOIN 결과로 당첨 결과 구성
+func newRecommendationOutcome(drawNo sql.NullInt64, matched []int64, matchedCount sql.NullInt64,
+	bonusMatched sql.NullBool, prizeRank sql.NullInt64, checkedAt sql.NullTime) *RecommendationOutcome {
+
+	outcome := &RecommendationOutcome{
+		DrawNo:         int(drawNo.Int64),
+		MatchedNumbers: make([]int, 0, len(matched)),
+		MatchedCount:   int(matchedCount.Int64),
+		BonusMatched:   bonusMatched.Bool,
+		CheckedAt:      checkedAt.Time,
+	}
+	for _, n := range matched {
+		outcome.MatchedNumbers = append(outcome.MatchedNumbers, int(n))
+	}
+	if prizeRank.Valid {
+		rank := int(prizeRank.Int64)
+		outcome.PrizeRank = &rank
+	}
+	return outcome
+}
+
+// whereClause 사용자 ID와 필터 조건으로 WHERE 절과 인자 생성 (r = lotto_recommendations 별칭)
+func (f RecommendationFilter) whereClause(userID int64) (string, []interface{}) {
+	conds := []string{"r.user_id = $1"}
+	args := []interface{}{userID}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.From != nil {
+		add("r.created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("r.created_at < $%d", *f.To)
+	}
+	if f.MethodCode != "" {
+		add("$%d = ANY(r.method_codes)", f.MethodCode)
+	}
+	if f.CombineMethod != "" {
+		add("r.combine_method = $%d", f.CombineMethod)
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,50 @@
+package lotto
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecommendationFilterWhereClause(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    RecommendationFilter
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "user only",
+			filter:    RecommendationFilter{},
+			wantWhere: "WHERE r.user_id = $1",
+			wantArgs:  []interface{}{int64(7)},
+		},
+		{
+			name:      "all filters",
+			filter:    RecommendationFilter{From: &from, To: &to, MethodCode: "BAYESIAN", CombineMethod: CombineMinMax},
+			wantWhere: "WHERE r.user_id = $1 AND r.created_at >= $2 AND r.created_at < $3 AND $4 = ANY(r.method_codes) AND r.combine_method = $5",
+			wantArgs:  []interface{}{int64(7), from, to, "BAYESIAN", CombineMinMax},
+		},
+		{
+			name:      "method only",
+			filter:    RecommendationFilter{MethodCode: "ML_MODEL"},
+			wantWhere: "WHERE r.user_id = $1 AND $2 = ANY(r.method_codes)",
+			wantArgs:  []interface{}{int64(7), "ML_MODEL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.filter.whereClause(7)
+			if where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
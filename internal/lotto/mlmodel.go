@@ -0,0 +1,365 @@
+package lotto
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// ML 모델 관련 상수
+const (
+	MethodMLModel = "ML_MODEL"
+
+	MLAlgorithmLogistic = "LOGISTIC"
+
+	defaultMLEpochs       = 200
+	defaultMLLearningRate = 0.05
+	defaultMLL2           = 0.001
+	defaultMLBatchSize    = 256
+	defaultMLHoldoutRatio = 0.1
+)
+
+// mlFeatureNames 모델 입력 특징 이름 (순서가 가중치 인덱스와 일치해야 함)
+var mlFeatureNames = []string{
+	"total_prob",
+	"bonus_prob",
+	"first_prob",
+	"last_prob",
+	"reappear_prob",
+	"bayesian_post",
+	"color_prob",
+	"row_prob",
+	"col_prob",
+	"gap",
+	"appeared",
+}
+
+// MLModel 번호별 다음 회차 출현 확률 예측 모델 (로지스틱 회귀)
+// 특징은 학습 데이터의 평균/표준편차로 표준화한 뒤 선형 결합한다.
+type MLModel struct {
+	Version       int       `json:"version"`
+	Algorithm     string    `json:"algorithm"`
+	FeatureNames  []string  `json:"feature_names"`
+	Means         []float64 `json:"means"`
+	Stds          []float64 `json:"stds"`
+	Weights       []float64 `json:"weights"`
+	Bias          float64   `json:"bias"`
+	TrainedDrawNo int       `json:"trained_draw_no"` // 학습에 사용된 마지막 회차 (정답 라벨 포함)
+	Metrics       MLMetrics `json:"metrics"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// MLMetrics 학습 결과 지표
+type MLMetrics struct {
+	SampleCount     int     `json:"sample_count"`      // 학습 샘플 수
+	HoldoutCount    int     `json:"holdout_count"`     // 검증 샘플 수
+	Epochs          int     `json:"epochs"`            // 학습 반복 횟수
+	LogLoss         float64 `json:"log_loss"`          // 검증 구간 log loss
+	BaselineLogLoss float64 `json:"baseline_log_loss"` // 균등 확률(6/45) 기준 log loss
+}
+
+// MLTrainConfig 학습 하이퍼파라미터
+type MLTrainConfig struct {
+	Epochs       int     `json:"epochs"`
+	LearningRate float64 `json:"learning_rate"`
+	L2           float64 `json:"l2"`
+	BatchSize    int     `json:"batch_size"`
+	HoldoutRatio float64 `json:"holdout_ratio"` // 최근 회차 중 검증에 사용할 비율
+	Seed         int64   `json:"seed,omitempty"`
+}
+
+// withDefaults 비어있는 하이퍼파라미터를 기본값으로 채움
+func (c MLTrainConfig) withDefaults() MLTrainConfig {
+	if c.Epochs <= 0 {
+		c.Epochs = defaultMLEpochs
+	}
+	if c.LearningRate <= 0 {
+		c.LearningRate = defaultMLLearningRate
+	}
+	if c.L2 <= 0 {
+		c.L2 = defaultMLL2
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultMLBatchSize
+	}
+	if c.HoldoutRatio <= 0 || c.HoldoutRatio >= 1 {
+		c.HoldoutRatio = defaultMLHoldoutRatio
+	}
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+	return c
+}
+
+// MLModelListResponse 모델 목록 응답
+type MLModelListResponse struct {
+	Models     []MLModel `json:"models"`
+	TotalCount int       `json:"total_count"`
+}
+
+// mlSample 학습 샘플 (회차 d의 번호 n 특징 -> d+1 회차 출현 여부)
+type mlSample struct {
+	DrawNo   int
+	Features []float64
+	Label    float64
+}
+
+// mlFeatures AnalysisStat과 미출현 간격으로 특징 벡터 생성
+func mlFeatures(s AnalysisStat, gap int) []float64 {
+	appeared := 0.0
+	if s.Appeared {
+		appeared = 1.0
+	}
+	return []float64{
+		s.TotalProb,
+		s.BonusProb,
+		s.FirstProb,
+		s.LastProb,
+		s.ReappearProb,
+		s.BayesianPost,
+		s.ColorProb,
+		s.RowProb,
+		s.ColProb,
+		float64(gap),
+		appeared,
+	}
+}
+
+// buildMLSamples 회차별 통합 분석 통계로 학습 샘플 생성
+// stats는 회차/번호 오름차순이어야 하며, 마지막 회차는 정답이 없으므로 제외된다.
+func buildMLSamples(stats []AnalysisStat) []mlSample {
+	byDraw := make(map[int][]AnalysisStat)
+	drawNos := make([]int, 0)
+	for _, s := range stats {
+		if _, ok := byDraw[s.DrawNo]; !ok {
+			drawNos = append(drawNos, s.DrawNo)
+		}
+		byDraw[s.DrawNo] = append(byDraw[s.DrawNo], s)
+	}
+	sort.Ints(drawNos)
+
+	lastAppear := make(map[int]int, TotalNumbers)
+	samples := make([]mlSample, 0, len(stats))
+
+	for i, drawNo := range drawNos {
+		for _, s := range byDraw[drawNo] {
+			if s.Appeared {
+				lastAppear[s.Number] = drawNo
+			}
+		}
+
+		// 다음 회차가 연속으로 존재해야 정답 라벨 생성 가능
+		if i+1 >= len(drawNos) || drawNos[i+1] != drawNo+1 {
+			continue
+		}
+		nextAppeared := make(map[int]bool, NumbersPerDraw)
+		for _, s := range byDraw[drawNo+1] {
+			if s.Appeared {
+				nextAppeared[s.Number] = true
+			}
+		}
+
+		for _, s := range byDraw[drawNo] {
+			label := 0.0
+			if nextAppeared[s.Number] {
+				label = 1.0
+			}
+			samples = append(samples, mlSample{
+				DrawNo:   drawNo,
+				Features: mlFeatures(s, drawNo-lastAppear[s.Number]),
+				Label:    label,
+			})
+		}
+	}
+
+	return samples
+}
+
+// TrainLogisticModel 미니배치 경사하강법으로 로지스틱 회귀 모델 학습
+// 최근 회차 HoldoutRatio 비율은 학습에서 제외하고 검증 log loss 계산에 사용한다.
+func TrainLogisticModel(stats []AnalysisStat, cfg MLTrainConfig) (*MLModel, error) {
+	cfg = cfg.withDefaults()
+
+	samples := buildMLSamples(stats)
+	if len(samples) < TotalNumbers*10 {
+		return nil, fmt.Errorf("not enough training samples: %d", len(samples))
+	}
+
+	// 회차 기준으로 학습/검증 분리 (시계열이므로 최근 회차를 검증에 사용)
+	firstDraw := samples[0].DrawNo
+	lastDraw := samples[len(samples)-1].DrawNo
+	splitDraw := lastDraw - int(float64(lastDraw-firstDraw+1)*cfg.HoldoutRatio)
+
+	var train, holdout []mlSample
+	for _, s := range samples {
+		if s.DrawNo > splitDraw {
+			holdout = append(holdout, s)
+		} else {
+			train = append(train, s)
+		}
+	}
+	if len(train) == 0 {
+		return nil, fmt.Errorf("empty training set")
+	}
+
+	featureCount := len(mlFeatureNames)
+	means, stds := featureMoments(train, featureCount)
+
+	model := &MLModel{
+		Algorithm:     MLAlgorithmLogistic,
+		FeatureNames:  append([]string(nil), mlFeatureNames...),
+		Means:         means,
+		Stds:          stds,
+		Weights:       make([]float64, featureCount),
+		TrainedDrawNo: lastDraw + 1, // 마지막 샘플의 정답(다음 회차 출현)까지 학습에 사용
+	}
+
+	// 편향 초기값: 기저 출현율의 로짓
+	baseRate := float64(NumbersPerDraw) / float64(TotalNumbers)
+	model.Bias = math.Log(baseRate / (1 - baseRate))
+
+	// 표준화된 특징 미리 계산
+	xs := make([][]float64, len(train))
+	for i, s := range train {
+		xs[i] = model.standardize(s.Features)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	order := make([]int, len(train))
+	for i := range order {
+		order[i] = i
+	}
+
+	grad := make([]float64, featureCount)
+	for epoch := 0; epoch < cfg.Epochs; epoch++ {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		for start := 0; start < len(order); start += cfg.BatchSize {
+			end := start + cfg.BatchSize
+			if end > len(order) {
+				end = len(order)
+			}
+
+			for k := range grad {
+				grad[k] = 0
+			}
+			gradBias := 0.0
+
+			for _, idx := range order[start:end] {
+				x := xs[idx]
+				errTerm := model.predictStandardized(x) - train[idx].Label
+				for k, v := range x {
+					grad[k] += errTerm * v
+				}
+				gradBias += errTerm
+			}
+
+			n := float64(end - start)
+			for k := range model.Weights {
+				model.Weights[k] -= cfg.LearningRate * (grad[k]/n + cfg.L2*model.Weights[k])
+			}
+			model.Bias -= cfg.LearningRate * gradBias / n
+		}
+	}
+
+	model.Metrics = MLMetrics{
+		SampleCount:  len(train),
+		HoldoutCount: len(holdout),
+		Epochs:       cfg.Epochs,
+	}
+	if len(holdout) > 0 {
+		model.Metrics.LogLoss = model.logLoss(holdout)
+		model.Metrics.BaselineLogLoss = baselineLogLoss(holdout, baseRate)
+	}
+
+	return model, nil
+}
+
+// featureMoments 특징별 평균/표준편차 계산 (표준편차 0은 1로 대체)
+func featureMoments(samples []mlSample, featureCount int) ([]float64, []float64) {
+	means := make([]float64, featureCount)
+	stds := make([]float64, featureCount)
+	n := float64(len(samples))
+
+	for _, s := range samples {
+		for k, v := range s.Features {
+			means[k] += v
+		}
+	}
+	for k := range means {
+		means[k] /= n
+	}
+	for _, s := range samples {
+		for k, v := range s.Features {
+			d := v - means[k]
+			stds[k] += d * d
+		}
+	}
+	for k := range stds {
+		stds[k] = math.Sqrt(stds[k] / n)
+		if stds[k] < 1e-12 {
+			stds[k] = 1
+		}
+	}
+	return means, stds
+}
+
+// standardize 특징 벡터를 학습 분포 기준으로 표준화
+func (m *MLModel) standardize(features []float64) []float64 {
+	x := make([]float64, len(features))
+	for k, v := range features {
+		x[k] = (v - m.Means[k]) / m.Stds[k]
+	}
+	return x
+}
+
+// predictStandardized 표준화된 특징으로 출현 확률 예측
+func (m *MLModel) predictStandardized(x []float64) float64 {
+	z := m.Bias
+	for k, v := range x {
+		z += m.Weights[k] * v
+	}
+	return sigmoid(z)
+}
+
+// Predict 원본 특징 벡터로 다음 회차 출현 확률 예측
+func (m *MLModel) Predict(features []float64) float64 {
+	return m.predictStandardized(m.standardize(features))
+}
+
+// Validate 직렬화된 모델의 차원 일관성 검사
+func (m *MLModel) Validate() error {
+	n := len(mlFeatureNames)
+	if len(m.Weights) != n || len(m.Means) != n || len(m.Stds) != n {
+		return fmt.Errorf("ml model v%d: expected %d features, got weights=%d means=%d stds=%d",
+			m.Version, n, len(m.Weights), len(m.Means), len(m.Stds))
+	}
+	return nil
+}
+
+// logLoss 샘플 집합에 대한 평균 log loss
+func (m *MLModel) logLoss(samples []mlSample) float64 {
+	const eps = 1e-12
+	total := 0.0
+	for _, s := range samples {
+		p := math.Min(math.Max(m.Predict(s.Features), eps), 1-eps)
+		total -= s.Label*math.Log(p) + (1-s.Label)*math.Log(1-p)
+	}
+	return total / float64(len(samples))
+}
+
+// baselineLogLoss 고정 확률 예측 시 log loss (비교 기준)
+func baselineLogLoss(samples []mlSample, p float64) float64 {
+	total := 0.0
+	for _, s := range samples {
+		total -= s.Label*math.Log(p) + (1-s.Label)*math.Log(1-p)
+	}
+	return total / float64(len(samples))
+}
+
+func sigmoid(z float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-z))
+}
@@ -0,0 +1,147 @@
+package lotto
+
+import (
+	"math"
+	"testing"
+)
+
+// 테스트용 회차별 통합 통계 생성
+// 짝수 회차마다 1~6번이 출현하며, TotalProb가 높은 번호가 다음 회차에 나오도록 구성한다.
+func makeTestDrawStats(draws int) []AnalysisStat {
+	stats := make([]AnalysisStat, 0, draws*TotalNumbers)
+	for d := 1; d <= draws; d++ {
+		hot := d%2 == 1 // 다음 회차(짝수)에 1~6번 출현 예정
+		for n := 1; n <= TotalNumbers; n++ {
+			appeared := false
+			if d%2 == 0 {
+				appeared = n <= 6
+			} else {
+				appeared = n >= 40
+			}
+
+			prob := 0.01
+			if hot && n <= 6 {
+				prob = 0.05
+			}
+			if !hot && n >= 40 {
+				prob = 0.05
+			}
+
+			stats = append(stats, AnalysisStat{
+				DrawNo:       d,
+				Number:       n,
+				TotalProb:    prob,
+				BayesianPost: 1.0 / TotalNumbers,
+				Appeared:     appeared,
+			})
+		}
+	}
+	return stats
+}
+
+func TestBuildMLSamples(t *testing.T) {
+	stats := makeTestDrawStats(4)
+	samples := buildMLSamples(stats)
+
+	// 마지막 회차는 정답이 없으므로 3개 회차 분량
+	if len(samples) != 3*TotalNumbers {
+		t.Fatalf("expected %d samples, got %d", 3*TotalNumbers, len(samples))
+	}
+
+	gapIdx := len(mlFeatureNames) - 2
+	for _, s := range samples {
+		if len(s.Features) != len(mlFeatureNames) {
+			t.Fatalf("expected %d features, got %d", len(mlFeatureNames), len(s.Features))
+		}
+	}
+
+	// 2회차 1번: 2회차에 출현했으므로 gap=0, 3회차에는 미출현
+	s := samples[TotalNumbers] // 2회차 첫 번째 번호(1번)
+	if s.DrawNo != 2 {
+		t.Fatalf("expected draw 2, got %d", s.DrawNo)
+	}
+	if s.Features[gapIdx] != 0 {
+		t.Errorf("draw 2 number 1: expected gap 0, got %v", s.Features[gapIdx])
+	}
+	if s.Label != 0 {
+		t.Errorf("draw 2 number 1: expected label 0, got %v", s.Label)
+	}
+
+	// 3회차 1번: 마지막 출현 2회차 -> gap=1, 4회차 출현
+	s = samples[2*TotalNumbers]
+	if s.Features[gapIdx] != 1 {
+		t.Errorf("draw 3 number 1: expected gap 1, got %v", s.Features[gapIdx])
+	}
+	if s.Label != 1 {
+		t.Errorf("draw 3 number 1: expected label 1, got %v", s.Label)
+	}
+}
+
+func TestBuildMLSamplesSkipsGap(t *testing.T) {
+	stats := makeTestDrawStats(5)
+	// 3회차 제거 -> 2회차, 3회차 샘플 생성 불가
+	filtered := make([]AnalysisStat, 0, len(stats))
+	for _, s := range stats {
+		if s.DrawNo != 3 {
+			filtered = append(filtered, s)
+		}
+	}
+
+	samples := buildMLSamples(filtered)
+	for _, s := range samples {
+		if s.DrawNo == 2 || s.DrawNo == 3 || s.DrawNo == 5 {
+			t.Errorf("unexpected sample for draw %d", s.DrawNo)
+		}
+	}
+	if len(samples) != 2*TotalNumbers {
+		t.Errorf("expected %d samples, got %d", 2*TotalNumbers, len(samples))
+	}
+}
+
+func TestTrainLogisticModel(t *testing.T) {
+	stats := makeTestDrawStats(60)
+
+	model, err := TrainLogisticModel(stats, MLTrainConfig{Epochs: 50, Seed: 1})
+	if err != nil {
+		t.Fatalf("TrainLogisticModel: %v", err)
+	}
+
+	if err := model.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if model.TrainedDrawNo != 60 {
+		t.Errorf("expected trained draw 60, got %d", model.TrainedDrawNo)
+	}
+	if model.Metrics.HoldoutCount == 0 {
+		t.Fatal("expected holdout samples")
+	}
+	if model.Metrics.LogLoss >= model.Metrics.BaselineLogLoss {
+		t.Errorf("log loss %.5f should beat baseline %.5f",
+			model.Metrics.LogLoss, model.Metrics.BaselineLogLoss)
+	}
+
+	// 학습된 신호: TotalProb가 높은 번호의 확률이 더 높아야 함
+	hot := model.Predict(mlFeatures(AnalysisStat{TotalProb: 0.05, BayesianPost: 1.0 / TotalNumbers}, 1))
+	cold := model.Predict(mlFeatures(AnalysisStat{TotalProb: 0.01, BayesianPost: 1.0 / TotalNumbers}, 1))
+	if hot <= cold {
+		t.Errorf("expected hot prob %.4f > cold prob %.4f", hot, cold)
+	}
+	for _, p := range []float64{hot, cold} {
+		if p <= 0 || p >= 1 || math.IsNaN(p) {
+			t.Errorf("prediction out of range: %v", p)
+		}
+	}
+}
+
+func TestTrainLogisticModelNotEnoughSamples(t *testing.T) {
+	if _, err := TrainLogisticModel(makeTestDrawStats(5), MLTrainConfig{}); err == nil {
+		t.Error("expected error for too few samples")
+	}
+}
+
+func TestMLModelValidate(t *testing.T) {
+	m := &MLModel{Version: 1, Weights: []float64{1, 2}}
+	if err := m.Validate(); err == nil {
+		t.Error("expected dimension mismatch error")
+	}
+}
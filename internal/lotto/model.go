@@ -1,6 +1,10 @@
 package lotto
 
-import "time"
+import (
+	"time"
+
+	"github.com/example/LottoSmash/internal/constants"
+)
 
 // LottoDraw 로또 당첨번호
 type LottoDraw struct {
@@ -396,6 +400,7 @@ type AnalysisMethod struct {
 	Category    string    `json:"category"`
 	IsActive    bool      `json:"is_active"`
 	SortOrder   int       `json:"sort_order"`
+	DefaultParams MethodParams `json:"default_params"` // 요청에서 생략한 파라미터의 기본값
 	CreatedAt   time.Time `json:"-"`
 	UpdatedAt   time.Time `json:"-"`
 }
@@ -406,9 +411,12 @@ type LottoRecommendation struct {
 	UserID        *int64    `json:"user_id,omitempty"`
 	MethodCodes   []string  `json:"method_codes"`
 	CombineMethod string    `json:"combine_method"`
+	DrawNo        int       `json:"draw_no"` // 대상 회차
 	Numbers       []int     `json:"numbers"`
 	BonusNumber   *int      `json:"bonus_number,omitempty"`
 	Confidence    float64   `json:"confidence"`
+	ExperimentID  *int64    `json:"experiment_id,omitempty"` // 배정된 A/B 실험
+	VariantCode   string    `json:"variant_code,omitempty"`  // 배정된 실험 변형
 	CreatedAt     time.Time `json:"created_at"`
 }
 
@@ -420,6 +428,13 @@ type RecommendRequest struct {
 	MinMaxMode   string             `json:"min_max_mode,omitempty"`  // MIN_MAX 조합 시 모드: "MAX"(낙관적, 기본) 또는 "MIN"(보수적)
 	IncludeBonus bool               `json:"include_bonus"`
 	Count        int                `json:"count"`                   // 추천 세트 개수 (기본값: 1, 최대: 10)
+	HalfLife     int                `json:"half_life,omitempty"`     // DECAY_FREQUENCY 반감기 (회차 수, 기본값: 52)
+	Mode         string             `json:"mode,omitempty"`          // 추천 모드: "TOP"(번호별 상위, 기본) 또는 "OPTIMIZE"(조합 최적화)
+	Objectives   *OptimizeWeights   `json:"objectives,omitempty"`    // OPTIMIZE 모드 목적 함수별 가중치
+	Explain      bool               `json:"explain,omitempty"`       // 번호별 선택 근거 포함 여부
+	AvoidShared  bool               `json:"avoid_shared,omitempty"`  // 같은 회차에 다른 사용자가 받은 (거의) 같은 조합 회피
+	AllowPastWinners bool           `json:"allow_past_winners,omitempty"` // 역대 1등 당첨 조합 허용 (기본: 제외)
+	Temperature  float64            `json:"temperature,omitempty"`   // 샘플링 온도: 0이면 점수 상위 6개 고정, 클수록 다양한 번호 (최대 5)
 }
 
 // Recommendation 단일 추천 결과
@@ -430,6 +445,12 @@ type Recommendation struct {
 	CombineMethod string                 `json:"combine_method"`
 	Confidence    float64                `json:"confidence"`
 	Details       map[string]interface{} `json:"details,omitempty"`
+	Optimization  *OptimizationReport    `json:"optimization,omitempty"` // OPTIMIZE 모드 목적 함수별 기여도
+	Explanations  []NumberExplanation    `json:"explanations,omitempty"` // explain=true 시 번호별 선택 근거
+	SharedBy      int                    `json:"shared_by"`              // 같은 회차에 같은 조합을 받은 다른 사용자 수
+	NearlySharedBy int                   `json:"nearly_shared_by"`       // 같은 회차에 5개 번호가 같은 조합을 받은 다른 사용자 수
+	PastWinnerDrawNo *int                `json:"past_winner_draw_no,omitempty"` // 역대 1등 조합과 같으면 해당 회차 (allow_past_winners=true 시)
+	Regenerated   bool                   `json:"regenerated,omitempty"`  // 중복 회피를 위해 다른 조합으로 교체됨
 }
 
 // RecommendResponse 추천 응답
@@ -437,6 +458,8 @@ type RecommendResponse struct {
 	Recommendations []Recommendation `json:"recommendations"`
 	GeneratedAt     time.Time        `json:"generated_at"`
 	LatestDrawNo    int              `json:"latest_draw_no"`
+	TargetDrawNo    int              `json:"target_draw_no,omitempty"` // 추천 번호가 참여하는 회차
+	Experiment      *ExperimentAssignment `json:"experiment,omitempty"` // 배정된 A/B 실험 변형과 UI 플래그
 }
 
 // MethodListResponse 분석 방법 목록 응답
@@ -467,6 +490,7 @@ type CombineMethod struct {
 	Description string `json:"description"`
 	IsActive    bool   `json:"is_active"`
 	SortOrder   int    `json:"sort_order"`
+	DefaultParams MethodParams `json:"default_params"` // 요청에서 생략한 파라미터의 기본값
 }
 
 // CombineMethodListResponse 조합 방법 목록 응답
@@ -475,11 +499,45 @@ type CombineMethodListResponse struct {
 	TotalCount int             `json:"total_count"`
 }
 
-// AllCombineMethods 전체 조합 방법 목록 (하드코딩)
-var AllCombineMethods = []CombineMethod{
+// defaultCombineMethods 레지스트리를 불러오기 전(DB 미연결 등) 사용하는 기본 조합 방법 목록
+// combine_methods 테이블 초기 데이터(000022)와 같다.
+var defaultCombineMethods = []CombineMethod{
 	{Code: CombineSimpleAvg, Name: "단순 평균", Description: "선택한 기법들의 확률을 단순 평균하여 조합", IsActive: true, SortOrder: 1},
 	{Code: CombineWeightedAvg, Name: "가중 평균", Description: "각 기법별 가중치를 직접 지정하여 평균", IsActive: true, SortOrder: 2},
 	{Code: CombineBayesian, Name: "베이지안 결합", Description: "베이지안 확률 결합으로 두 확률을 보수적으로 조합", IsActive: true, SortOrder: 3},
 	{Code: CombineGeometricMean, Name: "기하 평균", Description: "확률의 기하 평균으로 낮은 확률에 더 민감하게 반응", IsActive: true, SortOrder: 4},
-	{Code: CombineMinMax, Name: "최대/최소 기반", Description: "낙관적(최대) 또는 보수적(최소) 확률 선택", IsActive: true, SortOrder: 5},
+	{Code: CombineMinMax, Name: "최대/최소 기반", Description: "낙관적(최대) 또는 보수적(최소) 확률 선택", IsActive: true, SortOrder: 5, DefaultParams: MethodParams{MinMaxMode: "MAX"}},
+}
+
+// ========================================
+// 시간 감쇠 빈도 관련 모델
+// ========================================
+
+// 시간 감쇠 빈도 상수
+const (
+	MethodDecayFrequency = "DECAY_FREQUENCY"
+
+	DefaultDecayHalfLife = 52                         // 기본 반감기 (약 1년)
+	MaxDecayHalfLife     = constants.MaxDecayHalfLife // 최대 반감기
+)
+
+// DecayStat 시간 감쇠 가중 빈도 통계 (DB 저장용)
+// 최근 회차일수록 큰 가중치를 부여하여 전체 기간 빈도(NUMBER_FREQUENCY)와
+// 고정 윈도우(HOT_COLD)의 중간 성격을 가진다. 45개 번호의 score 합은 1이다.
+type DecayStat struct {
+	HalfLife      int     `json:"half_life"`      // 반감기 (회차 수)
+	DrawNo        int     `json:"draw_no"`        // 회차 번호
+	Number        int     `json:"number"`         // 번호 (1~45)
+	WeightedCount float64 `json:"weighted_count"` // 감쇠 가중 출현 횟수
+	WeightSum     float64 `json:"weight_sum"`     // 회차 가중치 합계
+	Score         float64 `json:"score"`          // weighted_count / (weight_sum * 6)
+	Appeared      bool    `json:"appeared"`       // 해당 회차 출현 여부
+}
+
+// DecayStatsResponse 시간 감쇠 빈도 통계 응답
+type DecayStatsResponse struct {
+	Numbers      []DecayStat `json:"numbers"`        // score 내림차순
+	HalfLife     int         `json:"half_life"`      // 반감기 (회차 수)
+	DecayFactor  float64     `json:"decay_factor"`   // 회차당 감쇠 계수 0.5^(1/half_life)
+	LatestDrawNo int         `json:"latest_draw_no"` // 기준 회차
 }
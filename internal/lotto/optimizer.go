@@ -0,0 +1,477 @@
+package lotto
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// 추천 모드 상수
+const (
+	RecommendModeTop      = "TOP"      // 번호별 점수 상위 6개 (기본)
+	RecommendModeOptimize = "OPTIMIZE" // 조합 단위 다목적 최적화
+
+	OptimizerAlgorithm = "SIMULATED_ANNEALING"
+
+	defaultOptimizeIterations = 4000
+	optimizeStartTemp         = 0.05
+	optimizeEndTemp           = 0.0005
+)
+
+// 목적 함수 이름
+const (
+	ObjectiveScore      = "score"      // 분석기법 번호별 점수
+	ObjectivePair       = "pair"       // 번호 쌍 동반 출현 친화도
+	ObjectivePattern    = "pattern"    // 합계/홀짝/고저 패턴의 전형성
+	ObjectivePopularity = "popularity" // 낮은 추정 인기도 (당첨 시 분배 유리)
+)
+
+// OptimizeWeights 목적 함수별 가중치 (합이 1이 되도록 정규화하여 사용)
+type OptimizeWeights struct {
+	Score      float64 `json:"score"`
+	Pair       float64 `json:"pair"`
+	Pattern    float64 `json:"pattern"`
+	Popularity float64 `json:"popularity"`
+}
+
+// DefaultOptimizeWeights 기본 목적 함수 가중치
+var DefaultOptimizeWeights = OptimizeWeights{
+	Score:      0.4,
+	Pair:       0.2,
+	Pattern:    0.2,
+	Popularity: 0.2,
+}
+
+// normalized 가중치 합이 1이 되도록 정규화 (모두 0이면 기본값)
+func (w OptimizeWeights) normalized() OptimizeWeights {
+	sum := w.Score + w.Pair + w.Pattern + w.Popularity
+	if sum <= 0 {
+		return DefaultOptimizeWeights.normalized()
+	}
+	return OptimizeWeights{
+		Score:      w.Score / sum,
+		Pair:       w.Pair / sum,
+		Pattern:    w.Pattern / sum,
+		Popularity: w.Popularity / sum,
+	}
+}
+
+// Validate 가중치 유효성 검사
+func (w OptimizeWeights) Validate() bool {
+	if w.Score < 0 || w.Pair < 0 || w.Pattern < 0 || w.Popularity < 0 {
+		return false
+	}
+	return w.Score+w.Pair+w.Pattern+w.Popularity > 0
+}
+
+// ObjectiveContribution 목적 함수별 기여도
+type ObjectiveContribution struct {
+	Objective    string  `json:"objective"`    // 목적 함수 이름
+	Weight       float64 `json:"weight"`       // 정규화된 가중치
+	Value        float64 `json:"value"`        // 목적 함수 값 (0~1)
+	Contribution float64 `json:"contribution"` // weight * value
+	Share        float64 `json:"share"`        // 전체 목적값 중 비중 (0~1)
+}
+
+// OptimizationReport 조합 최적화 결과 보고
+type OptimizationReport struct {
+	Algorithm       string                  `json:"algorithm"`
+	Iterations      int                     `json:"iterations"`
+	Objective       float64                 `json:"objective"`        // 최종 조합 목적값 (0~1)
+	GreedyObjective float64                 `json:"greedy_objective"` // 번호별 상위 6개 조합의 목적값 (비교용)
+	Contributions   []ObjectiveContribution `json:"contributions"`
+}
+
+// comboObjective 6개 번호 조합 평가기
+// 추천 요청마다 한 번 구성하여 여러 세트 생성에 재사용한다.
+type comboObjective struct {
+	weights OptimizeWeights
+
+	// 번호 쌍 동반 출현 확률 (pair[a][b], a<b)
+	pair             [TotalNumbers + 1][TotalNumbers + 1]float64
+	pairMin, pairMax float64
+
+	// 당첨번호 합계 분포
+	sumMean, sumStd float64
+
+	// 홀수/고번호 개수별 출현 확률 (0~6)
+	oddProb, highProb       [NumbersPerDraw + 1]float64
+	oddProbMax, highProbMax float64
+
+	// 추가 제외 조건 (다른 사용자와 중복, 역대 1등 조합 등; nil이면 없음)
+	reject func(nums []int) bool
+}
+
+// newComboObjective 과거 당첨번호와 번호 쌍 통계로 평가기 생성
+// pairStats가 비어있으면 당첨번호에서 직접 쌍 빈도를 계산한다.
+func newComboObjective(draws []*LottoDraw, pairStats []PairStatDB, weights OptimizeWeights) *comboObjective {
+	o := &comboObjective{weights: weights.normalized()}
+
+	// 번호 쌍 친화도
+	if len(pairStats) > 0 {
+		for _, p := range pairStats {
+			o.pair[p.Number1][p.Number2] = p.Prob
+		}
+	} else if len(draws) > 0 {
+		for _, d := range draws {
+			for _, pr := range extractPairs(d.Numbers()) {
+				o.pair[pr[0]][pr[1]]++
+			}
+		}
+		for a := 1; a <= TotalNumbers; a++ {
+			for b := a + 1; b <= TotalNumbers; b++ {
+				o.pair[a][b] /= float64(len(draws))
+			}
+		}
+	}
+	o.pairMin, o.pairMax = math.MaxFloat64, 0
+	for a := 1; a <= TotalNumbers; a++ {
+		for b := a + 1; b <= TotalNumbers; b++ {
+			o.pairMin = math.Min(o.pairMin, o.pair[a][b])
+			o.pairMax = math.Max(o.pairMax, o.pair[a][b])
+		}
+	}
+
+	// 합계/홀짝/고저 분포
+	if len(draws) > 0 {
+		var sum, sumSq float64
+		for _, d := range draws {
+			nums := d.Numbers()
+			total := float64(sumNumbers(nums))
+			sum += total
+			sumSq += total * total
+			o.oddProb[countOddNumbers(nums)]++
+			o.highProb[countHighNumbers(nums)]++
+		}
+		n := float64(len(draws))
+		o.sumMean = sum / n
+		o.sumStd = math.Sqrt(math.Max(sumSq/n-o.sumMean*o.sumMean, 0))
+		for i := range o.oddProb {
+			o.oddProb[i] /= n
+			o.highProb[i] /= n
+		}
+	} else {
+		// 이론 분포 근사 (균등 무작위 추첨)
+		o.sumMean, o.sumStd = 138, 31
+		for i := range o.oddProb {
+			o.oddProb[i] = 1.0 / float64(len(o.oddProb))
+			o.highProb[i] = 1.0 / float64(len(o.highProb))
+		}
+	}
+	for i := range o.oddProb {
+		o.oddProbMax = math.Max(o.oddProbMax, o.oddProb[i])
+		o.highProbMax = math.Max(o.highProbMax, o.highProb[i])
+	}
+
+	return o
+}
+
+// evaluate 조합의 목적 함수별 값 (각 0~1)
+// scoreNorm은 번호별 점수 상위 6개 합으로, score 목적을 0~1로 맞추는 데 쓴다.
+func (o *comboObjective) evaluate(nums []int, scores map[int]float64, scoreNorm float64) OptimizeWeights {
+	sorted := append([]int(nil), nums...)
+	sort.Ints(sorted)
+
+	var v OptimizeWeights
+
+	// 1. 번호별 점수
+	if scoreNorm > 0 {
+		total := 0.0
+		for _, n := range sorted {
+			total += scores[n]
+		}
+		v.Score = clamp01(total / scoreNorm)
+	}
+
+	// 2. 번호 쌍 친화도 (15개 쌍 평균을 전체 쌍 범위로 정규화)
+	if o.pairMax > o.pairMin {
+		v.Pair = clamp01((o.meanPairProb(sorted) - o.pairMin) / (o.pairMax - o.pairMin))
+	}
+
+	// 3. 패턴 전형성 (합계 정규분포 우도 + 홀짝/고저 비율 확률)
+	sumScore := 1.0
+	if o.sumStd > 0 {
+		z := (float64(sumNumbers(sorted)) - o.sumMean) / o.sumStd
+		sumScore = math.Exp(-z * z / 2)
+	}
+	oddScore, highScore := 1.0, 1.0
+	if o.oddProbMax > 0 {
+		oddScore = o.oddProb[countOddNumbers(sorted)] / o.oddProbMax
+	}
+	if o.highProbMax > 0 {
+		highScore = o.highProb[countHighNumbers(sorted)] / o.highProbMax
+	}
+	v.Pattern = (sumScore + oddScore + highScore) / 3
+
+	// 4. 낮은 인기도
+	v.Popularity = 1 - estimatePopularity(sorted)
+
+	return v
+}
+
+// meanPairProb 조합 내 번호 쌍(15개)의 평균 동반 출현 확률
+func (o *comboObjective) meanPairProb(nums []int) float64 {
+	pairs := extractPairs(nums)
+	if len(pairs) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, pr := range pairs {
+		total += o.pair[pr[0]][pr[1]]
+	}
+	return total / float64(len(pairs))
+}
+
+// total 가중 합산 목적값
+func (o *comboObjective) total(v OptimizeWeights) float64 {
+	w := o.weights
+	return w.Score*v.Score + w.Pair*v.Pair + w.Pattern*v.Pattern + w.Popularity*v.Popularity
+}
+
+// report 목적 함수별 기여도 보고 생성
+func (o *comboObjective) report(v OptimizeWeights, iterations int, greedy float64) *OptimizationReport {
+	w := o.weights
+	total := o.total(v)
+
+	entries := []struct {
+		name          string
+		weight, value float64
+	}{
+		{ObjectiveScore, w.Score, v.Score},
+		{ObjectivePair, w.Pair, v.Pair},
+		{ObjectivePattern, w.Pattern, v.Pattern},
+		{ObjectivePopularity, w.Popularity, v.Popularity},
+	}
+
+	contributions := make([]ObjectiveContribution, 0, len(entries))
+	for _, e := range entries {
+		c := ObjectiveContribution{
+			Objective:    e.name,
+			Weight:       e.weight,
+			Value:        e.value,
+			Contribution: e.weight * e.value,
+		}
+		if total > 0 {
+			c.Share = c.Contribution / total
+		}
+		contributions = append(contributions, c)
+	}
+
+	return &OptimizationReport{
+		Algorithm:       OptimizerAlgorithm,
+		Iterations:      iterations,
+		Objective:       total,
+		GreedyObjective: greedy,
+		Contributions:   contributions,
+	}
+}
+
+// optimize 시뮬레이티드 어닐링으로 목적값이 최대인 6개 번호 조합 탐색
+// start(보통 번호별 상위 6개)에서 시작해 한 번호씩 교체하며 탐색하고,
+// exclude에 있는 조합(같은 응답의 이전 세트)과 reject 조건에 걸리는 조합은 결과에서 제외한다.
+// 반복마다 난수를 쓰므로 rng는 호출자 전용(추천 세션별)이어야 한다.
+func (o *comboObjective) optimize(rng *rand.Rand, scores map[int]float64, start []int, exclude [][]int, iterations int) ([]int, *OptimizationReport) {
+	if iterations <= 0 {
+		iterations = defaultOptimizeIterations
+	}
+
+	scoreNorm := topScoreSum(scores, NumbersPerDraw)
+	excluded := make(map[string]bool, len(exclude))
+	for _, nums := range exclude {
+		excluded[comboKey(nums)] = true
+	}
+
+	current := append([]int(nil), start...)
+	inSet := make(map[int]bool, NumbersPerDraw)
+	for _, n := range current {
+		inSet[n] = true
+	}
+
+	greedyValue := o.total(o.evaluate(start, scores, scoreNorm))
+	currentValue := greedyValue
+
+	var best []int
+	bestValue := math.Inf(-1)
+	if !o.excluded(excluded, current) {
+		best = append([]int(nil), current...)
+		bestValue = currentValue
+	}
+
+	cooling := math.Pow(optimizeEndTemp/optimizeStartTemp, 1/float64(iterations))
+	temp := optimizeStartTemp
+
+	for i := 0; i < iterations; i++ {
+		// 이웃 해: 임의 위치의 번호를 세트에 없는 번호로 교체
+		pos := rng.Intn(NumbersPerDraw)
+		candidate := rng.Intn(TotalNumbers) + 1
+		if inSet[candidate] {
+			temp *= cooling
+			continue
+		}
+
+		old := current[pos]
+		current[pos] = candidate
+		value := o.total(o.evaluate(current, scores, scoreNorm))
+
+		delta := value - currentValue
+		if delta >= 0 || rng.Float64() < math.Exp(delta/temp) {
+			delete(inSet, old)
+			inSet[candidate] = true
+			currentValue = value
+
+			if value > bestValue && !o.excluded(excluded, current) {
+				best = append(best[:0], current...)
+				bestValue = value
+			}
+		} else {
+			current[pos] = old
+		}
+
+		temp *= cooling
+	}
+
+	// 모든 탐색 조합이 제외 대상이면 시작 조합 유지
+	if best == nil {
+		best = append([]int(nil), start...)
+	}
+	sort.Ints(best)
+
+	return best, o.report(o.evaluate(best, scores, scoreNorm), iterations, greedyValue)
+}
+
+// excluded 탐색 결과에서 제외할 조합인지 확인
+func (o *comboObjective) excluded(exclude map[string]bool, nums []int) bool {
+	if exclude[comboKey(nums)] {
+		return true
+	}
+	return o.reject != nil && o.reject(nums)
+}
+
+// estimatePopularity 다른 구매자가 고를 가능성(인기도) 추정 (0~1)
+// 실제 구매 데이터가 없으므로 알려진 선택 편향으로 근사한다:
+// 생일 번호(1~31) 편중, 연번, 등차수열, 용지의 같은 행/열 직선 배치
+func estimatePopularity(nums []int) float64 {
+	if len(nums) == 0 {
+		return 0
+	}
+
+	// 생일 번호 편중: 1~31 비율이 무작위 기대치(31/45)를 넘는 정도
+	birthday := 0
+	for _, n := range nums {
+		if n <= 31 {
+			birthday++
+		}
+	}
+	const birthdayBase = 31.0 / TotalNumbers
+	birthdayScore := clamp01((float64(birthday)/float64(len(nums)) - birthdayBase) / (1 - birthdayBase))
+
+	// 연번: 최대 연속 길이 3부터 인기도 상승
+	consecScore := 0.0
+	if c := countConsecutive(nums); c >= 3 {
+		consecScore = clamp01(float64(c-2) / 4)
+	}
+
+	// 등차수열 (예: 5, 10, 15, 20, 25, 30)
+	arithScore := 0.0
+	if len(nums) > 2 {
+		diff := nums[1] - nums[0]
+		arith := true
+		for i := 2; i < len(nums); i++ {
+			if nums[i]-nums[i-1] != diff {
+				arith = false
+				break
+			}
+		}
+		if arith {
+			arithScore = 1
+		}
+	}
+
+	// 용지 직선 배치: 같은 행 또는 열에 4개 이상
+	rows := make(map[int]int)
+	cols := make(map[int]int)
+	maxLine := 0
+	for _, n := range nums {
+		r, c := getRowCol(n)
+		rows[r]++
+		cols[c]++
+		if rows[r] > maxLine {
+			maxLine = rows[r]
+		}
+		if cols[c] > maxLine {
+			maxLine = cols[c]
+		}
+	}
+	lineScore := 0.0
+	if maxLine >= 4 {
+		lineScore = clamp01(float64(maxLine-3) / 3)
+	}
+
+	return clamp01(0.4*birthdayScore + 0.2*consecScore + 0.2*arithScore + 0.2*lineScore)
+}
+
+// buildComboObjective 최적화 모드용 평가기 생성 (당첨번호 + 최신 번호 쌍 통계)
+func (r *Recommender) buildComboObjective(ctx context.Context, weights *OptimizeWeights) (*comboObjective, error) {
+	draws, err := r.repo.GetAllDraws(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pairStats, err := r.repo.GetLatestPairStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := DefaultOptimizeWeights
+	if weights != nil {
+		w = *weights
+	}
+
+	return newComboObjective(draws, pairStats, w), nil
+}
+
+// topScoreSum 점수 상위 n개의 합
+func topScoreSum(scores map[int]float64, n int) float64 {
+	values := make([]float64, 0, len(scores))
+	for _, s := range scores {
+		values = append(values, s)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(values)))
+
+	total := 0.0
+	for i := 0; i < n && i < len(values); i++ {
+		total += values[i]
+	}
+	return total
+}
+
+// comboKey 조합 비교용 키 (순서 무관)
+func comboKey(nums []int) string {
+	sorted := append([]int(nil), nums...)
+	sort.Ints(sorted)
+	key := make([]byte, 0, len(sorted)*3)
+	for _, n := range sorted {
+		key = append(key, byte(n), ',')
+	}
+	return string(key)
+}
+
+// sumNumbers 번호 합계
+func sumNumbers(nums []int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,140 @@
+package lotto
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// 테스트용 과거 당첨번호 생성 (무작위 추첨)
+func makeRandomDraws(n int, seed int64) []*LottoDraw {
+	rng := rand.New(rand.NewSource(seed))
+	draws := make([]*LottoDraw, 0, n)
+	for i := 1; i <= n; i++ {
+		perm := rng.Perm(TotalNumbers)[:NumbersPerDraw]
+		nums := make([]int, NumbersPerDraw)
+		for k, p := range perm {
+			nums[k] = p + 1
+		}
+		sort.Ints(nums)
+		draws = append(draws, makeTestDraw(i, nums...))
+	}
+	return draws
+}
+
+func TestEstimatePopularity(t *testing.T) {
+	tests := []struct {
+		name    string
+		nums    []int
+		wantMin float64
+		wantMax float64
+	}{
+		{"arithmetic birthday", []int{1, 2, 3, 4, 5, 6}, 0.8, 1.0},
+		{"multiples of 5", []int{5, 10, 15, 20, 25, 30}, 0.6, 1.0},
+		{"spread high numbers", []int{8, 19, 33, 37, 41, 44}, 0.0, 0.05},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimatePopularity(tt.nums)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("estimatePopularity(%v) = %.3f, want [%.2f, %.2f]", tt.nums, got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestOptimizeWeights(t *testing.T) {
+	w := OptimizeWeights{Score: 2, Pair: 1, Pattern: 1}.normalized()
+	if math.Abs(w.Score+w.Pair+w.Pattern+w.Popularity-1) > 1e-9 {
+		t.Errorf("normalized weights should sum to 1: %+v", w)
+	}
+	if math.Abs(w.Score-0.5) > 1e-9 {
+		t.Errorf("score weight = %.3f, want 0.5", w.Score)
+	}
+
+	if (OptimizeWeights{}).Validate() {
+		t.Error("all-zero weights should be invalid")
+	}
+	if (OptimizeWeights{Score: 1, Pair: -1}).Validate() {
+		t.Error("negative weight should be invalid")
+	}
+}
+
+func TestComboObjectiveOptimize(t *testing.T) {
+	draws := makeRandomDraws(300, 7)
+	objective := newComboObjective(draws, nil, DefaultOptimizeWeights)
+
+	// 연속된 낮은 번호에 높은 점수 -> 탐욕 선택은 1~6 (인기 조합)
+	scores := make(map[int]float64, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		scores[n] = 1.0 / float64(n+10)
+	}
+	start := []int{1, 2, 3, 4, 5, 6}
+
+	rng := rand.New(rand.NewSource(1))
+	nums, report := objective.optimize(rng, scores, start, nil, 3000)
+
+	if len(nums) != NumbersPerDraw {
+		t.Fatalf("expected %d numbers, got %d", NumbersPerDraw, len(nums))
+	}
+	seen := make(map[int]bool)
+	for _, n := range nums {
+		if n < 1 || n > TotalNumbers || seen[n] {
+			t.Fatalf("invalid combination: %v", nums)
+		}
+		seen[n] = true
+	}
+
+	if report.Objective < report.GreedyObjective {
+		t.Errorf("optimized objective %.4f should not be worse than greedy %.4f", report.Objective, report.GreedyObjective)
+	}
+	if comboKey(nums) == comboKey(start) {
+		t.Errorf("expected optimizer to move away from popular greedy set %v", start)
+	}
+
+	// 기여도 합 = 목적값, 비중 합 = 1
+	contribSum, shareSum := 0.0, 0.0
+	for _, c := range report.Contributions {
+		if c.Value < 0 || c.Value > 1 {
+			t.Errorf("%s value out of range: %.4f", c.Objective, c.Value)
+		}
+		contribSum += c.Contribution
+		shareSum += c.Share
+	}
+	if len(report.Contributions) != 4 {
+		t.Errorf("expected 4 objectives, got %d", len(report.Contributions))
+	}
+	if math.Abs(contribSum-report.Objective) > 1e-9 {
+		t.Errorf("contributions sum %.6f != objective %.6f", contribSum, report.Objective)
+	}
+	if math.Abs(shareSum-1) > 1e-9 {
+		t.Errorf("shares sum %.6f != 1", shareSum)
+	}
+}
+
+func TestComboObjectiveOptimize_Exclude(t *testing.T) {
+	objective := newComboObjective(makeRandomDraws(100, 3), nil, OptimizeWeights{Score: 1})
+
+	scores := make(map[int]float64, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		scores[n] = float64(n)
+	}
+	start := []int{40, 41, 42, 43, 44, 45}
+
+	rng := rand.New(rand.NewSource(1))
+	nums, _ := objective.optimize(rng, scores, start, [][]int{start}, 2000)
+	if comboKey(nums) == comboKey(start) {
+		t.Errorf("excluded combination returned: %v", nums)
+	}
+}
+
+func TestComboKey(t *testing.T) {
+	if comboKey([]int{6, 1, 3}) != comboKey([]int{1, 3, 6}) {
+		t.Error("comboKey should be order independent")
+	}
+	if comboKey([]int{1, 2, 3}) == comboKey([]int{1, 2, 4}) {
+		t.Error("different combinations should have different keys")
+	}
+}
@@ -0,0 +1,49 @@
+package lotto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxPresetsPerUser 사용자당 최대 프리셋 수
+	MaxPresetsPerUser = 20
+	// MaxPresetNameLength 프리셋 이름 최대 길이
+	MaxPresetNameLength = 100
+)
+
+// ErrPresetLimitExceeded 사용자당 프리셋 개수 초과
+var ErrPresetLimitExceeded = fmt.Errorf("maximum %d presets allowed", MaxPresetsPerUser)
+
+// RecommendPreset 사용자 추천 전략 프리셋
+type RecommendPreset struct {
+	ID                  int64            `json:"id"`
+	UserID              int64            `json:"user_id"`
+	Name                string           `json:"name"`
+	Request             RecommendRequest `json:"request"`
+	AutoGenerate        bool             `json:"auto_generate"`                    // 매주 추첨 전 자동 생성
+	LastGeneratedDrawNo *int             `json:"last_generated_draw_no,omitempty"` // 마지막 자동 생성 대상 회차
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// PresetRequest 프리셋 생성/수정 요청
+type PresetRequest struct {
+	Name         string           `json:"name"`
+	Request      RecommendRequest `json:"request"`
+	AutoGenerate bool             `json:"auto_generate"`
+}
+
+// validatePresetRequest 프리셋 요청 유효성 검사 (추천 요청 기본값도 함께 설정)
+func validatePresetRequest(req *PresetRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("name is required")
+	}
+	if len([]rune(req.Name)) > MaxPresetNameLength {
+		return fmt.Errorf("name must be at most %d characters", MaxPresetNameLength)
+	}
+	return validateRecommendRequest(&req.Request)
+}
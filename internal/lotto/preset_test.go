@@ -0,0 +1,52 @@
+package lotto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePresetRequest(t *testing.T) {
+	valid := RecommendRequest{MethodCodes: []string{"NUMBER_FREQUENCY"}}
+
+	tests := []struct {
+		name    string
+		req     PresetRequest
+		wantErr bool
+	}{
+		{"valid", PresetRequest{Name: "주간 기본", Request: valid}, false},
+		{"empty name", PresetRequest{Name: "   ", Request: valid}, true},
+		{"long name", PresetRequest{Name: strings.Repeat("가", MaxPresetNameLength+1), Request: valid}, true},
+		{"no methods", PresetRequest{Name: "빈 기법", Request: RecommendRequest{}}, true},
+		{"bad mode", PresetRequest{Name: "모드", Request: RecommendRequest{MethodCodes: []string{"BAYESIAN"}, Mode: "RANDOM"}}, true},
+		{"weights mismatch", PresetRequest{Name: "가중", Request: RecommendRequest{
+			MethodCodes: []string{"BAYESIAN"},
+			CombineCode: CombineWeightedAvg,
+			Weights:     map[string]float64{"NUMBER_FREQUENCY": 1},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePresetRequest(&tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePresetRequestDefaults(t *testing.T) {
+	req := PresetRequest{
+		Name:    "  매주 자동  ",
+		Request: RecommendRequest{MethodCodes: []string{"BAYESIAN"}, Count: 50},
+	}
+	if err := validatePresetRequest(&req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Name != "매주 자동" {
+		t.Errorf("name = %q, want trimmed", req.Name)
+	}
+	if req.Request.Count != 10 {
+		t.Errorf("count = %d, want clamped to 10", req.Request.Count)
+	}
+}
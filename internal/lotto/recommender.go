@@ -5,6 +5,8 @@ import (
 	"math"
 	"math/rand"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/example/LottoSmash/internal/logger"
@@ -14,6 +16,8 @@ const (
 	TotalNumbers    = 45
 	NumbersPerDraw  = 6
 	DefaultTopCount = 10
+
+	MaxSamplingTemperature = 5.0 // 최대 샘플링 온도
 )
 
 // Recommender 추천 엔진
@@ -21,7 +25,18 @@ type Recommender struct {
 	repo     *Repository
 	analyzer *Analyzer
 	log      *logger.Logger
-	rng      *rand.Rand
+	rng      *rand.Rand // 요청 간 공유 (lockedSource). 세트 생성 중 반복 사용은 세션 rng로
+
+	// 활성 ML 모델 캐시 (ML_MODEL 기법용)
+	mlMu    sync.RWMutex
+	mlModel *MLModel
+
+	// 회차별 기법 확률 스냅샷 (읽기는 잠금 없이, 생성은 snapshotMu로 한 번만)
+	snapshot   atomic.Pointer[probabilitySnapshot]
+	snapshotMu sync.Mutex
+
+	// 대상 회차 추천 보유자 색인 (새 추천만 점진적으로 추가)
+	holders *holderIndex
 }
 
 // NewRecommender 새 추천 엔진 생성
@@ -30,10 +45,39 @@ func NewRecommender(repo *Repository, analyzer *Analyzer, log *logger.Logger) *R
 		repo:     repo,
 		analyzer: analyzer,
 		log:      log,
-		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:      rand.New(newLockedSource(time.Now().UnixNano())),
+		holders:  newHolderIndex(),
 	}
 }
 
+// lockedSource 여러 요청(HTTP 추천, 대량 추천 작업)이 동시에 사용할 수 있는 난수 소스
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func newLockedSource(seed int64) *lockedSource {
+	return &lockedSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
 // numberScore 번호와 점수를 함께 저장하는 구조체
 type numberScore struct {
 	Number int
@@ -41,13 +85,31 @@ type numberScore struct {
 }
 
 // Recommend 추천 실행 (메인 진입점)
-func (r *Recommender) Recommend(ctx context.Context, req RecommendRequest) (*RecommendResponse, error) {
+// userID는 중복 확인 시 본인 추천을 제외하기 위해 사용하며, 비로그인이면 nil이다.
+func (r *Recommender) Recommend(ctx context.Context, req RecommendRequest, userID *int64, targetDrawNo int) (*RecommendResponse, error) {
 	if req.Count <= 0 {
 		req.Count = 1
 	}
 	if req.Count > 10 {
 		req.Count = 10
 	}
+	return r.recommend(ctx, req, userID, targetDrawNo, false, nil)
+}
+
+// RecommendBulk 대량 추천 (비동기 작업용, 최대 MaxBulkSets개)
+// 세트 간 같은 조합 없이 생성하며, 세트마다 progress(생성 완료 수)를 호출하고 ctx 취소 시 중단한다.
+func (r *Recommender) RecommendBulk(ctx context.Context, req RecommendRequest, userID *int64, targetDrawNo int, progress func(done int)) (*RecommendResponse, error) {
+	if req.Count <= 0 {
+		req.Count = 1
+	}
+	if req.Count > MaxBulkSets {
+		req.Count = MaxBulkSets
+	}
+	return r.recommend(ctx, req, userID, targetDrawNo, true, progress)
+}
+
+// recommend 세트 개수가 정해진 요청으로 추천 실행
+func (r *Recommender) recommend(ctx context.Context, req RecommendRequest, userID *int64, targetDrawNo int, distinct bool, progress func(done int)) (*RecommendResponse, error) {
 	if req.CombineCode == "" {
 		req.CombineCode = CombineSimpleAvg
 	}
@@ -57,32 +119,124 @@ func (r *Recommender) Recommend(ctx context.Context, req RecommendRequest) (*Rec
 		return nil, err
 	}
 
-	recommendations := make([]Recommendation, 0, req.Count)
+	session, err := r.newSession(ctx, req, userID, targetDrawNo, latestDrawNo, distinct)
+	if err != nil {
+		return nil, err
+	}
 
-	for i := 0; i < req.Count; i++ {
-		rec, err := r.generateSingleRecommendation(ctx, req)
-		if err != nil {
-			return nil, err
-		}
-		recommendations = append(recommendations, *rec)
+	recommendations, err := r.generate(ctx, req, session, progress)
+	if err != nil {
+		return nil, err
 	}
 
 	return &RecommendResponse{
 		Recommendations: recommendations,
 		GeneratedAt:     time.Now(),
 		LatestDrawNo:    latestDrawNo,
+		TargetDrawNo:    targetDrawNo,
 	}, nil
 }
 
-// generateSingleRecommendation 단일 추천 생성
-func (r *Recommender) generateSingleRecommendation(ctx context.Context, req RecommendRequest) (*Recommendation, error) {
-	details := make(map[string]interface{})
+// newSession 추천 요청 1건에 필요한 데이터 조회 (세트마다 반복 조회하지 않음)
+func (r *Recommender) newSession(ctx context.Context, req RecommendRequest, userID *int64, targetDrawNo, latestDrawNo int, distinct bool) (*recommendSession, error) {
+	var err error
+	session := &recommendSession{
+		previous: make([][]int, 0, req.Count),
+		distinct: distinct,
+		rng:      rand.New(rand.NewSource(r.rng.Int63())),
+	}
 
-	stats, err := r.repo.GetLatestAnalysisStats(ctx)
+	// 기법별 확률: 회차별 스냅샷을 요청 전체에서 공유
+	session.snapshot, err = r.currentSnapshot(ctx, latestDrawNo, containsCode(req.MethodCodes, MethodMLModel))
 	if err != nil {
 		return nil, err
 	}
 
+	// 같은 회차 다른 사용자 추천 및 역대 1등 조합
+	session.collisions, err = r.loadCollisionIndex(ctx, session.snapshot, req, userID, targetDrawNo)
+	if err != nil {
+		return nil, err
+	}
+
+	// 조합 최적화 모드: 평가기는 요청당 한 번만 구성
+	if req.Mode == RecommendModeOptimize {
+		session.objective, err = r.buildComboObjective(ctx, req.Objectives)
+		if err != nil {
+			return nil, err
+		}
+		session.objective.reject = session.collisions.rejected
+	}
+
+	// 번호별 설명: 근거 데이터도 요청당 한 번만 조회
+	if req.Explain {
+		session.history, err = r.loadExplainHistory(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// generate 세션 상태로 req.Count개 세트 생성
+func (r *Recommender) generate(ctx context.Context, req RecommendRequest, session *recommendSession, progress func(done int)) ([]Recommendation, error) {
+	recommendations := make([]Recommendation, 0, req.Count)
+
+	for i := 0; i < req.Count; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rec, err := r.generateSingleRecommendation(ctx, req, session)
+		if err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, *rec)
+		session.previous = append(session.previous, rec.Numbers)
+		if progress != nil {
+			progress(i + 1)
+		}
+	}
+	return recommendations, nil
+}
+
+// recommendSession 추천 요청 1건 동안 세트 간에 공유되는 상태
+type recommendSession struct {
+	snapshot   *probabilitySnapshot // 기법별 확률 스냅샷
+	objective  *comboObjective      // OPTIMIZE 모드 평가기
+	history    *explainHistory      // explain=true 시 번호별 근거 데이터
+	previous   [][]int              // 같은 응답에서 이미 생성한 조합
+	distinct   bool                 // TOP 모드에서도 이전 세트와 같은 조합 제외 (대량 추천)
+	collisions *collisionIndex      // 다른 사용자 중복 및 역대 1등 조합 확인
+	rng        *rand.Rand           // 세션 전용 난수 (최적화기 반복 루프용, 다른 요청과 공유하지 않음)
+}
+
+// rejected 추천할 수 없는 조합인지 확인 (OPTIMIZE 모드의 이전 세트 제외는 최적화기가 처리)
+func (s *recommendSession) rejected(nums []int) bool {
+	if s.collisions.rejected(nums) {
+		return true
+	}
+	if !s.distinct {
+		return false
+	}
+	mask := numbersMask(nums)
+	for _, prev := range s.previous {
+		if numbersMask(prev) == mask {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSingleRecommendation 단일 추천 생성
+// OPTIMIZE 모드면 번호별 상위 6개에서 출발해 조합 단위로 최적화하며,
+// 같은 응답의 이전 세트와 동일한 조합은 피한다.
+// 역대 1등 조합(allow_past_winners=false)과 다른 사용자와 겹치는 조합(avoid_shared=true)은
+// 제외하고 점수가 가장 높은 대체 조합으로 바꾼다.
+func (r *Recommender) generateSingleRecommendation(ctx context.Context, req RecommendRequest, session *recommendSession) (*Recommendation, error) {
+	details := make(map[string]interface{})
+	methodProbs := make(map[string]map[int]float64, len(req.MethodCodes))
+
+	stats := session.snapshot.stats
+
 	// 확률 조합 방식으로 추천
 	var scores map[int]float64
 
@@ -90,12 +244,20 @@ func (r *Recommender) generateSingleRecommendation(ctx context.Context, req Reco
 		// 각 분석기법별 확률 맵 수집
 		probMaps := make([]map[int]float64, 0, len(req.MethodCodes))
 		for _, code := range req.MethodCodes {
-			probMap := r.getMethodProbabilities(code, stats)
+			probMap, err := r.methodProbabilities(ctx, session.snapshot, code, req.HalfLife)
+			if err != nil {
+				return nil, err
+			}
 			probMaps = append(probMaps, probMap)
-			details[code] = map[string]interface{}{
+			methodProbs[code] = probMap
+			methodDetails := map[string]interface{}{
 				"method": code,
 				"type":   "probability_based",
 			}
+			if code == MethodDecayFrequency {
+				methodDetails["half_life"] = decayHalfLifeOrDefault(req.HalfLife)
+			}
+			details[code] = methodDetails
 		}
 
 		// 조합 방법 적용
@@ -118,7 +280,7 @@ func (r *Recommender) generateSingleRecommendation(ctx context.Context, req Reco
 		// 기존 순위 기반 방식 (하위 호환)
 		scores = make(map[int]float64)
 		for _, code := range req.MethodCodes {
-			candidates, methodDetails, err := r.getMethodCandidates(ctx, code, stats)
+			candidates, methodDetails, err := r.snapshotCandidates(ctx, session.snapshot, code, req)
 			if err != nil {
 				r.log.Errorf("failed to get candidates for %s: %v", code, err)
 				continue
@@ -131,10 +293,38 @@ func (r *Recommender) generateSingleRecommendation(ctx context.Context, req Reco
 		}
 	}
 
-	// 점수 기준 상위 6개 선택
-	numbers := r.selectTopNumbers(scores, NumbersPerDraw)
+	// 점수 기준 상위 6개 선택 (온도가 있으면 점수 비례 샘플링)
+	var numbers []int
+	if req.Temperature > 0 {
+		numbers = r.sampleNumbers(scores, NumbersPerDraw, req.Temperature)
+	} else {
+		numbers = r.selectTopNumbers(scores, NumbersPerDraw)
+	}
+	regenerated := session.rejected(numbers)
+
+	// 조합 최적화 (OPTIMIZE 모드)
+	var report *OptimizationReport
+	if session.objective != nil {
+		numbers, report = session.objective.optimize(session.rng, scores, numbers, session.previous, defaultOptimizeIterations)
+	}
+
+	// 최적화로도 피하지 못한 제외 대상 조합은 점수 기준 대체 조합으로 교체
+	if session.rejected(numbers) {
+		if alt := session.collisions.alternative(scores, session.previous); alt != nil {
+			numbers = alt
+			report = nil // 최적화 결과가 아니므로 기여도 보고 생략
+		} else {
+			r.log.Warnf("no alternative combination found, keeping %v", numbers)
+		}
+	}
 	sort.Ints(numbers)
 
+	// 번호별 선택 근거 (explain=true)
+	var explanations []NumberExplanation
+	if session.history != nil {
+		explanations = buildExplanations(numbers, req.MethodCodes, methodProbs, scores, stats, session.history)
+	}
+
 	// 보너스 번호 선택 (요청 시)
 	var bonus *int
 	if req.IncludeBonus {
@@ -145,18 +335,24 @@ func (r *Recommender) generateSingleRecommendation(ctx context.Context, req Reco
 	// 신뢰도 계산
 	confidence := r.calculateCombineConfidence(numbers, scores, len(req.MethodCodes))
 
-	return &Recommendation{
+	rec := &Recommendation{
 		Numbers:       numbers,
 		Bonus:         bonus,
 		MethodsUsed:   req.MethodCodes,
 		CombineMethod: req.CombineCode,
 		Confidence:    confidence,
 		Details:       details,
-	}, nil
+		Optimization:  report,
+		Explanations:  explanations,
+		Regenerated:   regenerated,
+	}
+	session.collisions.annotate(rec)
+
+	return rec, nil
 }
 
 // getMethodCandidates 분석 방법별 후보 번호 추출
-func (r *Recommender) getMethodCandidates(ctx context.Context, code string, stats []AnalysisStat) ([]int, map[string]interface{}, error) {
+func (r *Recommender) getMethodCandidates(ctx context.Context, code string, stats []AnalysisStat, req RecommendRequest) ([]int, map[string]interface{}, error) {
 	switch code {
 	case "NUMBER_FREQUENCY":
 		return r.recommendByFrequency(stats)
@@ -178,6 +374,10 @@ func (r *Recommender) getMethodCandidates(ctx context.Context, code string, stat
 		return r.recommendByBayesian(stats)
 	case "HOT_COLD":
 		return r.recommendByHotCold(ctx)
+	case MethodMLModel:
+		return r.recommendByMLModel(ctx, stats)
+	case MethodDecayFrequency:
+		return r.recommendByDecayFrequency(ctx, req.HalfLife)
 	default:
 		return r.recommendByBayesian(stats) // 기본값
 	}
@@ -496,6 +696,65 @@ func (r *Recommender) recommendByHotCold(ctx context.Context) ([]int, map[string
 	return candidates, details, nil
 }
 
+// recommendByMLModel ML 모델 예측 확률 기반 추천
+func (r *Recommender) recommendByMLModel(ctx context.Context, stats []AnalysisStat) ([]int, map[string]interface{}, error) {
+	probMap, err := r.getMLProbabilities(ctx, stats)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scored := make([]numberScore, 0, len(probMap))
+	for num, p := range probMap {
+		scored = append(scored, numberScore{Number: num, Score: p})
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+
+	candidates := make([]int, 0, DefaultTopCount)
+	for i := 0; i < DefaultTopCount && i < len(scored); i++ {
+		candidates = append(candidates, scored[i].Number)
+	}
+
+	details := map[string]interface{}{
+		"top_numbers": candidates,
+		"method":      "ML 모델 예측 확률 기준 상위 번호",
+	}
+	if model := r.cachedMLModel(); model != nil {
+		details["model_version"] = model.Version
+	}
+
+	return candidates, details, nil
+}
+
+// recommendByDecayFrequency 시간 감쇠 빈도 기반 추천
+func (r *Recommender) recommendByDecayFrequency(ctx context.Context, halfLife int) ([]int, map[string]interface{}, error) {
+	halfLife = decayHalfLifeOrDefault(halfLife)
+	decayStats, err := r.analyzer.GetDecayStats(ctx, halfLife)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sorted := make([]DecayStat, len(decayStats))
+	copy(sorted, decayStats)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+
+	candidates := make([]int, 0, DefaultTopCount)
+	for i := 0; i < DefaultTopCount && i < len(sorted); i++ {
+		candidates = append(candidates, sorted[i].Number)
+	}
+
+	details := map[string]interface{}{
+		"top_numbers": candidates,
+		"half_life":   halfLife,
+		"method":      "시간 감쇠 가중 빈도 기준 상위 번호",
+	}
+
+	return candidates, details, nil
+}
+
 // selectTopNumbers 점수 기준 상위 N개 번호 선택
 func (r *Recommender) selectTopNumbers(scores map[int]float64, count int) []int {
 	scoreSlice := make([]numberScore, 0, len(scores))
@@ -534,6 +793,61 @@ func (r *Recommender) selectTopNumbers(scores map[int]float64, count int) []int
 	return numbers
 }
 
+// sampleNumbers 점수 비례 비복원 샘플링으로 N개 번호 선택
+// 가중치는 (점수/최고 점수)^(1/temperature)로, 온도가 낮을수록 상위 번호에 집중되고 높을수록 균등 선택에 가깝다.
+func (r *Recommender) sampleNumbers(scores map[int]float64, count int, temperature float64) []int {
+	maxScore := 0.0
+	for _, score := range scores {
+		maxScore = math.Max(maxScore, score)
+	}
+	if maxScore <= 0 {
+		return r.selectTopNumbers(scores, count)
+	}
+
+	var weights [TotalNumbers + 1]float64
+	for num, score := range scores {
+		if num >= 1 && num <= TotalNumbers && score > 0 {
+			weights[num] = math.Pow(score/maxScore, 1/temperature)
+		}
+	}
+
+	numbers := make([]int, 0, count)
+	for len(numbers) < count {
+		total := 0.0
+		for _, w := range weights {
+			total += w
+		}
+		if total <= 0 {
+			break
+		}
+
+		x := r.rng.Float64() * total
+		picked := 0
+		for num := 1; num <= TotalNumbers; num++ {
+			if weights[num] == 0 {
+				continue
+			}
+			picked = num
+			if x -= weights[num]; x < 0 {
+				break
+			}
+		}
+		numbers = append(numbers, picked)
+		weights[picked] = 0
+	}
+
+	// 점수가 있는 번호가 부족하면 나머지 번호 중 랜덤
+	chosen := numbersMask(numbers)
+	for len(numbers) < count {
+		n := r.rng.Intn(TotalNumbers) + 1
+		if chosen&(1<<uint(n)) == 0 {
+			numbers = append(numbers, n)
+			chosen |= 1 << uint(n)
+		}
+	}
+	return numbers
+}
+
 // selectBonusNumber 보너스 번호 선택
 func (r *Recommender) selectBonusNumber(stats []AnalysisStat, excludeNumbers []int) int {
 	excludeSet := make(map[int]bool)
@@ -623,6 +937,8 @@ func (r *Recommender) getMethodProbabilities(code string, stats []AnalysisStat)
 			probMap[s.Number] = s.BayesianPost
 		case "HOT_COLD":
 			probMap[s.Number] = s.BayesianPost
+		case MethodMLModel:
+			probMap[s.Number] = s.BayesianPost // 활성 모델이 없을 때의 폴백
 		default:
 			probMap[s.Number] = s.TotalProb
 		}
@@ -631,6 +947,105 @@ func (r *Recommender) getMethodProbabilities(code string, stats []AnalysisStat)
 	return probMap
 }
 
+// getDecayProbabilities 시간 감쇠 빈도 확률 맵 (45개 합 = 1)
+func (r *Recommender) getDecayProbabilities(ctx context.Context, halfLife int) (map[int]float64, error) {
+	decayStats, err := r.analyzer.GetDecayStats(ctx, decayHalfLifeOrDefault(halfLife))
+	if err != nil {
+		return nil, err
+	}
+
+	probMap := make(map[int]float64, TotalNumbers)
+	for _, s := range decayStats {
+		probMap[s.Number] = s.Score
+	}
+	return probMap, nil
+}
+
+// decayHalfLifeOrDefault 요청 반감기가 없으면 기본 반감기 반환
+func decayHalfLifeOrDefault(halfLife int) int {
+	if halfLife <= 0 {
+		return DefaultDecayHalfLife
+	}
+	return halfLife
+}
+
+// getMLProbabilities 활성 ML 모델로 번호별 다음 회차 출현 확률 계산
+// 다른 기법과 척도를 맞추기 위해 45개 확률의 합이 1이 되도록 정규화한다.
+// 활성 모델이 없으면 베이지안 사후확률로 폴백한다.
+func (r *Recommender) getMLProbabilities(ctx context.Context, stats []AnalysisStat) (map[int]float64, error) {
+	model, err := r.activeMLModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if model == nil {
+		r.log.Warnf("no active ml model, falling back to bayesian posterior")
+		return r.getMethodProbabilities(MethodMLModel, stats), nil
+	}
+
+	lastAppear, err := r.repo.GetLastAppearDrawNos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	probMap := make(map[int]float64, TotalNumbers)
+	sum := 0.0
+	for _, s := range stats {
+		p := model.Predict(mlFeatures(s, s.DrawNo-lastAppear[s.Number]))
+		probMap[s.Number] = p
+		sum += p
+	}
+	if sum > 0 {
+		for num, p := range probMap {
+			probMap[num] = p / sum
+		}
+	}
+
+	return probMap, nil
+}
+
+// activeMLModel 활성 모델 조회 (버전이 바뀐 경우에만 DB에서 다시 로드)
+// CLI로 학습한 모델도 재시작 없이 반영된다.
+func (r *Recommender) activeMLModel(ctx context.Context) (*MLModel, error) {
+	version, err := r.repo.GetActiveMLModelVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		r.SetMLModel(nil)
+		return nil, nil
+	}
+
+	if cached := r.cachedMLModel(); cached != nil && cached.Version == version {
+		return cached, nil
+	}
+
+	model, err := r.repo.GetActiveMLModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if model != nil {
+		if err := model.Validate(); err != nil {
+			return nil, err
+		}
+		r.log.Infof("loaded ml model v%d (trained through draw %d)", model.Version, model.TrainedDrawNo)
+	}
+	r.SetMLModel(model)
+	return model, nil
+}
+
+// SetMLModel 캐시된 ML 모델 교체
+func (r *Recommender) SetMLModel(model *MLModel) {
+	r.mlMu.Lock()
+	defer r.mlMu.Unlock()
+	r.mlModel = model
+}
+
+func (r *Recommender) cachedMLModel() *MLModel {
+	r.mlMu.RLock()
+	defer r.mlMu.RUnlock()
+	return r.mlModel
+}
+
 // combineSimpleAverage 단순 평균 조합: 각 번호별 확률을 산술 평균
 func (r *Recommender) combineSimpleAverage(probMaps []map[int]float64) map[int]float64 {
 	if len(probMaps) == 0 {
@@ -0,0 +1,356 @@
+package lotto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MethodRegistryTTL 레지스트리 캐시 유지 시간 (다른 인스턴스에서 변경한 내용도 이 시간 안에 반영)
+const MethodRegistryTTL = time.Minute
+
+// 기법 종류 (관리자 API 경로)
+const (
+	MethodKindAnalysis = "analysis"
+	MethodKindCombine  = "combine"
+)
+
+// ErrMethodUnsupported 추천 엔진이 구현하지 않은 기법 코드
+var ErrMethodUnsupported = errors.New("method is not implemented by the recommender")
+
+// ErrInvalidMethodSelection 요청한 기법/조합 방법을 사용할 수 없음 (클라이언트 오류)
+var ErrInvalidMethodSelection = errors.New("invalid method selection")
+
+// supportedAnalysisCodes 추천 엔진이 구현한 분석 기법
+var supportedAnalysisCodes = append(append([]string(nil), snapshotMethodCodes...), MethodMLModel, MethodDecayFrequency)
+
+// supportedCombineCodes 추천 엔진이 구현한 조합 방법
+var supportedCombineCodes = []string{CombineSimpleAvg, CombineWeightedAvg, CombineBayesian, CombineGeometricMean, CombineMinMax}
+
+// MethodParams 기법별 기본 파라미터 (JSONB)
+type MethodParams struct {
+	HalfLife   int     `json:"half_life,omitempty"`    // DECAY_FREQUENCY 기본 반감기
+	Weight     float64 `json:"weight,omitempty"`       // WEIGHTED_AVG에서 가중치를 생략한 기법의 가중치
+	MinMaxMode string  `json:"min_max_mode,omitempty"` // MIN_MAX 기본 모드 (MAX / MIN)
+}
+
+// Value driver.Valuer (JSONB 저장)
+func (p MethodParams) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan sql.Scanner (JSONB 조회)
+func (p *MethodParams) Scan(src interface{}) error {
+	*p = MethodParams{}
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported default_params type %T", src)
+	}
+}
+
+// Validate 파라미터 범위 검사
+func (p MethodParams) Validate() error {
+	if p.HalfLife < 0 || p.HalfLife > MaxDecayHalfLife {
+		return fmt.Errorf("half_life must be between 0 and %d (0 = default)", MaxDecayHalfLife)
+	}
+	if p.Weight < 0 {
+		return errors.New("weight must not be negative")
+	}
+	if p.MinMaxMode != "" && p.MinMaxMode != "MAX" && p.MinMaxMode != "MIN" {
+		return errors.New("min_max_mode must be 'MAX' or 'MIN'")
+	}
+	return nil
+}
+
+// MethodCreateRequest 기법 등록 요청 (추천 엔진이 구현한 코드만 등록 가능)
+type MethodCreateRequest struct {
+	Code          string       `json:"code"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description,omitempty"`
+	Category      string       `json:"category,omitempty"`  // 분석 기법만
+	IsActive      *bool        `json:"is_active,omitempty"` // 기본값: true
+	SortOrder     int          `json:"sort_order"`
+	DefaultParams MethodParams `json:"default_params"`
+}
+
+// MethodUpdateRequest 기법 수정 요청 (지정한 필드만 변경)
+type MethodUpdateRequest struct {
+	Name          *string       `json:"name,omitempty"`
+	Description   *string       `json:"description,omitempty"`
+	Category      *string       `json:"category,omitempty"` // 분석 기법만
+	IsActive      *bool         `json:"is_active,omitempty"`
+	SortOrder     *int          `json:"sort_order,omitempty"`
+	DefaultParams *MethodParams `json:"default_params,omitempty"`
+}
+
+// MethodOrderRequest 기법 순서 변경 요청 (나열 순서대로 sort_order = 1, 2, ...)
+type MethodOrderRequest struct {
+	Codes []string `json:"codes"`
+}
+
+// MethodRegistryResponse 관리자용 전체 기법 목록 (비활성 포함)
+type MethodRegistryResponse struct {
+	AnalysisMethods []AnalysisMethod `json:"analysis_methods"`
+	CombineMethods  []CombineMethod  `json:"combine_methods"`
+	LoadedAt        time.Time        `json:"loaded_at"`
+}
+
+// validateMethodKind 기법 종류 검사
+func validateMethodKind(kind string) error {
+	if kind != MethodKindAnalysis && kind != MethodKindCombine {
+		return errors.New("kind must be 'analysis' or 'combine'")
+	}
+	return nil
+}
+
+// validateMethodCreateRequest 등록 요청 유효성 검사 (코드 정규화)
+func validateMethodCreateRequest(kind string, req *MethodCreateRequest) error {
+	if err := validateMethodKind(kind); err != nil {
+		return err
+	}
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	req.Name = strings.TrimSpace(req.Name)
+
+	supported := supportedAnalysisCodes
+	if kind == MethodKindCombine {
+		supported = supportedCombineCodes
+	}
+	if !containsCode(supported, req.Code) {
+		return fmt.Errorf("%w: %s", ErrMethodUnsupported, req.Code)
+	}
+	if req.Name == "" || len(req.Name) > 50 {
+		return errors.New("name is required (max 50 characters)")
+	}
+	if kind == MethodKindAnalysis && strings.TrimSpace(req.Category) == "" {
+		return errors.New("category is required for analysis methods")
+	}
+	return req.DefaultParams.Validate()
+}
+
+// validateMethodUpdateRequest 수정 요청 유효성 검사
+func validateMethodUpdateRequest(kind string, req *MethodUpdateRequest) error {
+	if err := validateMethodKind(kind); err != nil {
+		return err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 50 {
+			return errors.New("name is required (max 50 characters)")
+		}
+		req.Name = &name
+	}
+	if req.Category != nil && kind == MethodKindCombine {
+		return errors.New("category is only available for analysis methods")
+	}
+	if req.DefaultParams != nil {
+		return req.DefaultParams.Validate()
+	}
+	return nil
+}
+
+// validateMethodOrderRequest 순서 변경 요청 유효성 검사 (코드 정규화)
+func validateMethodOrderRequest(kind string, req *MethodOrderRequest) error {
+	if err := validateMethodKind(kind); err != nil {
+		return err
+	}
+	if len(req.Codes) == 0 {
+		return errors.New("at least one code is required")
+	}
+	seen := make(map[string]bool, len(req.Codes))
+	for i, code := range req.Codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if seen[code] {
+			return fmt.Errorf("duplicate code: %s", code)
+		}
+		seen[code] = true
+		req.Codes[i] = code
+	}
+	return nil
+}
+
+// applyAnalysisUpdate 분석 기법에 수정 요청 반영
+func applyAnalysisUpdate(m *AnalysisMethod, req MethodUpdateRequest) {
+	if req.Name != nil {
+		m.Name = *req.Name
+	}
+	if req.Description != nil {
+		m.Description = *req.Description
+	}
+	if req.Category != nil {
+		m.Category = *req.Category
+	}
+	if req.IsActive != nil {
+		m.IsActive = *req.IsActive
+	}
+	if req.SortOrder != nil {
+		m.SortOrder = *req.SortOrder
+	}
+	if req.DefaultParams != nil {
+		m.DefaultParams = *req.DefaultParams
+	}
+}
+
+// applyCombineUpdate 조합 방법에 수정 요청 반영
+func applyCombineUpdate(m *CombineMethod, req MethodUpdateRequest) {
+	if req.Name != nil {
+		m.Name = *req.Name
+	}
+	if req.Description != nil {
+		m.Description = *req.Description
+	}
+	if req.IsActive != nil {
+		m.IsActive = *req.IsActive
+	}
+	if req.SortOrder != nil {
+		m.SortOrder = *req.SortOrder
+	}
+	if req.DefaultParams != nil {
+		m.DefaultParams = *req.DefaultParams
+	}
+}
+
+// ========================================
+// 기법 레지스트리 (메모리 캐시)
+// ========================================
+
+// methodRegistry DB에서 불러온 분석 기법/조합 방법 목록 (sort_order 순, 비활성 포함, 읽기 전용)
+type methodRegistry struct {
+	analysis []AnalysisMethod
+	combine  []CombineMethod
+	loadedAt time.Time
+}
+
+// newMethodRegistry 목록 정렬 후 레지스트리 구성
+func newMethodRegistry(analysis []AnalysisMethod, combine []CombineMethod, loadedAt time.Time) *methodRegistry {
+	sort.SliceStable(analysis, func(i, j int) bool { return analysis[i].SortOrder < analysis[j].SortOrder })
+	sort.SliceStable(combine, func(i, j int) bool { return combine[i].SortOrder < combine[j].SortOrder })
+	return &methodRegistry{analysis: analysis, combine: combine, loadedAt: loadedAt}
+}
+
+// activeAnalysis 활성 분석 기법
+func (m *methodRegistry) activeAnalysis() []AnalysisMethod {
+	methods := make([]AnalysisMethod, 0, len(m.analysis))
+	for _, am := range m.analysis {
+		if am.IsActive {
+			methods = append(methods, am)
+		}
+	}
+	return methods
+}
+
+// activeCombine 활성 조합 방법
+func (m *methodRegistry) activeCombine() []CombineMethod {
+	methods := make([]CombineMethod, 0, len(m.combine))
+	for _, cm := range m.combine {
+		if cm.IsActive {
+			methods = append(methods, cm)
+		}
+	}
+	return methods
+}
+
+// analysisMethod 코드로 분석 기법 조회
+func (m *methodRegistry) analysisMethod(code string) (AnalysisMethod, bool) {
+	for _, am := range m.analysis {
+		if am.Code == code {
+			return am, true
+		}
+	}
+	return AnalysisMethod{}, false
+}
+
+// combineMethod 코드로 조합 방법 조회
+func (m *methodRegistry) combineMethod(code string) (CombineMethod, bool) {
+	for _, cm := range m.combine {
+		if cm.Code == code {
+			return cm, true
+		}
+	}
+	return CombineMethod{}, false
+}
+
+// validateVariant 실험 변형 기본 기법/조합 방법이 레지스트리에 있고 활성 상태인지 확인
+// 잘못된 코드가 있으면 배정된 사용자의 추천이 모두 실패하므로 실험 등록/수정/시작 시 검사한다.
+func (m *methodRegistry) validateVariant(c VariantConfig) error {
+	for _, code := range c.MethodCodes {
+		if am, ok := m.analysisMethod(code); !ok || !am.IsActive {
+			return fmt.Errorf("%w: method_code %s is not available", ErrInvalidMethodSelection, code)
+		}
+	}
+	if c.CombineCode != "" {
+		if cm, ok := m.combineMethod(c.CombineCode); !ok || !cm.IsActive {
+			return fmt.Errorf("%w: combine_code %s is not available", ErrInvalidMethodSelection, c.CombineCode)
+		}
+	}
+	return nil
+}
+
+// resolveRequest 활성 기법만 남기고(sort_order 순) 조합 방법 확인 후 기본 파라미터 적용
+// 조합 방법을 생략하면 활성 조합 방법 중 첫 번째를 사용한다.
+func (m *methodRegistry) resolveRequest(req *RecommendRequest) error {
+	if len(req.MethodCodes) == 0 {
+		return fmt.Errorf("%w: at least one method_code is required", ErrInvalidMethodSelection)
+	}
+
+	validCodes := make([]string, 0, len(req.MethodCodes))
+	for _, am := range m.analysis {
+		if am.IsActive && containsCode(req.MethodCodes, am.Code) {
+			validCodes = append(validCodes, am.Code)
+		}
+	}
+	if len(validCodes) == 0 {
+		return fmt.Errorf("%w: no valid method codes provided", ErrInvalidMethodSelection)
+	}
+	req.MethodCodes = validCodes
+
+	if req.CombineCode == "" {
+		active := m.activeCombine()
+		if len(active) == 0 {
+			return errors.New("no active combine method")
+		}
+		req.CombineCode = active[0].Code
+	}
+	combine, ok := m.combineMethod(req.CombineCode)
+	if !ok || !combine.IsActive {
+		return fmt.Errorf("%w: combine_code %s is not available", ErrInvalidMethodSelection, req.CombineCode)
+	}
+
+	// 기본 파라미터
+	if req.CombineCode == CombineMinMax && req.MinMaxMode == "" {
+		req.MinMaxMode = combine.DefaultParams.MinMaxMode
+	}
+	var weights map[string]float64 // 호출자의 맵을 수정하지 않도록 복사본에 기본 가중치 추가
+	for _, code := range req.MethodCodes {
+		am, _ := m.analysisMethod(code)
+		if code == MethodDecayFrequency && req.HalfLife == 0 {
+			req.HalfLife = am.DefaultParams.HalfLife
+		}
+		if req.CombineCode != CombineWeightedAvg || am.DefaultParams.Weight == 0 {
+			continue
+		}
+		if _, ok := req.Weights[code]; ok {
+			continue
+		}
+		if weights == nil {
+			weights = make(map[string]float64, len(req.MethodCodes))
+			for k, v := range req.Weights {
+				weights[k] = v
+			}
+		}
+		weights[code] = am.DefaultParams.Weight
+	}
+	if weights != nil {
+		req.Weights = weights
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package lotto
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testRegistry() *methodRegistry {
+	analysis := []AnalysisMethod{
+		{Code: MethodDecayFrequency, IsActive: true, SortOrder: 3, DefaultParams: MethodParams{HalfLife: 26, Weight: 2}},
+		{Code: "BAYESIAN", IsActive: true, SortOrder: 1},
+		{Code: "HOT_COLD", IsActive: false, SortOrder: 2},
+	}
+	combine := []CombineMethod{
+		{Code: CombineSimpleAvg, IsActive: false, SortOrder: 1},
+		{Code: CombineMinMax, IsActive: true, SortOrder: 3, DefaultParams: MethodParams{MinMaxMode: "MIN"}},
+		{Code: CombineWeightedAvg, IsActive: true, SortOrder: 2},
+	}
+	return newMethodRegistry(analysis, combine, time.Now())
+}
+
+func TestMethodParamsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  MethodParams
+		wantErr bool
+	}{
+		{"empty", MethodParams{}, false},
+		{"valid", MethodParams{HalfLife: 52, Weight: 0.5, MinMaxMode: "MIN"}, false},
+		{"half life default", MethodParams{HalfLife: 0}, false},
+		{"negative half life", MethodParams{HalfLife: -1}, true},
+		{"half life too large", MethodParams{HalfLife: MaxDecayHalfLife + 1}, true},
+		{"negative weight", MethodParams{Weight: -1}, true},
+		{"bad mode", MethodParams{MinMaxMode: "AVG"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.params.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMethodParamsScan(t *testing.T) {
+	var p MethodParams
+	if err := p.Scan([]byte(`{"half_life": 52, "min_max_mode": "MAX"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if p.HalfLife != 52 || p.MinMaxMode != "MAX" {
+		t.Errorf("params = %+v", p)
+	}
+	if err := p.Scan(nil); err != nil || p != (MethodParams{}) {
+		t.Errorf("nil scan = %+v, %v", p, err)
+	}
+}
+
+func TestValidateMethodCreateRequest(t *testing.T) {
+	req := MethodCreateRequest{Code: " min_max ", Name: "최대/최소"}
+	if err := validateMethodCreateRequest(MethodKindCombine, &req); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if req.Code != CombineMinMax {
+		t.Errorf("code = %q, want normalized", req.Code)
+	}
+
+	unknown := MethodCreateRequest{Code: "MEDIAN", Name: "중앙값"}
+	if err := validateMethodCreateRequest(MethodKindCombine, &unknown); !errors.Is(err, ErrMethodUnsupported) {
+		t.Errorf("unsupported code err = %v", err)
+	}
+
+	noCategory := MethodCreateRequest{Code: "BAYESIAN", Name: "베이지안"}
+	if err := validateMethodCreateRequest(MethodKindAnalysis, &noCategory); err == nil {
+		t.Error("analysis method without category should fail")
+	}
+
+	if err := validateMethodCreateRequest("other", &req); err == nil {
+		t.Error("unknown kind should fail")
+	}
+}
+
+func TestMethodRegistryOrderAndActive(t *testing.T) {
+	reg := testRegistry()
+
+	if reg.analysis[0].Code != "BAYESIAN" || reg.combine[0].Code != CombineSimpleAvg {
+		t.Errorf("registry not sorted by sort_order")
+	}
+	if got := len(reg.activeAnalysis()); got != 2 {
+		t.Errorf("active analysis = %d, want 2", got)
+	}
+	active := reg.activeCombine()
+	if len(active) != 2 || active[0].Code != CombineWeightedAvg {
+		t.Errorf("active combine = %+v", active)
+	}
+}
+
+func TestMethodRegistryResolveRequest(t *testing.T) {
+	reg := testRegistry()
+
+	// 비활성 기법 제외, sort_order 순 정렬, 생략한 조합 방법은 첫 번째 활성 조합
+	weights := map[string]float64{"BAYESIAN": 1}
+	req := RecommendRequest{MethodCodes: []string{MethodDecayFrequency, "HOT_COLD", "BAYESIAN"}, Weights: weights}
+	if err := reg.resolveRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.MethodCodes) != 2 || req.MethodCodes[0] != "BAYESIAN" || req.MethodCodes[1] != MethodDecayFrequency {
+		t.Errorf("method codes = %v", req.MethodCodes)
+	}
+	if req.CombineCode != CombineWeightedAvg {
+		t.Errorf("combine = %s, want first active", req.CombineCode)
+	}
+	if req.HalfLife != 26 {
+		t.Errorf("half life = %d, want default 26", req.HalfLife)
+	}
+	if req.Weights[MethodDecayFrequency] != 2 || req.Weights["BAYESIAN"] != 1 {
+		t.Errorf("weights = %v", req.Weights)
+	}
+	if _, ok := weights[MethodDecayFrequency]; ok {
+		t.Error("caller's weights map should not be modified")
+	}
+
+	// 요청 값이 있으면 기본값보다 우선
+	req = RecommendRequest{MethodCodes: []string{MethodDecayFrequency}, HalfLife: 10, CombineCode: CombineMinMax}
+	if err := reg.resolveRequest(&req); err != nil {
+		t.Fatal(err)
+	}
+	if req.HalfLife != 10 || req.MinMaxMode != "MIN" {
+		t.Errorf("req = %+v", req)
+	}
+
+	// 비활성 조합 방법, 비활성 기법만 지정
+	for _, bad := range []RecommendRequest{
+		{MethodCodes: []string{"BAYESIAN"}, CombineCode: CombineSimpleAvg},
+		{MethodCodes: []string{"BAYESIAN"}, CombineCode: CombineGeometricMean},
+		{MethodCodes: []string{"HOT_COLD"}},
+		{},
+	} {
+		if err := reg.resolveRequest(&bad); !errors.Is(err, ErrInvalidMethodSelection) {
+			t.Errorf("resolveRequest(%+v) err = %v, want ErrInvalidMethodSelection", bad, err)
+		}
+	}
+}
+
+func TestMethodRegistryValidateVariant(t *testing.T) {
+	reg := testRegistry()
+	tests := []struct {
+		name    string
+		config  VariantConfig
+		wantErr bool
+	}{
+		{"no defaults", VariantConfig{Temperature: 1}, false},
+		{"active codes", VariantConfig{MethodCodes: []string{"BAYESIAN", MethodDecayFrequency}, CombineCode: CombineMinMax}, false},
+		{"unknown method", VariantConfig{MethodCodes: []string{"BAYESAIN"}}, true},
+		{"inactive method", VariantConfig{MethodCodes: []string{"BAYESIAN", "HOT_COLD"}}, true},
+		{"unknown combine", VariantConfig{CombineCode: "SIMPLE_AVERAGE"}, true},
+		{"inactive combine", VariantConfig{CombineCode: CombineSimpleAvg}, true},
+	}
+	for _, tt := range tests {
+		err := reg.validateVariant(tt.config)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidMethodSelection) {
+			t.Errorf("%s: err = %v, want ErrInvalidMethodSelection", tt.name, err)
+		}
+	}
+}
+
+func TestGetCombineMethodsFromRegistry(t *testing.T) {
+	svc := &Service{}
+	svc.registry.Store(testRegistry())
+
+	resp := svc.GetCombineMethods()
+	if resp.TotalCount != 2 || resp.Methods[0].Code != CombineWeightedAvg {
+		t.Errorf("resp = %+v", resp)
+	}
+}
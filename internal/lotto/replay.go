@@ -0,0 +1,211 @@
+package lotto
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// MaxReplayTickets 한 번에 리플레이 가능한 최대 티켓 수
+	MaxReplayTickets = 100
+	// TicketPrice 1게임 가격 (원)
+	TicketPrice int64 = 1000
+
+	// 4등/5등 고정 당첨금 (회차 데이터에 1게임당 당첨금이 없을 때 사용)
+	fixedFourthPerGame int64 = 50000
+	fixedFifthPerGame  int64 = 5000
+)
+
+// PrizeRank 일치 개수와 보너스 일치 여부로 등수 계산 (낙첨이면 0)
+// 한국 로또 6/45 등수 기준:
+//   - 1등: 6개 번호 일치
+//   - 2등: 5개 번호 일치 + 보너스 번호 일치
+//   - 3등: 5개 번호 일치
+//   - 4등: 4개 번호 일치
+//   - 5등: 3개 번호 일치
+func PrizeRank(matchCount int, bonusMatched bool) int {
+	switch {
+	case matchCount == 6:
+		return 1
+	case matchCount == 5 && bonusMatched:
+		return 2
+	case matchCount == 5:
+		return 3
+	case matchCount == 4:
+		return 4
+	case matchCount == 3:
+		return 5
+	}
+	return 0
+}
+
+// ReplayRequest 역대 당첨 리플레이 요청
+type ReplayRequest struct {
+	Tickets [][]int `json:"tickets"` // 6개 번호 조합 목록
+}
+
+// ReplayWin 리플레이 당첨 회차
+type ReplayWin struct {
+	DrawNo          int    `json:"draw_no"`
+	DrawDate        string `json:"draw_date"`
+	Rank            int    `json:"rank"`
+	MatchedNumbers  []int  `json:"matched_numbers"`
+	BonusMatched    bool   `json:"bonus_matched"`
+	Payout          int64  `json:"payout"`                     // 1게임당 당첨금 (원)
+	PayoutEstimated bool   `json:"payout_estimated,omitempty"` // 회차 데이터 누락으로 고정 당첨금 사용
+}
+
+// ReplayResult 티켓별 리플레이 결과
+type ReplayResult struct {
+	Numbers       []int       `json:"numbers"`
+	Wins          []ReplayWin `json:"wins"`
+	RankCounts    map[int]int `json:"rank_counts"`    // 등수 -> 당첨 횟수
+	DrawsPlayed   int         `json:"draws_played"`   // 1회차부터 매주 1게임 구매 가정
+	TotalCost     int64       `json:"total_cost"`     // 총 구매 금액 (원)
+	TotalWinnings int64       `json:"total_winnings"` // 총 당첨금 (원)
+	NetProfit     int64       `json:"net_profit"`     // 순손익 (원)
+	ROI           float64     `json:"roi"`            // 수익률 (%)
+	BestRank      int         `json:"best_rank,omitempty"`
+}
+
+// ReplayResponse 역대 당첨 리플레이 응답
+type ReplayResponse struct {
+	Tickets       []ReplayResult `json:"tickets"`
+	TicketPrice   int64          `json:"ticket_price"`
+	TotalDraws    int            `json:"total_draws"`
+	FirstDrawNo   int            `json:"first_draw_no"`
+	LatestDrawNo  int            `json:"latest_draw_no"`
+	TotalCost     int64          `json:"total_cost"`     // 전체 티켓 합계
+	TotalWinnings int64          `json:"total_winnings"` // 전체 티켓 합계
+	ROI           float64        `json:"roi"`            // 전체 티켓 수익률 (%)
+}
+
+// replayDraw 비트마스크로 변환한 당첨 회차
+type replayDraw struct {
+	drawNo   int
+	drawDate string
+	mask     uint64 // 당첨번호 6개 (bit n = 번호 n)
+	bonus    uint64 // 보너스 번호
+	perGame  [6]int64
+}
+
+// numbersMask 번호 목록을 비트마스크로 변환
+func numbersMask(nums []int) uint64 {
+	var mask uint64
+	for _, n := range nums {
+		mask |= 1 << uint(n)
+	}
+	return mask
+}
+
+// maskNumbers 비트마스크를 오름차순 번호 목록으로 변환
+func maskNumbers(mask uint64) []int {
+	nums := make([]int, 0, bits.OnesCount64(mask))
+	for mask != 0 {
+		n := bits.TrailingZeros64(mask)
+		nums = append(nums, n)
+		mask &= mask - 1
+	}
+	return nums
+}
+
+// compileReplayDraws 당첨번호를 비트마스크 형태로 변환 (회차순)
+func compileReplayDraws(draws []*LottoDraw) []replayDraw {
+	compiled := make([]replayDraw, 0, len(draws))
+	for _, d := range draws {
+		compiled = append(compiled, replayDraw{
+			drawNo:   d.DrawNo,
+			drawDate: d.DrawDate,
+			mask:     numbersMask(d.Numbers()),
+			bonus:    1 << uint(d.BonusNum),
+			perGame:  [6]int64{0, d.FirstPerGame, d.SecondPerGame, d.ThirdPerGame, d.FourthPerGame, d.FifthPerGame},
+		})
+	}
+	sort.Slice(compiled, func(i, j int) bool { return compiled[i].drawNo < compiled[j].drawNo })
+	return compiled
+}
+
+// payout 등수별 1게임당 당첨금 (4등/5등은 누락 시 고정 금액)
+func (d replayDraw) payout(rank int) (int64, bool) {
+	amount := d.perGame[rank]
+	if amount > 0 {
+		return amount, false
+	}
+	switch rank {
+	case 4:
+		return fixedFourthPerGame, true
+	case 5:
+		return fixedFifthPerGame, true
+	}
+	return 0, false
+}
+
+// replayTickets 티켓별 리플레이 (DB 접근 없는 계산부)
+func replayTickets(tickets [][]int, draws []replayDraw) *ReplayResponse {
+	resp := &ReplayResponse{
+		Tickets:     make([]ReplayResult, 0, len(tickets)),
+		TicketPrice: TicketPrice,
+		TotalDraws:  len(draws),
+	}
+	if len(draws) > 0 {
+		resp.FirstDrawNo = draws[0].drawNo
+		resp.LatestDrawNo = draws[len(draws)-1].drawNo
+	}
+
+	for _, ticket := range tickets {
+		result := replayTicket(numbersMask(ticket), draws)
+		resp.TotalCost += result.TotalCost
+		resp.TotalWinnings += result.TotalWinnings
+		resp.Tickets = append(resp.Tickets, result)
+	}
+	resp.ROI = replayROI(resp.TotalWinnings, resp.TotalCost)
+
+	return resp
+}
+
+// replayTicket 단일 티켓 리플레이
+func replayTicket(ticket uint64, draws []replayDraw) ReplayResult {
+	result := ReplayResult{
+		Numbers:     maskNumbers(ticket),
+		Wins:        []ReplayWin{},
+		RankCounts:  make(map[int]int, 5),
+		DrawsPlayed: len(draws),
+		TotalCost:   int64(len(draws)) * TicketPrice,
+	}
+
+	for _, d := range draws {
+		matched := ticket & d.mask
+		rank := PrizeRank(bits.OnesCount64(matched), ticket&d.bonus != 0)
+		if rank == 0 {
+			continue
+		}
+
+		amount, estimated := d.payout(rank)
+		result.Wins = append(result.Wins, ReplayWin{
+			DrawNo:          d.drawNo,
+			DrawDate:        d.drawDate,
+			Rank:            rank,
+			MatchedNumbers:  maskNumbers(matched),
+			BonusMatched:    ticket&d.bonus != 0,
+			Payout:          amount,
+			PayoutEstimated: estimated,
+		})
+		result.RankCounts[rank]++
+		result.TotalWinnings += amount
+		if result.BestRank == 0 || rank < result.BestRank {
+			result.BestRank = rank
+		}
+	}
+
+	result.NetProfit = result.TotalWinnings - result.TotalCost
+	result.ROI = replayROI(result.TotalWinnings, result.TotalCost)
+	return result
+}
+
+// replayROI 수익률 (%) = (당첨금 - 구매금액) / 구매금액 * 100
+func replayROI(winnings, cost int64) float64 {
+	if cost == 0 {
+		return 0
+	}
+	return float64(winnings-cost) / float64(cost) * 100
+}
@@ -0,0 +1,149 @@
+package lotto
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPrizeRank(t *testing.T) {
+	tests := []struct {
+		match int
+		bonus bool
+		want  int
+	}{
+		{6, false, 1},
+		{5, true, 2},
+		{5, false, 3},
+		{4, true, 4},
+		{3, false, 5},
+		{2, true, 0},
+		{0, false, 0},
+	}
+	for _, tt := range tests {
+		if got := PrizeRank(tt.match, tt.bonus); got != tt.want {
+			t.Errorf("PrizeRank(%d, %v) = %d, want %d", tt.match, tt.bonus, got, tt.want)
+		}
+	}
+}
+
+func TestMaskNumbersRoundTrip(t *testing.T) {
+	nums := []int{1, 9, 17, 30, 44, 45}
+	if got := maskNumbers(numbersMask(nums)); !reflect.DeepEqual(got, nums) {
+		t.Errorf("round trip = %v, want %v", got, nums)
+	}
+}
+
+func TestReplayTicket(t *testing.T) {
+	d1 := makeTestDraw(1, 1, 2, 3, 4, 5, 6)
+	d1.BonusNum = 7
+	d1.FirstPerGame = 2000000000
+	d2 := makeTestDraw(2, 1, 2, 3, 4, 5, 40)
+	d2.BonusNum = 6
+	d2.SecondPerGame = 50000000
+	d3 := makeTestDraw(3, 1, 2, 3, 30, 31, 32)
+	d3.BonusNum = 33 // 5등, 당첨금 누락 -> 고정 금액
+	d4 := makeTestDraw(4, 10, 11, 12, 13, 14, 15)
+	d4.BonusNum = 16
+
+	// 입력 순서와 무관하게 회차순으로 정렬되어야 함
+	draws := compileReplayDraws([]*LottoDraw{d3, d1, d4, d2})
+	result := replayTicket(numbersMask([]int{6, 5, 4, 3, 2, 1}), draws)
+
+	if len(result.Wins) != 3 {
+		t.Fatalf("wins = %d, want 3", len(result.Wins))
+	}
+	wantRanks := []int{1, 2, 5}
+	for i, w := range result.Wins {
+		if w.Rank != wantRanks[i] {
+			t.Errorf("win %d rank = %d, want %d", i, w.Rank, wantRanks[i])
+		}
+	}
+	if !result.Wins[1].BonusMatched {
+		t.Error("draw 2 should be bonus matched")
+	}
+	if !result.Wins[2].PayoutEstimated || result.Wins[2].Payout != fixedFifthPerGame {
+		t.Errorf("draw 3 payout = %d (estimated=%v), want fixed %d", result.Wins[2].Payout, result.Wins[2].PayoutEstimated, fixedFifthPerGame)
+	}
+	if !reflect.DeepEqual(result.Wins[2].MatchedNumbers, []int{1, 2, 3}) {
+		t.Errorf("draw 3 matched = %v", result.Wins[2].MatchedNumbers)
+	}
+
+	wantWinnings := int64(2000000000 + 50000000 + 5000)
+	if result.TotalWinnings != wantWinnings {
+		t.Errorf("winnings = %d, want %d", result.TotalWinnings, wantWinnings)
+	}
+	if result.TotalCost != 4*TicketPrice {
+		t.Errorf("cost = %d, want %d", result.TotalCost, 4*TicketPrice)
+	}
+	if result.BestRank != 1 {
+		t.Errorf("best rank = %d, want 1", result.BestRank)
+	}
+	wantROI := float64(wantWinnings-4*TicketPrice) / float64(4*TicketPrice) * 100
+	if math.Abs(result.ROI-wantROI) > 1e-9 {
+		t.Errorf("roi = %f, want %f", result.ROI, wantROI)
+	}
+}
+
+// 비트마스크 매칭 결과가 map 기반 단순 비교와 일치하는지 검증
+func TestReplayMatchesNaiveCheck(t *testing.T) {
+	draws := makeRandomDraws(300, 11)
+	rng := rand.New(rand.NewSource(5))
+	for _, d := range draws {
+		// 당첨번호와 겹치지 않는 보너스 번호
+		drawn := numbersMask(d.Numbers())
+		for d.BonusNum == 0 || drawn&(1<<uint(d.BonusNum)) != 0 {
+			d.BonusNum = rng.Intn(TotalNumbers) + 1
+		}
+	}
+	compiled := compileReplayDraws(draws)
+
+	for k := 0; k < 20; k++ {
+		ticket := rng.Perm(TotalNumbers)[:NumbersPerDraw]
+		for i := range ticket {
+			ticket[i]++
+		}
+		result := replayTicket(numbersMask(ticket), compiled)
+
+		want := 0
+		for _, d := range draws {
+			win := make(map[int]bool, NumbersPerDraw)
+			for _, n := range d.Numbers() {
+				win[n] = true
+			}
+			match, bonus := 0, false
+			for _, n := range ticket {
+				if win[n] {
+					match++
+				} else if n == d.BonusNum {
+					bonus = true
+				}
+			}
+			if PrizeRank(match, bonus) > 0 {
+				want++
+			}
+		}
+		if len(result.Wins) != want {
+			t.Errorf("ticket %v: wins = %d, want %d", ticket, len(result.Wins), want)
+		}
+	}
+}
+
+func BenchmarkReplayTickets(b *testing.B) {
+	compiled := compileReplayDraws(makeRandomDraws(1200, 3))
+	rng := rand.New(rand.NewSource(9))
+	tickets := make([][]int, MaxReplayTickets)
+	for i := range tickets {
+		ticket := rng.Perm(TotalNumbers)[:NumbersPerDraw]
+		for j := range ticket {
+			ticket[j]++
+		}
+		tickets[i] = ticket
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		replayTickets(tickets, compiled)
+	}
+}
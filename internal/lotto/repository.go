@@ -3,13 +3,22 @@ package lotto
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 var (
-	ErrDrawNotFound = errors.New("draw not found")
+	ErrDrawNotFound    = errors.New("draw not found")
+	ErrMLModelNotFound = errors.New("ml model not found")
+	ErrPresetNotFound  = errors.New("preset not found")
+	ErrTicketNotFound  = errors.New("ticket not found")
+	ErrTicketDuplicate = errors.New("ticket already registered")
+	ErrMethodNotFound  = errors.New("method not found")
+	ErrMethodDuplicate = errors.New("method already exists")
 )
 
 type Repository struct {
@@ -91,7 +100,12 @@ func (r *Repository) GetDraws(ctx context.Context, limit, offset int) ([]LottoDr
 func (r *Repository) GetAllDraws(ctx context.Context) ([]*LottoDraw, error) {
 	rows, err := r.db.QueryContext(ctx,
 		`SELECT draw_no, draw_date, num1, num2, num3, num4, num5, num6,
-		        bonus_num, first_prize, first_winners, created_at, updated_at
+		        bonus_num, first_prize, first_winners, COALESCE(first_per_game, 0),
+		        COALESCE(second_prize, 0), COALESCE(second_winners, 0), COALESCE(second_per_game, 0),
+		        COALESCE(third_prize, 0), COALESCE(third_winners, 0), COALESCE(third_per_game, 0),
+		        COALESCE(fourth_prize, 0), COALESCE(fourth_winners, 0), COALESCE(fourth_per_game, 0),
+		        COALESCE(fifth_prize, 0), COALESCE(fifth_winners, 0), COALESCE(fifth_per_game, 0),
+		        created_at, updated_at
 		 FROM lotto_draws ORDER BY draw_no ASC`,
 	)
 	if err != nil {
@@ -105,7 +119,11 @@ func (r *Repository) GetAllDraws(ctx context.Context) ([]*LottoDraw, error) {
 		if err := rows.Scan(
 			&draw.DrawNo, &draw.DrawDate,
 			&draw.Num1, &draw.Num2, &draw.Num3, &draw.Num4, &draw.Num5, &draw.Num6,
-			&draw.BonusNum, &draw.FirstPrize, &draw.FirstWinners,
+			&draw.BonusNum, &draw.FirstPrize, &draw.FirstWinners, &draw.FirstPerGame,
+			&draw.SecondPrize, &draw.SecondWinners, &draw.SecondPerGame,
+			&draw.ThirdPrize, &draw.ThirdWinners, &draw.ThirdPerGame,
+			&draw.FourthPrize, &draw.FourthWinners, &draw.FourthPerGame,
+			&draw.FifthPrize, &draw.FifthWinners, &draw.FifthPerGame,
 			&draw.CreatedAt, &draw.UpdatedAt,
 		); err != nil {
 			return nil, err
@@ -1889,17 +1907,115 @@ func (r *Repository) UpdateHighLowStatsProb(ctx context.Context, stat HighLowSta
 	return err
 }
 
+// Decay Stats Methods
+
+// UpsertDecayStats 시간 감쇠 빈도 통계 일괄 저장/업데이트 (한 회차 45개 번호)
+func (r *Repository) UpsertDecayStats(ctx context.Context, stats []DecayStat) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO lotto_decay_stats (
+			half_life, draw_no, number, weighted_count, weight_sum, score, appeared, calculated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (half_life, draw_no, number) DO UPDATE SET
+			weighted_count = EXCLUDED.weighted_count,
+			weight_sum = EXCLUDED.weight_sum,
+			score = EXCLUDED.score,
+			appeared = EXCLUDED.appeared,
+			calculated_at = NOW(),
+			updated_at = NOW()`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, stat := range stats {
+		if _, err := stmt.ExecContext(ctx,
+			stat.HalfLife, stat.DrawNo, stat.Number,
+			stat.WeightedCount, stat.WeightSum, stat.Score, stat.Appeared,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLatestDecayDrawNo 반감기별 감쇠 통계가 계산된 가장 최근 회차 번호 조회
+func (r *Repository) GetLatestDecayDrawNo(ctx context.Context, halfLife int) (int, error) {
+	var drawNo int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(draw_no), 0) FROM lotto_decay_stats WHERE half_life = $1", halfLife,
+	).Scan(&drawNo)
+	if err != nil {
+		return 0, err
+	}
+	return drawNo, nil
+}
+
+// GetDecayStatsByDrawNo 특정 회차의 감쇠 통계 조회 (번호 오름차순)
+func (r *Repository) GetDecayStatsByDrawNo(ctx context.Context, halfLife, drawNo int) ([]DecayStat, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT half_life, draw_no, number, weighted_count, weight_sum, score, appeared
+		 FROM lotto_decay_stats
+		 WHERE half_life = $1 AND draw_no = $2
+		 ORDER BY number ASC`, halfLife, drawNo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDecayStats(rows)
+}
+
+// GetLatestDecayStats 가장 최근 회차의 감쇠 통계 조회 (번호 오름차순)
+func (r *Repository) GetLatestDecayStats(ctx context.Context, halfLife int) ([]DecayStat, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT half_life, draw_no, number, weighted_count, weight_sum, score, appeared
+		 FROM lotto_decay_stats
+		 WHERE half_life = $1
+		   AND draw_no = (SELECT COALESCE(MAX(draw_no), 0) FROM lotto_decay_stats WHERE half_life = $1)
+		 ORDER BY number ASC`, halfLife,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDecayStats(rows)
+}
+
+func scanDecayStats(rows *sql.Rows) ([]DecayStat, error) {
+	var stats []DecayStat
+	for rows.Next() {
+		var s DecayStat
+		if err := rows.Scan(
+			&s.HalfLife, &s.DrawNo, &s.Number,
+			&s.WeightedCount, &s.WeightSum, &s.Score, &s.Appeared,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
 // ========================================
 // Analysis Methods (분석 방법)
 // ========================================
 
-// GetActiveAnalysisMethods 활성화된 분석 방법 목록 조회
-func (r *Repository) GetActiveAnalysisMethods(ctx context.Context) ([]AnalysisMethod, error) {
+// GetAllAnalysisMethods 전체 분석 방법 목록 조회 (비활성 포함)
+func (r *Repository) GetAllAnalysisMethods(ctx context.Context) ([]AnalysisMethod, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, code, name, description, category, is_active, sort_order, created_at, updated_at
+		`SELECT id, code, name, description, category, is_active, sort_order, default_params, created_at, updated_at
 		 FROM analysis_methods
-		 WHERE is_active = true
-		 ORDER BY sort_order ASC`,
+		 ORDER BY sort_order ASC, id ASC`,
 	)
 	if err != nil {
 		return nil, err
@@ -1912,7 +2028,7 @@ func (r *Repository) GetActiveAnalysisMethods(ctx context.Context) ([]AnalysisMe
 		var description sql.NullString
 		if err := rows.Scan(
 			&m.ID, &m.Code, &m.Name, &description, &m.Category,
-			&m.IsActive, &m.SortOrder, &m.CreatedAt, &m.UpdatedAt,
+			&m.IsActive, &m.SortOrder, &m.DefaultParams, &m.CreatedAt, &m.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1924,30 +2040,54 @@ func (r *Repository) GetActiveAnalysisMethods(ctx context.Context) ([]AnalysisMe
 	return methods, rows.Err()
 }
 
-// GetAnalysisMethodsByCodes 코드로 분석 방법 조회
-func (r *Repository) GetAnalysisMethodsByCodes(ctx context.Context, codes []string) ([]AnalysisMethod, error) {
-	if len(codes) == 0 {
-		return nil, nil
+// CreateAnalysisMethod 분석 방법 등록 (같은 코드가 있으면 ErrMethodDuplicate)
+func (r *Repository) CreateAnalysisMethod(ctx context.Context, m *AnalysisMethod) error {
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO analysis_methods (code, name, description, category, is_active, sort_order, default_params)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (code) DO NOTHING
+		 RETURNING id, created_at, updated_at`,
+		m.Code, m.Name, m.Description, m.Category, m.IsActive, m.SortOrder, m.DefaultParams,
+	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMethodDuplicate
 	}
+	return err
+}
 
-	query := `SELECT id, code, name, description, category, is_active, sort_order, created_at, updated_at
-			  FROM analysis_methods
-			  WHERE code = ANY($1) AND is_active = true
-			  ORDER BY sort_order ASC`
+// UpdateAnalysisMethod 분석 방법 수정 (코드 기준)
+func (r *Repository) UpdateAnalysisMethod(ctx context.Context, m *AnalysisMethod) error {
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE analysis_methods
+		 SET name = $2, description = $3, category = $4, is_active = $5, sort_order = $6, default_params = $7, updated_at = NOW()
+		 WHERE code = $1
+		 RETURNING id, created_at, updated_at`,
+		m.Code, m.Name, m.Description, m.Category, m.IsActive, m.SortOrder, m.DefaultParams,
+	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMethodNotFound
+	}
+	return err
+}
 
-	rows, err := r.db.QueryContext(ctx, query, codes)
+// GetAllCombineMethods 전체 조합 방법 목록 조회 (비활성 포함)
+func (r *Repository) GetAllCombineMethods(ctx context.Context) ([]CombineMethod, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT code, name, description, is_active, sort_order, default_params
+		 FROM combine_methods
+		 ORDER BY sort_order ASC, id ASC`,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var methods []AnalysisMethod
+	var methods []CombineMethod
 	for rows.Next() {
-		var m AnalysisMethod
+		var m CombineMethod
 		var description sql.NullString
 		if err := rows.Scan(
-			&m.ID, &m.Code, &m.Name, &description, &m.Category,
-			&m.IsActive, &m.SortOrder, &m.CreatedAt, &m.UpdatedAt,
+			&m.Code, &m.Name, &description, &m.IsActive, &m.SortOrder, &m.DefaultParams,
 		); err != nil {
 			return nil, err
 		}
@@ -1959,6 +2099,101 @@ func (r *Repository) GetAnalysisMethodsByCodes(ctx context.Context, codes []stri
 	return methods, rows.Err()
 }
 
+// CreateCombineMethod 조합 방법 등록 (같은 코드가 있으면 ErrMethodDuplicate)
+func (r *Repository) CreateCombineMethod(ctx context.Context, m *CombineMethod) error {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO combine_methods (code, name, description, is_active, sort_order, default_params)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (code) DO NOTHING`,
+		m.Code, m.Name, m.Description, m.IsActive, m.SortOrder, m.DefaultParams,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrMethodDuplicate
+	}
+	return nil
+}
+
+// UpdateCombineMethod 조합 방법 수정 (코드 기준)
+func (r *Repository) UpdateCombineMethod(ctx context.Context, m *CombineMethod) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE combine_methods
+		 SET name = $2, description = $3, is_active = $4, sort_order = $5, default_params = $6, updated_at = NOW()
+		 WHERE code = $1`,
+		m.Code, m.Name, m.Description, m.IsActive, m.SortOrder, m.DefaultParams,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrMethodNotFound
+	}
+	return nil
+}
+
+// methodTable 기법 종류별 테이블
+func methodTable(kind string) (string, error) {
+	switch kind {
+	case MethodKindAnalysis:
+		return "analysis_methods", nil
+	case MethodKindCombine:
+		return "combine_methods", nil
+	default:
+		return "", fmt.Errorf("unknown method kind: %s", kind)
+	}
+}
+
+// DeleteMethod 기법 삭제
+func (r *Repository) DeleteMethod(ctx context.Context, kind, code string) error {
+	table, err := methodTable(kind)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE code = $1`, code)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrMethodNotFound
+	}
+	return nil
+}
+
+// ReorderMethods 나열한 순서대로 sort_order 재지정 (1부터)
+// 목록에 없는 기법은 기존 순서를 유지한 채 뒤로 보낸다.
+func (r *Repository) ReorderMethods(ctx context.Context, kind string, codes []string) error {
+	table, err := methodTable(kind)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, code := range codes {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE `+table+` SET sort_order = $2, updated_at = NOW() WHERE code = $1`, code, i+1)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: %s", ErrMethodNotFound, code)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE `+table+` SET sort_order = sort_order + $2, updated_at = NOW()
+		 WHERE code <> ALL($1)`, pq.Array(codes), len(codes)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // ========================================
 // Recommendations (추천 기록)
 // ========================================
@@ -1966,45 +2201,1079 @@ func (r *Repository) GetAnalysisMethodsByCodes(ctx context.Context, codes []stri
 // SaveRecommendation 추천 기록 저장
 func (r *Repository) SaveRecommendation(ctx context.Context, rec *LottoRecommendation) error {
 	query := `
-		INSERT INTO lotto_recommendations (user_id, method_codes, combine_method, numbers, bonus_number, confidence, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		INSERT INTO lotto_recommendations (user_id, method_codes, combine_method, draw_no, numbers, bonus_number, confidence, experiment_id, variant_code, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), NOW())
 		RETURNING id, created_at`
 
 	err := r.db.QueryRowContext(ctx, query,
-		rec.UserID, rec.MethodCodes, rec.CombineMethod, rec.Numbers, rec.BonusNumber, rec.Confidence,
+		rec.UserID, pq.Array(rec.MethodCodes), rec.CombineMethod, rec.DrawNo, pq.Array(rec.Numbers), rec.BonusNumber, rec.Confidence,
+		rec.ExperimentID, rec.VariantCode,
 	).Scan(&rec.ID, &rec.CreatedAt)
 
 	return err
 }
 
-// GetRecommendationsByUserID 사용자별 추천 기록 조회
-func (r *Repository) GetRecommendationsByUserID(ctx context.Context, userID int64, limit int) ([]LottoRecommendation, error) {
-	if limit <= 0 {
-		limit = 20
+// GetRecommendationHoldersAfter 대상 회차에서 afterID 이후 저장된 추천 번호와 보유자 조회 (마지막 추천 ID 함께 반환)
+// 보유자는 로그인 사용자면 user_id, 비로그인 추천이면 추천마다 별개(-id)로 센다.
+func (r *Repository) GetRecommendationHoldersAfter(ctx context.Context, drawNo int, afterID int64) ([]RecommendationHolder, int64, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, COALESCE(user_id, -id), numbers
+		 FROM lotto_recommendations
+		 WHERE draw_no = $1 AND id > $2
+		 ORDER BY id ASC`,
+		drawNo, afterID,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var holders []RecommendationHolder
+	lastID := afterID
+	for rows.Next() {
+		var h RecommendationHolder
+		var numbers []int64
+		if err := rows.Scan(&lastID, &h.HolderID, pq.Array(&numbers)); err != nil {
+			return nil, 0, err
+		}
+		h.Numbers = make([]int, 0, len(numbers))
+		for _, n := range numbers {
+			h.Numbers = append(h.Numbers, int(n))
+		}
+		holders = append(holders, h)
 	}
+	return holders, lastID, rows.Err()
+}
 
+// GetWinningCombinations 역대 1등 당첨 조합 조회 (회차 -> 번호 6개)
+func (r *Repository) GetWinningCombinations(ctx context.Context) (map[int][]int, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, user_id, method_codes, combine_method, numbers, bonus_number, confidence, created_at
-		 FROM lotto_recommendations
-		 WHERE user_id = $1
-		 ORDER BY created_at DESC
-		 LIMIT $2`, userID, limit,
+		`SELECT draw_no, num1, num2, num3, num4, num5, num6 FROM lotto_draws`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	combos := make(map[int][]int)
+	for rows.Next() {
+		var drawNo int
+		nums := make([]int, NumbersPerDraw)
+		if err := rows.Scan(&drawNo, &nums[0], &nums[1], &nums[2], &nums[3], &nums[4], &nums[5]); err != nil {
+			return nil, err
+		}
+		combos[drawNo] = nums
+	}
+	return combos, rows.Err()
+}
+
+// GetRecommendationsByUserID 사용자별 추천 기록과 당첨 확인 결과 조회 (최신순)
+func (r *Repository) GetRecommendationsByUserID(ctx context.Context, userID int64, filter RecommendationFilter) ([]RecommendationHistoryItem, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+
+	where, args := filter.whereClause(userID)
+
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM lotto_recommendations r `+where, args...,
+	).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	// 당첨 확인 결과는 추천당 하나 (대상 회차 기준)
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT r.id, r.user_id, r.method_codes, COALESCE(r.combine_method, ''), COALESCE(r.draw_no, 0), r.numbers,
+		        r.bonus_number, COALESCE(r.confidence, 0), r.created_at,
+		        wc.draw_no, wc.matched_numbers, wc.matched_count, wc.bonus_matched, wc.prize_rank, wc.created_at
+		 FROM lotto_recommendations r
+		 LEFT JOIN winning_checks wc ON wc.recommendation_id = r.id
+		 %s
+		 ORDER BY r.created_at DESC, r.id DESC
+		 LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)),
+		args...,
 	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []RecommendationHistoryItem{}
+	for rows.Next() {
+		var item RecommendationHistoryItem
+		var drawNo, matchedCount, prizeRank sql.NullInt64
+		var bonusMatched sql.NullBool
+		var checkedAt sql.NullTime
+		var matched []int64
+		if err := rows.Scan(
+			&item.ID, &item.UserID, pq.Array(&item.MethodCodes), &item.CombineMethod, &item.DrawNo, pq.Array(&item.Numbers),
+			&item.BonusNumber, &item.Confidence, &item.CreatedAt,
+			&drawNo, pq.Array(&matched), &matchedCount, &bonusMatched, &prizeRank, &checkedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		item.Status = RecommendationPending
+		if drawNo.Valid {
+			item.Status = RecommendationSettled
+			item.Outcome = newRecommendationOutcome(drawNo, matched, matchedCount, bonusMatched, prizeRank, checkedAt)
+		}
+		items = append(items, item)
+	}
+	return items, totalCount, rows.Err()
+}
+
+// ========================================
+// Recommend Presets (추천 전략 프리셋)
+// ========================================
+
+const presetColumns = `id, user_id, name, request, auto_generate, last_generated_draw_no, created_at, updated_at`
+
+// CreatePreset 프리셋 저장
+func (r *Repository) CreatePreset(ctx context.Context, p *RecommendPreset) error {
+	request, err := json.Marshal(p.Request)
+	if err != nil {
+		return fmt.Errorf("marshal preset request: %w", err)
+	}
+
+	return r.db.QueryRowContext(ctx,
+		`INSERT INTO lotto_recommend_presets (user_id, name, request, auto_generate)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at, updated_at`,
+		p.UserID, p.Name, request, p.AutoGenerate,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+// UpdatePreset 프리셋 수정 (본인 프리셋만)
+func (r *Repository) UpdatePreset(ctx context.Context, p *RecommendPreset) error {
+	request, err := json.Marshal(p.Request)
+	if err != nil {
+		return fmt.Errorf("marshal preset request: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx,
+		`UPDATE lotto_recommend_presets
+		 SET name = $3, request = $4, auto_generate = $5, updated_at = NOW()
+		 WHERE id = $1 AND user_id = $2
+		 RETURNING created_at, updated_at`,
+		p.ID, p.UserID, p.Name, request, p.AutoGenerate,
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPresetNotFound
+	}
+	return err
+}
+
+// DeletePreset 프리셋 삭제 (본인 프리셋만)
+func (r *Repository) DeletePreset(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM lotto_recommend_presets WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPresetNotFound
+	}
+	return nil
+}
+
+// GetPreset 프리셋 단건 조회 (본인 프리셋만)
+func (r *Repository) GetPreset(ctx context.Context, userID, id int64) (*RecommendPreset, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+presetColumns+` FROM lotto_recommend_presets WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	presets, err := scanPresets(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(presets) == 0 {
+		return nil, ErrPresetNotFound
+	}
+	return &presets[0], nil
+}
+
+// GetPresetsByUserID 사용자 프리셋 목록 조회 (생성순)
+func (r *Repository) GetPresetsByUserID(ctx context.Context, userID int64) ([]RecommendPreset, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+presetColumns+` FROM lotto_recommend_presets WHERE user_id = $1 ORDER BY id ASC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPresets(rows)
+}
+
+// CountPresetsByUserID 사용자 프리셋 개수 조회
+func (r *Repository) CountPresetsByUserID(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM lotto_recommend_presets WHERE user_id = $1`, userID).Scan(&count)
+	return count, err
+}
+
+// GetPendingAutoPresets 대상 회차에 아직 자동 생성하지 않은 프리셋 조회
+func (r *Repository) GetPendingAutoPresets(ctx context.Context, targetDrawNo int) ([]RecommendPreset, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+presetColumns+` FROM lotto_recommend_presets
+		 WHERE auto_generate = true
+		   AND (last_generated_draw_no IS NULL OR last_generated_draw_no < $1)
+		 ORDER BY id ASC`, targetDrawNo)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var recs []LottoRecommendation
+	return scanPresets(rows)
+}
+
+// GeneratePresetOnce 프리셋 행을 잠근 채 대상 회차 자동 생성 실행 (이미 생성됐거나 다른 인스턴스가 실행 중이면 false)
+// generate가 성공해야 생성 회차를 기록하고, 실패하거나 프로세스가 중간에 죽으면 트랜잭션이 롤백되어 다시 시도된다.
+func (r *Repository) GeneratePresetOnce(ctx context.Context, id int64, drawNo int, generate func() error) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var locked int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM lotto_recommend_presets
+		 WHERE id = $1 AND auto_generate = true
+		   AND (last_generated_draw_no IS NULL OR last_generated_draw_no < $2)
+		 FOR UPDATE SKIP LOCKED`, id, drawNo).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := generate(); err != nil {
+		return true, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE lotto_recommend_presets SET last_generated_draw_no = $2 WHERE id = $1`, id, drawNo); err != nil {
+		return true, err
+	}
+	return true, tx.Commit()
+}
+
+func scanPresets(rows *sql.Rows) ([]RecommendPreset, error) {
+	var presets []RecommendPreset
 	for rows.Next() {
-		var rec LottoRecommendation
+		var p RecommendPreset
+		var request []byte
+		var lastDrawNo sql.NullInt64
 		if err := rows.Scan(
-			&rec.ID, &rec.UserID, &rec.MethodCodes, &rec.CombineMethod, &rec.Numbers,
-			&rec.BonusNumber, &rec.Confidence, &rec.CreatedAt,
+			&p.ID, &p.UserID, &p.Name, &request, &p.AutoGenerate, &lastDrawNo, &p.CreatedAt, &p.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
-		recs = append(recs, rec)
+		if err := json.Unmarshal(request, &p.Request); err != nil {
+			return nil, fmt.Errorf("unmarshal preset %d request: %w", p.ID, err)
+		}
+		if lastDrawNo.Valid {
+			v := int(lastDrawNo.Int64)
+			p.LastGeneratedDrawNo = &v
+		}
+		presets = append(presets, p)
+	}
+	return presets, rows.Err()
+}
+
+// ========================================
+// Ticket Wallet (구매 용지)
+// ========================================
+
+// CreateTicket 구매 용지와 게임 저장
+// 같은 QR URL을 이미 등록했다면 ErrTicketDuplicate를 반환한다.
+func (r *Repository) CreateTicket(ctx context.Context, t *Ticket) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO lotto_tickets (user_id, draw_no, source, qr_url)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id, qr_url) WHERE qr_url IS NOT NULL DO NOTHING
+		 RETURNING id, created_at`,
+		t.UserID, t.DrawNo, t.Source, t.QRURL,
+	).Scan(&t.ID, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTicketDuplicate
+	}
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO lotto_ticket_games (ticket_id, slot, numbers, mode)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i := range t.Games {
+		g := &t.Games[i]
+		if err := stmt.QueryRowContext(ctx, t.ID, g.Slot, pq.Array(g.Numbers), g.Mode).Scan(&g.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetTicketsByUserID 사용자 구매 용지 목록 조회 (회차 최신순, 게임별 당첨 결과 포함)
+func (r *Repository) GetTicketsByUserID(ctx context.Context, userID int64, limit, offset int) ([]Ticket, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM lotto_tickets WHERE user_id = $1`, userID,
+	).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, draw_no, source, qr_url, created_at
+		 FROM lotto_tickets
+		 WHERE user_id = $1
+		 ORDER BY draw_no DESC, id DESC
+		 LIMIT $2 OFFSET $3`, userID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tickets := []Ticket{}
+	index := make(map[int64]int)
+	ids := make([]int64, 0, limit)
+	for rows.Next() {
+		var t Ticket
+		if err := rows.Scan(&t.ID, &t.UserID, &t.DrawNo, &t.Source, &t.QRURL, &t.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		t.Games = []TicketGame{}
+		index[t.ID] = len(tickets)
+		ids = append(ids, t.ID)
+		tickets = append(tickets, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(ids) == 0 {
+		return tickets, totalCount, nil
+	}
+
+	gameRows, err := r.db.QueryContext(ctx,
+		`SELECT g.ticket_id, g.id, g.slot, g.numbers, g.mode,
+		        wc.draw_no, wc.matched_numbers, wc.matched_count, wc.bonus_matched, wc.prize_rank, wc.created_at
+		 FROM lotto_ticket_games g
+		 LEFT JOIN winning_checks wc ON wc.ticket_game_id = g.id
+		 WHERE g.ticket_id = ANY($1)
+		 ORDER BY g.ticket_id, g.slot`, pq.Array(ids),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer gameRows.Close()
+
+	for gameRows.Next() {
+		var ticketID int64
+		var g TicketGame
+		var drawNo, matchedCount, prizeRank sql.NullInt64
+		var bonusMatched sql.NullBool
+		var checkedAt sql.NullTime
+		var matched []int64
+		if err := gameRows.Scan(
+			&ticketID, &g.ID, &g.Slot, pq.Array(&g.Numbers), &g.Mode,
+			&drawNo, pq.Array(&matched), &matchedCount, &bonusMatched, &prizeRank, &checkedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		if drawNo.Valid {
+			g.Outcome = newRecommendationOutcome(drawNo, matched, matchedCount, bonusMatched, prizeRank, checkedAt)
+		}
+		t := &tickets[index[ticketID]]
+		t.Games = append(t.Games, g)
+	}
+	if err := gameRows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	for i := range tickets {
+		tickets[i].Status = ticketStatus(tickets[i].Games)
+	}
+	return tickets, totalCount, nil
+}
+
+// DeleteTicket 구매 용지 삭제 (본인 용지만)
+func (r *Repository) DeleteTicket(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM lotto_tickets WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTicketNotFound
+	}
+	return nil
+}
+
+// ========================================
+// ML Models (머신러닝 예측 모델)
+// ========================================
+
+// GetAllAnalysisStats 전체 회차의 통합 분석 통계 조회 (ML 학습용, 회차/번호 오름차순)
+func (r *Repository) GetAllAnalysisStats(ctx context.Context) ([]AnalysisStat, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT draw_no, number, total_count, COALESCE(total_prob, 0), bonus_count, COALESCE(bonus_prob, 0),
+		        first_count, COALESCE(first_prob, 0), last_count, COALESCE(last_prob, 0),
+		        reappear_total, reappear_count, COALESCE(reappear_prob, 0),
+		        COALESCE(bayesian_prior, 0), COALESCE(bayesian_post, 0),
+		        COALESCE(color_count, 0), COALESCE(color_prob, 0),
+		        COALESCE(row_count, 0), COALESCE(row_prob, 0),
+		        COALESCE(col_count, 0), COALESCE(col_prob, 0),
+		        appeared, calculated_at
+		 FROM lotto_analysis_stats
+		 ORDER BY draw_no ASC, number ASC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []AnalysisStat
+	for rows.Next() {
+		var stat AnalysisStat
+		if err := rows.Scan(
+			&stat.DrawNo, &stat.Number, &stat.TotalCount, &stat.TotalProb, &stat.BonusCount, &stat.BonusProb,
+			&stat.FirstCount, &stat.FirstProb, &stat.LastCount, &stat.LastProb,
+			&stat.ReappearTotal, &stat.ReappearCount, &stat.ReappearProb,
+			&stat.BayesianPrior, &stat.BayesianPost,
+			&stat.ColorCount, &stat.ColorProb, &stat.RowCount, &stat.RowProb, &stat.ColCount, &stat.ColProb,
+			&stat.Appeared, &stat.CalculatedAt,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// GetLastAppearDrawNos 번호별 마지막 출현 회차 조회 (미출현 시 0)
+func (r *Repository) GetLastAppearDrawNos(ctx context.Context) (map[int]int, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT number, COALESCE(MAX(draw_no) FILTER (WHERE appeared), 0)
+		 FROM lotto_analysis_stats
+		 GROUP BY number`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastAppear := make(map[int]int, 45)
+	for rows.Next() {
+		var number, drawNo int
+		if err := rows.Scan(&number, &drawNo); err != nil {
+			return nil, err
+		}
+		lastAppear[number] = drawNo
+	}
+	return lastAppear, rows.Err()
+}
+
+// SaveMLModel 새 버전으로 모델 저장 (activate=true면 기존 활성 모델 비활성화 후 활성화)
+func (r *Repository) SaveMLModel(ctx context.Context, model *MLModel, activate bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 동시 학습 시 버전 충돌 방지
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE lotto_ml_models IN EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) + 1 FROM lotto_ml_models`,
+	).Scan(&model.Version); err != nil {
+		return err
+	}
+
+	if activate {
+		if _, err := tx.ExecContext(ctx, `UPDATE lotto_ml_models SET is_active = false WHERE is_active = true`); err != nil {
+			return err
+		}
+	}
+
+	// 버전이 확정된 후 직렬화
+	model.IsActive = activate
+	data, err := json.Marshal(model)
+	if err != nil {
+		return fmt.Errorf("marshal ml model: %w", err)
+	}
+
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO lotto_ml_models (version, algorithm, trained_draw_no, sample_count, log_loss, baseline_log_loss, model_data, is_active)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING created_at`,
+		model.Version, model.Algorithm, model.TrainedDrawNo, model.Metrics.SampleCount,
+		model.Metrics.LogLoss, model.Metrics.BaselineLogLoss, data, activate,
+	).Scan(&model.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetActiveMLModelVersion 활성 모델 버전 조회 (없으면 0)
+func (r *Repository) GetActiveMLModelVersion(ctx context.Context) (int, error) {
+	var version int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM lotto_ml_models WHERE is_active = true`,
+	).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetActiveMLModel 활성 모델 조회 (없으면 nil)
+func (r *Repository) GetActiveMLModel(ctx context.Context) (*MLModel, error) {
+	var data []byte
+	var createdAt time.Time
+	var version int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT version, model_data, created_at FROM lotto_ml_models WHERE is_active = true`,
+	).Scan(&version, &data, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var model MLModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("unmarshal ml model v%d: %w", version, err)
+	}
+	model.Version = version
+	model.IsActive = true
+	model.CreatedAt = createdAt
+	return &model, nil
+}
+
+// GetMLModels 모델 목록 조회 (최신 버전순, 가중치 제외 요약) 및 전체 모델 수
+func (r *Repository) GetMLModels(ctx context.Context, limit int) ([]MLModel, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var totalCount int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM lotto_ml_models`).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT version, algorithm, trained_draw_no, sample_count,
+		        COALESCE(log_loss, 0), COALESCE(baseline_log_loss, 0), is_active, created_at
+		 FROM lotto_ml_models
+		 ORDER BY version DESC
+		 LIMIT $1`, limit,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var models []MLModel
+	for rows.Next() {
+		var m MLModel
+		if err := rows.Scan(
+			&m.Version, &m.Algorithm, &m.TrainedDrawNo, &m.Metrics.SampleCount,
+			&m.Metrics.LogLoss, &m.Metrics.BaselineLogLoss, &m.IsActive, &m.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		models = append(models, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return models, totalCount, nil
+}
+
+// ActivateMLModel 특정 버전 모델 활성화 (기존 활성 모델은 비활성화)
+func (r *Repository) ActivateMLModel(ctx context.Context, version int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE lotto_ml_models SET is_active = false WHERE is_active = true`); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE lotto_ml_models SET is_active = true WHERE version = $1`, version)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrMLModelNotFound
+	}
+
+	return tx.Commit()
+}
+
+// ========================================
+// Recommend Experiments (추천 A/B 실험)
+// ========================================
+
+const experimentColumns = `id, key, name, description, status, variants, started_at, stopped_at, created_at, updated_at`
+
+// CreateExperiment 실험 등록 (DRAFT, 같은 키가 있으면 ErrExperimentDuplicate)
+func (r *Repository) CreateExperiment(ctx context.Context, e *Experiment) error {
+	variants, err := json.Marshal(e.Variants)
+	if err != nil {
+		return fmt.Errorf("marshal experiment variants: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx,
+		`INSERT INTO recommend_experiments (key, name, description, status, variants)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (key) DO NOTHING
+		 RETURNING id, created_at, updated_at`,
+		e.Key, e.Name, e.Description, e.Status, variants,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrExperimentDuplicate
+	}
+	return err
+}
+
+// UpdateExperiment 실험 수정 (DRAFT 상태만, 아니면 ErrExperimentConflict)
+func (r *Repository) UpdateExperiment(ctx context.Context, e *Experiment) error {
+	variants, err := json.Marshal(e.Variants)
+	if err != nil {
+		return fmt.Errorf("marshal experiment variants: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx,
+		`UPDATE recommend_experiments
+		 SET name = $2, description = $3, variants = $4, updated_at = NOW()
+		 WHERE id = $1 AND status = 'DRAFT'
+		 RETURNING key, status, created_at, updated_at`,
+		e.ID, e.Name, e.Description, variants,
+	).Scan(&e.Key, &e.Status, &e.CreatedAt, &e.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrExperimentConflict
+	}
+	return err
+}
+
+// StartExperiment DRAFT 실험 시작 (다른 실험이 진행 중이면 ErrExperimentConflict)
+func (r *Repository) StartExperiment(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE recommend_experiments
+		 SET status = 'RUNNING', started_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'DRAFT'
+		   AND NOT EXISTS (SELECT 1 FROM recommend_experiments WHERE status = 'RUNNING')`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrExperimentConflict
+	}
+	return nil
+}
+
+// StopExperiment 진행 중 실험 종료
+func (r *Repository) StopExperiment(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE recommend_experiments
+		 SET status = 'STOPPED', stopped_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'RUNNING'`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrExperimentConflict
+	}
+	return nil
+}
+
+// GetExperiment 실험 단건 조회
+func (r *Repository) GetExperiment(ctx context.Context, id int64) (*Experiment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+experimentColumns+` FROM recommend_experiments WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	experiments, err := scanExperiments(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(experiments) == 0 {
+		return nil, ErrExperimentNotFound
+	}
+	return &experiments[0], nil
+}
+
+// GetRunningExperiment 진행 중 실험 조회 (없으면 nil)
+func (r *Repository) GetRunningExperiment(ctx context.Context) (*Experiment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+experimentColumns+` FROM recommend_experiments WHERE status = 'RUNNING' LIMIT 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	experiments, err := scanExperiments(rows)
+	if err != nil || len(experiments) == 0 {
+		return nil, err
+	}
+	return &experiments[0], nil
+}
+
+// GetExperiments 실험 목록 조회 (최신순)
+func (r *Repository) GetExperiments(ctx context.Context) ([]Experiment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+experimentColumns+` FROM recommend_experiments ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanExperiments(rows)
+}
+
+func scanExperiments(rows *sql.Rows) ([]Experiment, error) {
+	var experiments []Experiment
+	for rows.Next() {
+		var e Experiment
+		var description sql.NullString
+		var variants []byte
+		var startedAt, stoppedAt sql.NullTime
+		if err := rows.Scan(
+			&e.ID, &e.Key, &e.Name, &description, &e.Status, &variants,
+			&startedAt, &stoppedAt, &e.CreatedAt, &e.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(variants, &e.Variants); err != nil {
+			return nil, fmt.Errorf("unmarshal experiment %d variants: %w", e.ID, err)
+		}
+		if description.Valid {
+			e.Description = description.String
+		}
+		if startedAt.Valid {
+			e.StartedAt = &startedAt.Time
+		}
+		if stoppedAt.Valid {
+			e.StoppedAt = &stoppedAt.Time
+		}
+		experiments = append(experiments, e)
+	}
+	return experiments, rows.Err()
+}
+
+// GetExperimentMetrics 변형별 성과 집계
+// 추천 단위: 당첨 확인 결과(일치 개수, 5등 이상 당첨)
+// 사용자 단위: 재사용(2개 이상 회차 추천), 실험 기간 [from, to) 중 Zam 사용량
+func (r *Repository) GetExperimentMetrics(ctx context.Context, experimentID int64, from, to time.Time) ([]VariantMetrics, error) {
+	byCode := make(map[string]*VariantMetrics)
+	metric := func(code string) *VariantMetrics {
+		m, ok := byCode[code]
+		if !ok {
+			m = &VariantMetrics{Code: code}
+			byCode[code] = m
+		}
+		return m
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT r.variant_code, COUNT(*), COUNT(wc.id),
+		        COALESCE(AVG(wc.matched_count), 0), COALESCE(VAR_SAMP(wc.matched_count), 0),
+		        COUNT(wc.prize_rank)
+		 FROM lotto_recommendations r
+		 LEFT JOIN winning_checks wc ON wc.recommendation_id = r.id
+		 WHERE r.experiment_id = $1 AND r.variant_code IS NOT NULL
+		 GROUP BY r.variant_code`, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code string
+		var v VariantMetrics
+		if err := rows.Scan(&code, &v.Recommendations, &v.Checked, &v.MatchMean, &v.MatchVariance, &v.Winners); err != nil {
+			return nil, err
+		}
+		m := metric(code)
+		m.Recommendations, m.Checked, m.MatchMean, m.MatchVariance, m.Winners = v.Recommendations, v.Checked, v.MatchMean, v.MatchVariance, v.Winners
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 사용자는 변형에 고정 배정되므로 첫 추천의 변형으로 집계
+	userRows, err := r.db.QueryContext(ctx,
+		`WITH exposed AS (
+		     SELECT user_id,
+		            (ARRAY_AGG(variant_code ORDER BY id))[1] AS variant_code,
+		            COUNT(DISTINCT draw_no) AS draws
+		     FROM lotto_recommendations
+		     WHERE experiment_id = $1 AND variant_code IS NOT NULL AND user_id IS NOT NULL
+		     GROUP BY user_id
+		 ), spent AS (
+		     SELECT user_id, SUM(-amount) AS amount
+		     FROM zam_transactions
+		     WHERE amount < 0 AND created_at >= $2 AND created_at < $3
+		     GROUP BY user_id
+		 )
+		 SELECT e.variant_code, COUNT(*), COUNT(*) FILTER (WHERE e.draws >= 2),
+		        COALESCE(SUM(s.amount), 0),
+		        COALESCE(AVG(COALESCE(s.amount, 0)), 0), COALESCE(VAR_SAMP(COALESCE(s.amount, 0)), 0)
+		 FROM exposed e
+		 LEFT JOIN spent s ON s.user_id = e.user_id
+		 GROUP BY e.variant_code`, experimentID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer userRows.Close()
+
+	for userRows.Next() {
+		var code string
+		var v VariantMetrics
+		if err := userRows.Scan(&code, &v.Users, &v.RepeatUsers, &v.ZamSpent, &v.ZamMean, &v.ZamVariance); err != nil {
+			return nil, err
+		}
+		m := metric(code)
+		m.Users, m.RepeatUsers, m.ZamSpent, m.ZamMean, m.ZamVariance = v.Users, v.RepeatUsers, v.ZamSpent, v.ZamMean, v.ZamVariance
+	}
+	if err := userRows.Err(); err != nil {
+		return nil, err
+	}
+
+	metrics := make([]VariantMetrics, 0, len(byCode))
+	for _, m := range byCode {
+		metrics = append(metrics, *m)
+	}
+	return metrics, nil
+}
+
+// ========================================
+// Bulk Jobs (대량 추천 작업)
+// ========================================
+
+const bulkJobColumns = `id, user_id, type, status, total, completed, COALESCE(error, ''), created_at, started_at, finished_at`
+
+// InsertBulkJob 사용자 미종료 작업 수 제한 확인 후 작업 등록 (여러 서버 공통 제한)
+func (r *Repository) InsertBulkJob(ctx context.Context, job BulkJob, maxActive int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 같은 사용자의 동시 제출 직렬화
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, job.UserID); err != nil {
+		return err
+	}
+	var active int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM lotto_bulk_jobs WHERE user_id = $1 AND finished_at IS NULL`, job.UserID,
+	).Scan(&active); err != nil {
+		return err
+	}
+	if active >= maxActive {
+		return ErrBulkJobLimitExceeded
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO lotto_bulk_jobs (id, user_id, type, status, total, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		job.ID, job.UserID, job.Type, job.Status, job.Total, job.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteBulkJob 큐에 넣지 못한 작업 삭제
+func (r *Repository) DeleteBulkJob(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM lotto_bulk_jobs WHERE id = $1`, id)
+	return err
+}
+
+// MarkBulkJobStarted 실행 시작 기록 (대기 중 다른 서버에서 취소되어 이미 종료된 작업이면 false)
+func (r *Repository) MarkBulkJobStarted(ctx context.Context, id string, startedAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE lotto_bulk_jobs SET status = $2, started_at = $3, updated_at = NOW()
+		 WHERE id = $1 AND finished_at IS NULL`,
+		id, BulkJobRunning, startedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// UpdateBulkJobProgress 진행률 기록 후 작업을 멈춰야 하는지 반환 (다른 서버에서 취소 요청, 이미 종료 처리됨)
+func (r *Repository) UpdateBulkJobProgress(ctx context.Context, id string, completed int) (bool, error) {
+	var cancelRequested bool
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE lotto_bulk_jobs SET completed = $2, updated_at = NOW()
+		 WHERE id = $1 AND finished_at IS NULL
+		 RETURNING cancel_requested`,
+		id, completed).Scan(&cancelRequested)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	return cancelRequested, err
+}
+
+// FinishBulkJob 작업 종료 상태와 결과 기록 (이미 종료된 작업은 그대로 둠)
+func (r *Repository) FinishBulkJob(ctx context.Context, job BulkJob, result *BulkJobResult) error {
+	var resultJSON []byte
+	if result != nil {
+		var err error
+		if resultJSON, err = json.Marshal(result); err != nil {
+			return err
+		}
+	}
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE lotto_bulk_jobs
+		 SET status = $2, completed = $3, error = NULLIF($4, ''), result = $5, finished_at = $6, updated_at = NOW()
+		 WHERE id = $1 AND finished_at IS NULL`,
+		job.ID, job.Status, job.Completed, job.Error, resultJSON, job.FinishedAt)
+	return err
+}
+
+// RequestBulkJobCancel 취소 요청 기록 (대기 중인 작업은 바로 취소, 실행 중인 작업은 실행 서버가 진행 기록 때 중단)
+func (r *Repository) RequestBulkJobCancel(ctx context.Context, userID int64, id string) (BulkJob, error) {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE lotto_bulk_jobs
+		 SET cancel_requested = true,
+		     status = CASE WHEN status = $3 THEN $4 ELSE status END,
+		     finished_at = CASE WHEN status = $3 THEN NOW() ELSE finished_at END,
+		     updated_at = NOW()
+		 WHERE id = $1 AND user_id = $2 AND finished_at IS NULL`,
+		id, userID, BulkJobQueued, BulkJobCancelled)
+	if err != nil {
+		return BulkJob{}, err
+	}
+	return r.GetBulkJob(ctx, userID, id)
+}
+
+// GetBulkJob 사용자 본인 작업 조회
+func (r *Repository) GetBulkJob(ctx context.Context, userID int64, id string) (BulkJob, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+bulkJobColumns+` FROM lotto_bulk_jobs WHERE id = $1 AND user_id = $2`, id, userID)
+	job, err := scanBulkJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return BulkJob{}, ErrBulkJobNotFound
+	}
+	return job, err
+}
+
+// GetBulkJobsByUserID 사용자 작업 목록 (미종료 작업과 finishedAfter 이후 종료된 작업, 최신순)
+func (r *Repository) GetBulkJobsByUserID(ctx context.Context, userID int64, finishedAfter time.Time) ([]BulkJob, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+bulkJobColumns+` FROM lotto_bulk_jobs
+		 WHERE user_id = $1 AND (finished_at IS NULL OR finished_at > $2)
+		 ORDER BY created_at DESC`,
+		userID, finishedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]BulkJob, 0)
+	for rows.Next() {
+		job, err := scanBulkJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// GetBulkJobResult 완료된 작업 결과 조회 (아직 결과가 없으면 ErrBulkJobNotReady)
+func (r *Repository) GetBulkJobResult(ctx context.Context, userID int64, id string) (*BulkJobResult, error) {
+	var raw []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT result FROM lotto_bulk_jobs WHERE id = $1 AND user_id = $2`, id, userID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBulkJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, ErrBulkJobNotReady
+	}
+
+	var result BulkJobResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PruneBulkJobs 보관 기간이 지난 종료 작업 삭제, stale 동안 진행 기록이 없는 미종료 작업은 실패 처리 (실행 서버 중단)
+func (r *Repository) PruneBulkJobs(ctx context.Context, retention, stale time.Duration) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM lotto_bulk_jobs WHERE finished_at < NOW() - $1 * INTERVAL '1 second'`,
+		int(retention.Seconds())); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE lotto_bulk_jobs
+		 SET status = $2, error = 'interrupted: server stopped before the job finished', finished_at = NOW(), updated_at = NOW()
+		 WHERE finished_at IS NULL AND updated_at < NOW() - $1 * INTERVAL '1 second'`,
+		int(stale.Seconds()), BulkJobFailed)
+	return err
+}
+
+func scanBulkJob(row interface{ Scan(...interface{}) error }) (BulkJob, error) {
+	var job BulkJob
+	var startedAt, finishedAt sql.NullTime
+	if err := row.Scan(&job.ID, &job.UserID, &job.Type, &job.Status, &job.Total, &job.Completed, &job.Error,
+		&job.CreatedAt, &startedAt, &finishedAt); err != nil {
+		return BulkJob{}, err
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	if job.Total > 0 {
+		job.Progress = float64(job.Completed) / float64(job.Total)
 	}
-	return recs, rows.Err()
+	return job, nil
 }
@@ -0,0 +1,204 @@
+package lotto
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// MaxScoreTickets 한 번에 평가 가능한 최대 티켓 수
+const MaxScoreTickets = 10
+
+// ScoreTicketsRequest 사용자 선택 번호 평가 요청
+type ScoreTicketsRequest struct {
+	Tickets     [][]int  `json:"tickets"`                // 평가할 6개 번호 조합 목록
+	MethodCodes []string `json:"method_codes,omitempty"` // 평가 기법 (비어있으면 활성 기법 전체)
+	HalfLife    int      `json:"half_life,omitempty"`    // DECAY_FREQUENCY 반감기
+}
+
+// MethodScore 분석기법별 조합 점수
+type MethodScore struct {
+	Code        string  `json:"code"`
+	Probability float64 `json:"probability"` // 6개 번호 확률 합
+	Score       float64 `json:"score"`       // 해당 기법 상위 6개 합 대비 비율 (0~1)
+	AvgRank     float64 `json:"avg_rank"`    // 6개 번호의 평균 순위 (1~45)
+}
+
+// PatternProfile 조합 패턴 프로필
+type PatternProfile struct {
+	Sum         int            `json:"sum"`         // 번호 합계
+	OddEven     string         `json:"odd_even"`    // 홀:짝 (예: "3:3")
+	HighLow     string         `json:"high_low"`    // 고:저 (예: "3:3")
+	Consecutive int            `json:"consecutive"` // 최대 연번 길이 (없으면 0)
+	Colors      map[string]int `json:"colors"`      // 색상별 개수 (Y/B/R/G/E)
+}
+
+// TicketScore 티켓 평가 결과
+type TicketScore struct {
+	Numbers      []int          `json:"numbers"`
+	MethodScores []MethodScore  `json:"method_scores"`
+	Pattern      PatternProfile `json:"pattern"`
+	PairAffinity float64        `json:"pair_affinity"` // 15개 쌍 평균 동반 출현 확률
+	PairStrength float64        `json:"pair_strength"` // 전체 쌍 범위 대비 친화도 (0~1)
+	Popularity   float64        `json:"popularity"`    // 추정 인기도 (0~1, 낮을수록 분배 유리)
+	Overall      float64        `json:"overall"`       // 종합 점수 (0~1, 조합 최적화 기본 가중치 기준)
+	Percentile   float64        `json:"percentile"`    // 역대 당첨 조합 대비 종합 점수 백분위 (0~100)
+}
+
+// ScoreTicketsResponse 사용자 선택 번호 평가 응답
+type ScoreTicketsResponse struct {
+	Tickets         []TicketScore `json:"tickets"`
+	MethodCodes     []string      `json:"method_codes"`
+	HistoricalDraws int           `json:"historical_draws"` // 백분위 비교 대상 회차 수
+	LatestDrawNo    int           `json:"latest_draw_no"`
+}
+
+// ValidateTicket 6개 번호 조합 유효성 검사 (1~45, 중복 없음)
+func ValidateTicket(nums []int) error {
+	if len(nums) != NumbersPerDraw {
+		return fmt.Errorf("ticket must have %d numbers, got %d", NumbersPerDraw, len(nums))
+	}
+	seen := make(map[int]bool, NumbersPerDraw)
+	for _, n := range nums {
+		if n < 1 || n > TotalNumbers {
+			return fmt.Errorf("number %d out of range 1-%d", n, TotalNumbers)
+		}
+		if seen[n] {
+			return fmt.Errorf("duplicate number %d", n)
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+// ScoreTickets 사용자 선택 조합 평가
+// 기법별 확률, 패턴, 쌍 친화도를 계산하고, 같은 기준으로 역대 당첨 조합을 평가해 백분위를 낸다.
+func (r *Recommender) ScoreTickets(ctx context.Context, req ScoreTicketsRequest) (*ScoreTicketsResponse, error) {
+	latestDrawNo, err := r.repo.GetLatestDrawNo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := r.currentSnapshot(ctx, latestDrawNo, containsCode(req.MethodCodes, MethodMLModel))
+	if err != nil {
+		return nil, err
+	}
+
+	methodProbs := make(map[string]map[int]float64, len(req.MethodCodes))
+	probMaps := make([]map[int]float64, 0, len(req.MethodCodes))
+	for _, code := range req.MethodCodes {
+		probMap, err := r.methodProbabilities(ctx, snap, code, req.HalfLife)
+		if err != nil {
+			return nil, err
+		}
+		methodProbs[code] = probMap
+		probMaps = append(probMaps, probMap)
+	}
+
+	draws, err := r.repo.GetAllDraws(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pairStats, err := r.repo.GetLatestPairStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objective := newComboObjective(draws, pairStats, DefaultOptimizeWeights)
+	tickets := scoreTickets(req.Tickets, req.MethodCodes, methodProbs, r.combineSimpleAverage(probMaps), objective, draws)
+
+	return &ScoreTicketsResponse{
+		Tickets:         tickets,
+		MethodCodes:     req.MethodCodes,
+		HistoricalDraws: len(draws),
+		LatestDrawNo:    latestDrawNo,
+	}, nil
+}
+
+// scoreTickets 티켓별 평가 (DB 접근 없는 계산부)
+func scoreTickets(tickets [][]int, codes []string, methodProbs map[string]map[int]float64,
+	combined map[int]float64, objective *comboObjective, draws []*LottoDraw) []TicketScore {
+
+	methodRanks := make(map[string]map[int]int, len(methodProbs))
+	methodNorms := make(map[string]float64, len(methodProbs))
+	for code, probs := range methodProbs {
+		methodRanks[code] = rankNumbers(probs)
+		methodNorms[code] = topScoreSum(probs, NumbersPerDraw)
+	}
+	combinedNorm := topScoreSum(combined, NumbersPerDraw)
+
+	// 역대 당첨 조합의 종합 점수 분포
+	historical := make([]float64, 0, len(draws))
+	for _, d := range draws {
+		historical = append(historical, objective.total(objective.evaluate(d.Numbers(), combined, combinedNorm)))
+	}
+	sort.Float64s(historical)
+
+	results := make([]TicketScore, 0, len(tickets))
+	for _, ticket := range tickets {
+		nums := append([]int(nil), ticket...)
+		sort.Ints(nums)
+
+		ts := TicketScore{
+			Numbers:      nums,
+			MethodScores: make([]MethodScore, 0, len(codes)),
+			Pattern:      patternProfile(nums),
+			PairAffinity: objective.meanPairProb(nums),
+			Popularity:   estimatePopularity(nums),
+		}
+
+		for _, code := range codes {
+			probs, ok := methodProbs[code]
+			if !ok {
+				continue
+			}
+			ms := MethodScore{Code: code}
+			rankSum := 0
+			for _, n := range nums {
+				ms.Probability += probs[n]
+				rankSum += methodRanks[code][n]
+			}
+			if methodNorms[code] > 0 {
+				ms.Score = clamp01(ms.Probability / methodNorms[code])
+			}
+			ms.AvgRank = float64(rankSum) / float64(len(nums))
+			ts.MethodScores = append(ts.MethodScores, ms)
+		}
+
+		values := objective.evaluate(nums, combined, combinedNorm)
+		ts.PairStrength = values.Pair
+		ts.Overall = objective.total(values)
+		ts.Percentile = percentileOf(historical, ts.Overall)
+
+		results = append(results, ts)
+	}
+
+	return results
+}
+
+// patternProfile 조합 패턴 프로필 계산 (nums는 오름차순)
+func patternProfile(nums []int) PatternProfile {
+	odd := countOddNumbers(nums)
+	high := countHighNumbers(nums)
+
+	colors := make(map[string]int, 5)
+	for _, n := range nums {
+		colors[getColorForNumber(n)]++
+	}
+
+	return PatternProfile{
+		Sum:         sumNumbers(nums),
+		OddEven:     oddEvenRatioKey(odd),
+		HighLow:     highLowRatioKey(high),
+		Consecutive: countConsecutive(nums),
+		Colors:      colors,
+	}
+}
+
+// percentileOf 정렬된 분포에서 value 이하 비율 (0~100)
+func percentileOf(sorted []float64, value float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := sort.Search(len(sorted), func(i int) bool { return sorted[i] > value })
+	return float64(idx) / float64(len(sorted)) * 100
+}
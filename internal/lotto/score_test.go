@@ -0,0 +1,119 @@
+package lotto
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValidateTicket(t *testing.T) {
+	tests := []struct {
+		name    string
+		nums    []int
+		wantErr bool
+	}{
+		{"valid", []int{1, 7, 13, 22, 38, 45}, false},
+		{"too few", []int{1, 2, 3, 4, 5}, true},
+		{"out of range", []int{0, 2, 3, 4, 5, 6}, true},
+		{"over range", []int{1, 2, 3, 4, 5, 46}, true},
+		{"duplicate", []int{1, 2, 3, 4, 5, 5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTicket(tt.nums)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTicket(%v) error = %v, wantErr %v", tt.nums, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPatternProfile(t *testing.T) {
+	p := patternProfile([]int{3, 4, 5, 21, 33, 42})
+
+	if p.Sum != 108 {
+		t.Errorf("sum = %d, want 108", p.Sum)
+	}
+	if p.OddEven != "4:2" {
+		t.Errorf("odd_even = %s, want 4:2", p.OddEven)
+	}
+	if p.HighLow != "2:4" {
+		t.Errorf("high_low = %s, want 2:4", p.HighLow)
+	}
+	if p.Consecutive != 3 {
+		t.Errorf("consecutive = %d, want 3", p.Consecutive)
+	}
+	if p.Colors["Y"] != 3 || p.Colors["R"] != 1 || p.Colors["G"] != 1 || p.Colors["E"] != 1 {
+		t.Errorf("unexpected colors: %v", p.Colors)
+	}
+}
+
+func TestPercentileOf(t *testing.T) {
+	dist := []float64{0.1, 0.2, 0.3, 0.4}
+	tests := []struct {
+		value float64
+		want  float64
+	}{
+		{0.05, 0},
+		{0.2, 50},
+		{0.35, 75},
+		{0.9, 100},
+	}
+	for _, tt := range tests {
+		if got := percentileOf(dist, tt.value); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("percentileOf(%.2f) = %.1f, want %.1f", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestScoreTickets(t *testing.T) {
+	stats := makeTestStats()
+	r := &Recommender{}
+	codes := []string{"NUMBER_FREQUENCY", "REAPPEAR_PROB"}
+	methodProbs := map[string]map[int]float64{
+		"NUMBER_FREQUENCY": r.getMethodProbabilities("NUMBER_FREQUENCY", stats),
+		"REAPPEAR_PROB":    r.getMethodProbabilities("REAPPEAR_PROB", stats),
+	}
+	combined := r.combineSimpleAverage([]map[int]float64{methodProbs["NUMBER_FREQUENCY"], methodProbs["REAPPEAR_PROB"]})
+
+	draws := makeRandomDraws(200, 11)
+	objective := newComboObjective(draws, nil, DefaultOptimizeWeights)
+
+	tickets := [][]int{
+		{45, 44, 43, 42, 41, 40}, // NUMBER_FREQUENCY 상위 6개 (정렬 안 된 입력)
+		{1, 2, 3, 4, 5, 6},
+	}
+	results := scoreTickets(tickets, codes, methodProbs, combined, objective, draws)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	top := results[0]
+	if top.Numbers[0] != 40 {
+		t.Errorf("numbers should be sorted: %v", top.Numbers)
+	}
+	if len(top.MethodScores) != 2 {
+		t.Fatalf("expected 2 method scores, got %d", len(top.MethodScores))
+	}
+	if math.Abs(top.MethodScores[0].Score-1) > 1e-9 {
+		t.Errorf("top-6 NUMBER_FREQUENCY score = %.4f, want 1", top.MethodScores[0].Score)
+	}
+	if math.Abs(top.MethodScores[0].AvgRank-3.5) > 1e-9 {
+		t.Errorf("top-6 avg rank = %.2f, want 3.5", top.MethodScores[0].AvgRank)
+	}
+	if results[1].MethodScores[1].AvgRank != 3.5 { // REAPPEAR_PROB은 작은 번호가 상위
+		t.Errorf("REAPPEAR_PROB avg rank = %.2f, want 3.5", results[1].MethodScores[1].AvgRank)
+	}
+
+	for _, res := range results {
+		if res.Percentile < 0 || res.Percentile > 100 {
+			t.Errorf("percentile out of range: %.2f", res.Percentile)
+		}
+		if res.Overall < 0 || res.Overall > 1 {
+			t.Errorf("overall out of range: %.4f", res.Overall)
+		}
+	}
+	if results[1].Popularity <= results[0].Popularity {
+		t.Errorf("1-6 should be more popular (%.3f) than 40-45 (%.3f)", results[1].Popularity, results[0].Popularity)
+	}
+}
@@ -4,19 +4,25 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
 	"github.com/example/LottoSmash/internal/constants"
 	"github.com/example/LottoSmash/internal/logger"
+	"github.com/example/LottoSmash/internal/txid"
+	"github.com/example/LottoSmash/internal/worker"
 	"golang.org/x/text/encoding/korean"
 	"golang.org/x/text/transform"
 )
@@ -28,6 +34,13 @@ type Service struct {
 	recommender *Recommender
 	log         *logger.Logger
 	docsPath    string
+	checker     WinningChecker
+	jobPool     chan<- worker.Job
+	jobNotifier JobNotifier
+	bulkJobs    *bulkJobStore
+	registry    atomic.Pointer[methodRegistry] // 분석 기법/조합 방법 레지스트리 캐시
+	registryMu  sync.Mutex
+	experiment  atomic.Pointer[experimentCache] // 진행 중 A/B 실험 캐시
 
 	// Crawler settings
 	CrawlerBatchSize  int
@@ -40,12 +53,28 @@ func NewService(repo *Repository, client *Client, analyzer *Analyzer, log *logge
 		client:   client,
 		analyzer: analyzer,
 		log:      log,
+		bulkJobs: newBulkJobStore(repo, log),
 	}
 	// 추천 엔진 초기화
 	svc.recommender = NewRecommender(repo, analyzer, log)
 	return svc
 }
 
+// SetWinningChecker 이미 추첨된 회차의 용지 등록 시 즉시 당첨 확인에 사용할 checker 설정
+func (s *Service) SetWinningChecker(checker WinningChecker) {
+	s.checker = checker
+}
+
+// SetJobNotifier 대량 작업 종료 시 사용자에게 알릴 notifier 설정
+func (s *Service) SetJobNotifier(n JobNotifier) {
+	s.jobNotifier = n
+}
+
+// SetJobPool 대량 추천 작업을 실행할 워커 풀 입력 채널 설정
+func (s *Service) SetJobPool(pool chan<- worker.Job) {
+	s.jobPool = pool
+}
+
 // InitializeDraws 서버 시작 시 당첨번호 데이터 동기화 및 최신화
 func (s *Service) InitializeDraws(ctx context.Context, docsPath string) error {
 	s.docsPath = docsPath
@@ -515,9 +544,19 @@ func (s *Service) RunAnalysis(ctx context.Context) error {
 	}
 
 	s.log.Infof("lotto analysis completed successfully")
+
+	// 새 분석 결과로 추천용 확률 스냅샷 교체
+	s.refreshRecommendSnapshot(ctx)
 	return nil
 }
 
+// refreshRecommendSnapshot 추천 엔진 확률 스냅샷 재생성 (실패 시 다음 추천 요청에서 다시 생성)
+func (s *Service) refreshRecommendSnapshot(ctx context.Context) {
+	if err := s.recommender.RefreshSnapshot(ctx); err != nil {
+		s.log.Errorf("failed to refresh probability snapshot: %v", err)
+	}
+}
+
 // GetDraws 당첨번호 목록 조회
 func (s *Service) GetDraws(ctx context.Context, limit, offset int) (*DrawListResponse, error) {
 	draws, err := s.repo.GetDraws(ctx, limit, offset)
@@ -653,6 +692,37 @@ func (s *Service) GetAnalysisStatsHistory(ctx context.Context, number int, limit
 	return s.repo.GetAnalysisStatsHistory(ctx, number, limit)
 }
 
+// GetDecayStats 시간 감쇠 빈도 통계 조회 (score 내림차순)
+func (s *Service) GetDecayStats(ctx context.Context, halfLife int) (*DecayStatsResponse, error) {
+	if halfLife <= 0 {
+		halfLife = DefaultDecayHalfLife
+	}
+	if halfLife > MaxDecayHalfLife {
+		return nil, fmt.Errorf("invalid half_life: must be between 0 and %d (0 = default)", MaxDecayHalfLife)
+	}
+
+	stats, err := s.analyzer.GetDecayStats(ctx, halfLife)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Score > stats[j].Score
+	})
+
+	latestDrawNo := 0
+	if len(stats) > 0 {
+		latestDrawNo = stats[0].DrawNo
+	}
+
+	return &DecayStatsResponse{
+		Numbers:      stats,
+		HalfLife:     halfLife,
+		DecayFactor:  decayFactor(halfLife),
+		LatestDrawNo: latestDrawNo,
+	}, nil
+}
+
 // TriggerSync 수동 동기화 (관리자용)
 func (s *Service) TriggerSync(ctx context.Context) error {
 	if err := s.FetchNewDraw(ctx); err != nil {
@@ -802,70 +872,856 @@ func (s *Service) loadBundledCSV() ([]LottoDraw, error) {
 
 // GetAnalysisMethods 활성화된 분석 방법 목록 조회
 func (s *Service) GetAnalysisMethods(ctx context.Context) (*MethodListResponse, error) {
-	methods, err := s.repo.GetActiveAnalysisMethods(ctx)
+	reg, err := s.methodRegistry(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	methods := reg.activeAnalysis()
 	return &MethodListResponse{
 		Methods:    methods,
 		TotalCount: len(methods),
 	}, nil
 }
 
-// GetCombineMethods 조합 방법 목록 반환
+// GetCombineMethods 활성화된 조합 방법 목록 반환 (레지스트리를 불러오기 전이면 기본 목록)
 func (s *Service) GetCombineMethods() *CombineMethodListResponse {
+	methods := defaultCombineMethods
+	if reg := s.registry.Load(); reg != nil {
+		methods = reg.activeCombine()
+	}
 	return &CombineMethodListResponse{
-		Methods:    AllCombineMethods,
-		TotalCount: len(AllCombineMethods),
+		Methods:    methods,
+		TotalCount: len(methods),
 	}
 }
 
-// RecommendNumbers 번호 추천
-func (s *Service) RecommendNumbers(ctx context.Context, req RecommendRequest, userID *int64) (*RecommendResponse, error) {
-	// 분석 방법 유효성 검사
-	if len(req.MethodCodes) == 0 {
-		return nil, fmt.Errorf("at least one method_code is required")
+// ========================================
+// 기법 레지스트리 관리
+// ========================================
+
+// methodRegistry 캐시된 레지스트리 반환 (TTL이 지났으면 다시 불러옴)
+// 다시 불러오지 못하면 이전 레지스트리를 그대로 사용한다.
+func (s *Service) methodRegistry(ctx context.Context) (*methodRegistry, error) {
+	if reg := s.registry.Load(); reg != nil && time.Since(reg.loadedAt) < MethodRegistryTTL {
+		return reg, nil
+	}
+
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+	if reg := s.registry.Load(); reg != nil && time.Since(reg.loadedAt) < MethodRegistryTTL {
+		return reg, nil
+	}
+
+	reg, err := s.loadMethodRegistryLocked(ctx)
+	if err != nil {
+		if stale := s.registry.Load(); stale != nil {
+			s.log.Errorf("failed to reload method registry, using cached: %v", err)
+			return stale, nil
+		}
+		return nil, fmt.Errorf("failed to load method registry: %w", err)
+	}
+	return reg, nil
+}
+
+// ReloadMethodRegistry DB에서 분석 기법/조합 방법 레지스트리를 즉시 다시 불러옴
+func (s *Service) ReloadMethodRegistry(ctx context.Context) (*MethodRegistryResponse, error) {
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+
+	reg, err := s.loadMethodRegistryLocked(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload method registry: %w", err)
+	}
+	return registryResponse(reg), nil
+}
+
+// loadMethodRegistryLocked 레지스트리 조회 후 교체 (registryMu 보유 상태에서 호출)
+func (s *Service) loadMethodRegistryLocked(ctx context.Context) (*methodRegistry, error) {
+	analysis, err := s.repo.GetAllAnalysisMethods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	combine, err := s.repo.GetAllCombineMethods(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// 요청된 분석 방법들이 존재하는지 확인
-	methods, err := s.repo.GetAnalysisMethodsByCodes(ctx, req.MethodCodes)
+	reg := newMethodRegistry(analysis, combine, time.Now())
+	s.registry.Store(reg)
+	s.log.Infof("method registry loaded: %d analysis methods, %d combine methods", len(analysis), len(combine))
+	return reg, nil
+}
+
+// GetMethodRegistry 관리자용 전체 기법 목록 (비활성 포함)
+func (s *Service) GetMethodRegistry(ctx context.Context) (*MethodRegistryResponse, error) {
+	reg, err := s.methodRegistry(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate method codes: %w", err)
+		return nil, err
 	}
+	return registryResponse(reg), nil
+}
 
-	if len(methods) == 0 {
-		return nil, fmt.Errorf("no valid method codes provided")
+// CreateMethod 기법 등록 (요청은 validateMethodCreateRequest로 검증된 상태)
+func (s *Service) CreateMethod(ctx context.Context, kind string, req MethodCreateRequest) (*MethodRegistryResponse, error) {
+	isActive := req.IsActive == nil || *req.IsActive
+
+	var err error
+	if kind == MethodKindAnalysis {
+		err = s.repo.CreateAnalysisMethod(ctx, &AnalysisMethod{
+			Code: req.Code, Name: req.Name, Description: req.Description, Category: req.Category,
+			IsActive: isActive, SortOrder: req.SortOrder, DefaultParams: req.DefaultParams,
+		})
+	} else {
+		err = s.repo.CreateCombineMethod(ctx, &CombineMethod{
+			Code: req.Code, Name: req.Name, Description: req.Description,
+			IsActive: isActive, SortOrder: req.SortOrder, DefaultParams: req.DefaultParams,
+		})
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create method: %w", err)
+	}
+	return s.ReloadMethodRegistry(ctx)
+}
 
-	// 실제 존재하는 코드로 필터링
-	validCodes := make([]string, 0, len(methods))
-	for _, m := range methods {
-		validCodes = append(validCodes, m.Code)
+// UpdateMethod 기법 수정 (활성/비활성, 이름, 설명, 순서, 기본 파라미터)
+func (s *Service) UpdateMethod(ctx context.Context, kind, code string, req MethodUpdateRequest) (*MethodRegistryResponse, error) {
+	// 최신 값 기준으로 수정하도록 먼저 다시 불러옴
+	if _, err := s.ReloadMethodRegistry(ctx); err != nil {
+		return nil, err
 	}
-	req.MethodCodes = validCodes
+	reg := s.registry.Load()
 
-	// 추천 실행
-	resp, err := s.recommender.Recommend(ctx, req)
+	var err error
+	if kind == MethodKindAnalysis {
+		m, ok := reg.analysisMethod(code)
+		if !ok {
+			return nil, ErrMethodNotFound
+		}
+		applyAnalysisUpdate(&m, req)
+		err = s.repo.UpdateAnalysisMethod(ctx, &m)
+	} else {
+		m, ok := reg.combineMethod(code)
+		if !ok {
+			return nil, ErrMethodNotFound
+		}
+		applyCombineUpdate(&m, req)
+		err = s.repo.UpdateCombineMethod(ctx, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update method: %w", err)
+	}
+	return s.ReloadMethodRegistry(ctx)
+}
+
+// ReorderMethods 기법 표시 순서 변경
+func (s *Service) ReorderMethods(ctx context.Context, kind string, req MethodOrderRequest) (*MethodRegistryResponse, error) {
+	if err := s.repo.ReorderMethods(ctx, kind, req.Codes); err != nil {
+		return nil, fmt.Errorf("failed to reorder methods: %w", err)
+	}
+	return s.ReloadMethodRegistry(ctx)
+}
+
+// DeleteMethod 기법 삭제 (기존 추천 기록의 코드는 그대로 남음)
+func (s *Service) DeleteMethod(ctx context.Context, kind, code string) (*MethodRegistryResponse, error) {
+	if err := s.repo.DeleteMethod(ctx, kind, code); err != nil {
+		return nil, fmt.Errorf("failed to delete method: %w", err)
+	}
+	return s.ReloadMethodRegistry(ctx)
+}
+
+// registryResponse 레지스트리를 관리자용 응답으로 변환
+func registryResponse(reg *methodRegistry) *MethodRegistryResponse {
+	return &MethodRegistryResponse{
+		AnalysisMethods: reg.analysis,
+		CombineMethods:  reg.combine,
+		LoadedAt:        reg.loadedAt,
+	}
+}
+
+// RecommendNumbers 번호 추천
+// assignment는 AssignExperiment로 배정한 A/B 실험 변형으로(없으면 nil), 요청에서 생략한 값은 변형 기본값을 사용하고
+// 추천 기록에도 같은 변형을 남긴다. 호출자가 기본값 적용과 기록에 같은 배정을 쓰도록 한 번만 배정해 넘긴다.
+func (s *Service) RecommendNumbers(ctx context.Context, req RecommendRequest, userID *int64, assignment *ExperimentAssignment) (*RecommendResponse, error) {
+	return s.recommendForDraw(ctx, req, userID, assignment, NextDrawNo(time.Now()))
+}
+
+// recommendForDraw 대상 회차를 지정한 번호 추천 (자동 생성 프리셋은 스케줄러가 정한 회차 사용)
+func (s *Service) recommendForDraw(ctx context.Context, req RecommendRequest, userID *int64, assignment *ExperimentAssignment, targetDrawNo int) (*RecommendResponse, error) {
+	assignment.applyDefaults(&req)
+
+	if err := s.resolveRecommendMethods(ctx, &req); err != nil {
+		return nil, err
+	}
+
+	// 추천 실행 (판매 마감 기준 대상 회차의 다른 사용자 추천과 중복 확인)
+	resp, err := s.recommender.Recommend(ctx, req, userID, targetDrawNo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate recommendations: %w", err)
 	}
 
-	// 추천 기록 저장
+	// 추천 기록 저장 (대상 회차 포함)
 	for _, rec := range resp.Recommendations {
 		lottoRec := &LottoRecommendation{
 			UserID:        userID,
 			MethodCodes:   rec.MethodsUsed,
 			CombineMethod: rec.CombineMethod,
+			DrawNo:        resp.TargetDrawNo,
 			Numbers:       rec.Numbers,
 			BonusNumber:   rec.Bonus,
 			Confidence:    rec.Confidence,
 		}
+		if assignment != nil {
+			lottoRec.ExperimentID = &assignment.ExperimentID
+			lottoRec.VariantCode = assignment.Variant
+		}
 		if err := s.repo.SaveRecommendation(ctx, lottoRec); err != nil {
 			s.log.Errorf("failed to save recommendation: %v", err)
 			// 저장 실패해도 추천 결과는 반환
 		}
 	}
 
+	resp.Experiment = assignment
 	return resp, nil
 }
+
+// resolveRecommendMethods 활성 분석 방법만 남기고 조합 방법 확인 후 기법별 기본 파라미터 적용
+func (s *Service) resolveRecommendMethods(ctx context.Context, req *RecommendRequest) error {
+	if len(req.MethodCodes) == 0 {
+		return fmt.Errorf("%w: at least one method_code is required", ErrInvalidMethodSelection)
+	}
+
+	if req.HalfLife < 0 || req.HalfLife > MaxDecayHalfLife {
+		return fmt.Errorf("half_life must be between 0 and %d (0 = default)", MaxDecayHalfLife)
+	}
+
+	reg, err := s.methodRegistry(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate method codes: %w", err)
+	}
+	return reg.resolveRequest(req)
+}
+
+// GetRecommendationHistory 사용자 추천 기록과 당첨 결과 조회
+func (s *Service) GetRecommendationHistory(ctx context.Context, userID int64, filter RecommendationFilter) (*RecommendationHistoryResponse, error) {
+	items, totalCount, err := s.repo.GetRecommendationsByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommendation history: %w", err)
+	}
+	return &RecommendationHistoryResponse{
+		Recommendations: items,
+		TotalCount:      totalCount,
+	}, nil
+}
+
+// ScoreTickets 사용자 선택 번호 평가
+func (s *Service) ScoreTickets(ctx context.Context, req ScoreTicketsRequest) (*ScoreTicketsResponse, error) {
+	if len(req.Tickets) == 0 {
+		return nil, fmt.Errorf("at least one ticket is required")
+	}
+	for i, ticket := range req.Tickets {
+		if err := ValidateTicket(ticket); err != nil {
+			return nil, fmt.Errorf("ticket %d: %w", i+1, err)
+		}
+	}
+
+	// 평가 기법: 지정하지 않으면 활성 기법 전체
+	reg, err := s.methodRegistry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load analysis methods: %w", err)
+	}
+	requested := req.MethodCodes
+	req.MethodCodes = make([]string, 0, len(reg.analysis))
+	for _, m := range reg.activeAnalysis() {
+		if len(requested) == 0 || containsCode(requested, m.Code) {
+			req.MethodCodes = append(req.MethodCodes, m.Code)
+		}
+	}
+	if len(req.MethodCodes) == 0 {
+		return nil, fmt.Errorf("%w: no valid method codes provided", ErrInvalidMethodSelection)
+	}
+
+	resp, err := s.recommender.ScoreTickets(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to score tickets: %w", err)
+	}
+	return resp, nil
+}
+
+// ReplayTickets 역대 전 회차에 대해 티켓 당첨 여부와 수익률 리플레이
+func (s *Service) ReplayTickets(ctx context.Context, req ReplayRequest) (*ReplayResponse, error) {
+	if len(req.Tickets) == 0 {
+		return nil, fmt.Errorf("at least one ticket is required")
+	}
+	for i, ticket := range req.Tickets {
+		if err := ValidateTicket(ticket); err != nil {
+			return nil, fmt.Errorf("ticket %d: %w", i+1, err)
+		}
+	}
+
+	draws, err := s.repo.GetAllDraws(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load draws: %w", err)
+	}
+
+	return replayTickets(req.Tickets, compileReplayDraws(draws)), nil
+}
+
+// ========================================
+// 추천 전략 프리셋
+// ========================================
+
+// CreatePreset 프리셋 생성 (요청은 핸들러에서 검증됨)
+func (s *Service) CreatePreset(ctx context.Context, userID int64, req PresetRequest) (*RecommendPreset, error) {
+	count, err := s.repo.CountPresetsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count presets: %w", err)
+	}
+	if count >= MaxPresetsPerUser {
+		return nil, ErrPresetLimitExceeded
+	}
+
+	preset := &RecommendPreset{
+		UserID:       userID,
+		Name:         req.Name,
+		Request:      req.Request,
+		AutoGenerate: req.AutoGenerate,
+	}
+	if err := s.repo.CreatePreset(ctx, preset); err != nil {
+		return nil, fmt.Errorf("failed to create preset: %w", err)
+	}
+	return preset, nil
+}
+
+// UpdatePreset 프리셋 수정
+func (s *Service) UpdatePreset(ctx context.Context, userID, id int64, req PresetRequest) (*RecommendPreset, error) {
+	preset, err := s.repo.GetPreset(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	preset.Name = req.Name
+	preset.Request = req.Request
+	preset.AutoGenerate = req.AutoGenerate
+	if err := s.repo.UpdatePreset(ctx, preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// DeletePreset 프리셋 삭제
+func (s *Service) DeletePreset(ctx context.Context, userID, id int64) error {
+	return s.repo.DeletePreset(ctx, userID, id)
+}
+
+// GetPreset 프리셋 조회
+func (s *Service) GetPreset(ctx context.Context, userID, id int64) (*RecommendPreset, error) {
+	return s.repo.GetPreset(ctx, userID, id)
+}
+
+// GetPresets 사용자 프리셋 목록 조회
+func (s *Service) GetPresets(ctx context.Context, userID int64) ([]RecommendPreset, error) {
+	presets, err := s.repo.GetPresetsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if presets == nil {
+		presets = []RecommendPreset{}
+	}
+	return presets, nil
+}
+
+// RunPreset 저장된 프리셋으로 추천 실행 (추천 기록은 사용자 기록으로 저장)
+func (s *Service) RunPreset(ctx context.Context, userID, id int64) (*RecommendResponse, error) {
+	preset, err := s.repo.GetPreset(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.RecommendNumbers(ctx, preset.Request, &userID, s.AssignExperiment(ctx, &userID))
+}
+
+// RunAutoPresets 자동 생성 프리셋을 대상 회차로 실행
+// 프리셋마다 행을 잠그고 생성하므로 여러 번/여러 인스턴스에서 호출해도 회차당 한 번만 생성되고,
+// 실패한 프리셋은 다음 호출에서 다시 시도된다.
+func (s *Service) RunAutoPresets(ctx context.Context, targetDrawNo int) (int, error) {
+	presets, err := s.repo.GetPendingAutoPresets(ctx, targetDrawNo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load auto presets: %w", err)
+	}
+	if len(presets) == 0 {
+		return 0, nil
+	}
+
+	generated := 0
+	for _, p := range presets {
+		userID := p.UserID
+		request := p.Request
+		ran, err := s.repo.GeneratePresetOnce(ctx, p.ID, targetDrawNo, func() error {
+			_, err := s.recommendForDraw(ctx, request, &userID, s.AssignExperiment(ctx, &userID), targetDrawNo)
+			return err
+		})
+		if err != nil {
+			s.log.Errorf("failed to run preset %d for user %d: %v", p.ID, p.UserID, err)
+			continue
+		}
+		if ran {
+			generated++
+		}
+	}
+
+	s.log.Infof("auto presets generated for draw %d: %d/%d", targetDrawNo, generated, len(presets))
+	return generated, nil
+}
+
+// ========================================
+// 구매 용지 지갑
+// ========================================
+
+// CreateTicket 구매 용지 수동 등록 (게임은 핸들러에서 검증됨)
+func (s *Service) CreateTicket(ctx context.Context, userID int64, req CreateTicketRequest) (*Ticket, error) {
+	if req.DrawNo == 0 {
+		req.DrawNo = NextDrawNo(time.Now())
+	}
+	return s.saveTicket(ctx, userID, req.DrawNo, TicketSourceManual, nil, req.Games)
+}
+
+// ImportTicketQR 용지 QR URL로 구매 용지 등록
+func (s *Service) ImportTicketQR(ctx context.Context, userID int64, rawURL string) (*Ticket, error) {
+	drawNo, games, err := ParseTicketQR(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if drawNo > NextDrawNo(time.Now()) {
+		return nil, fmt.Errorf("%w: draw %d is not on sale yet", ErrInvalidTicketQR, drawNo)
+	}
+	url := strings.TrimSpace(rawURL)
+	return s.saveTicket(ctx, userID, drawNo, TicketSourceQR, &url, games)
+}
+
+func (s *Service) saveTicket(ctx context.Context, userID int64, drawNo int, source string, qrURL *string, games []TicketGameInput) (*Ticket, error) {
+	ticket := &Ticket{
+		UserID: userID,
+		DrawNo: drawNo,
+		Source: source,
+		QRURL:  qrURL,
+		Games:  make([]TicketGame, 0, len(games)),
+		Status: RecommendationPending,
+	}
+	for i, g := range games {
+		nums := append([]int(nil), g.Numbers...)
+		sort.Ints(nums)
+		ticket.Games = append(ticket.Games, TicketGame{Slot: ticketSlot(i), Numbers: nums, Mode: g.Mode})
+	}
+
+	if err := s.repo.CreateTicket(ctx, ticket); err != nil {
+		return nil, err
+	}
+
+	// 이미 판매 마감된 회차면 이 용지만 바로 당첨 확인 (당첨번호가 아직 수집되지 않았으면 정기 작업에서 처리)
+	// 용지는 이미 저장되었으므로 클라이언트가 연결을 끊어도 확인과 당첨 알림은 끝까지 진행한다.
+	if s.checker != nil && drawNo < NextDrawNo(time.Now()) {
+		checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ticketCheckTimeout)
+		defer cancel()
+		if err := s.checker.ProcessTicket(checkCtx, ticket.ID, drawNo); err != nil && !errors.Is(err, ErrDrawNotFound) {
+			s.log.Errorf("failed to check ticket %d for draw %d: %v", ticket.ID, drawNo, err)
+		}
+	}
+
+	return ticket, nil
+}
+
+// GetTickets 사용자 구매 용지 목록 조회
+func (s *Service) GetTickets(ctx context.Context, userID int64, limit, offset int) (*TicketListResponse, error) {
+	tickets, totalCount, err := s.repo.GetTicketsByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickets: %w", err)
+	}
+	return &TicketListResponse{Tickets: tickets, TotalCount: totalCount}, nil
+}
+
+// DeleteTicket 구매 용지 삭제
+func (s *Service) DeleteTicket(ctx context.Context, userID, id int64) error {
+	return s.repo.DeleteTicket(ctx, userID, id)
+}
+
+// ========================================
+// 대량 추천 작업
+// ========================================
+
+// SubmitBulkJob 대량 작업 등록 후 워커 풀에 제출
+// 작업은 요청과 무관한 컨텍스트로 실행되며 CancelBulkJob으로만 중단된다.
+func (s *Service) SubmitBulkJob(ctx context.Context, userID int64, req BulkJobRequest) (*BulkJob, error) {
+	if s.jobPool == nil {
+		return nil, ErrBulkJobsUnavailable
+	}
+	if req.Type != BulkJobTypeWheel {
+		if err := s.resolveRecommendMethods(ctx, &req.Recommend); err != nil {
+			return nil, err
+		}
+	}
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	job, err := s.bulkJobs.add(BulkJob{
+		ID:     txid.NewID(),
+		UserID: userID,
+		Type:   req.Type,
+		Total:  bulkJobTotal(req),
+	}, cancel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	select {
+	case s.jobPool <- worker.Job{
+		Type: worker.JobTypeTask,
+		TxID: job.ID,
+		Ctx:  jobCtx,
+		Task: func(ctx context.Context) (interface{}, error) {
+			s.runBulkJob(ctx, job.ID, userID, req)
+			return nil, nil
+		},
+	}:
+	default:
+		s.bulkJobs.remove(job.ID)
+		cancel()
+		return nil, ErrBulkJobQueueFull
+	}
+
+	s.log.Infof("bulk job %s submitted (user=%d, type=%s, total=%d)", job.ID, userID, job.Type, job.Total)
+	return &job, nil
+}
+
+// runBulkJob 워커에서 작업 실행 후 결과 기록
+func (s *Service) runBulkJob(ctx context.Context, id string, userID int64, req BulkJobRequest) {
+	s.executeBulkJob(ctx, id, userID, func(ctx context.Context) (*BulkJobResult, error) {
+		return s.generateBulk(ctx, id, userID, req)
+	})
+}
+
+// executeBulkJob 생성 함수 실행 후 상태 기록
+// 생성 중 panic이 나도 작업을 FAILED로 종료해 사용자 작업 슬롯을 반환한다.
+func (s *Service) executeBulkJob(ctx context.Context, id string, userID int64, generate func(context.Context) (*BulkJobResult, error)) {
+	if !s.bulkJobs.start(id) {
+		return // 대기 중 취소됨
+	}
+
+	start := time.Now()
+	result, err := recoverBulkJob(ctx, generate)
+	s.bulkJobs.finish(id, result, err)
+	if s.jobNotifier != nil {
+		if job, snapErr := s.bulkJobs.snapshot(userID, id); snapErr == nil {
+			s.jobNotifier.NotifyJobFinished(userID, job)
+		}
+	}
+
+	if err != nil {
+		s.log.Warnf("bulk job %s stopped after %s: %v", id, time.Since(start), err)
+		return
+	}
+	s.log.Infof("bulk job %s completed in %s (%d sets)", id, time.Since(start), len(result.Recommendations))
+}
+
+// recoverBulkJob 생성 함수의 panic을 오류로 변환
+func recoverBulkJob(ctx context.Context, generate func(context.Context) (*BulkJobResult, error)) (result *BulkJobResult, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			result, err = nil, fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return generate(ctx)
+}
+
+// generateBulk 작업 종류별 세트 생성
+func (s *Service) generateBulk(ctx context.Context, id string, userID int64, req BulkJobRequest) (*BulkJobResult, error) {
+	targetDrawNo := NextDrawNo(time.Now())
+	progress := func(done int) { s.bulkJobs.progress(id, done) }
+
+	var recs []Recommendation
+	switch req.Type {
+	case BulkJobTypeWheel:
+		collisions, err := s.recommender.collisionIndexForDraw(ctx, RecommendRequest{AllowPastWinners: true}, &userID, targetDrawNo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load shared combinations: %w", err)
+		}
+		combos := FullWheel(req.WheelNumbers)
+		recs = make([]Recommendation, 0, len(combos))
+		for i, nums := range combos {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			rec := Recommendation{Numbers: nums, CombineMethod: BulkJobTypeWheel}
+			collisions.annotate(&rec)
+			recs = append(recs, rec)
+			progress(i + 1)
+		}
+	default:
+		resp, err := s.recommender.RecommendBulk(ctx, req.Recommend, &userID, targetDrawNo, progress)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to generate recommendations: %w", err)
+		}
+		recs = resp.Recommendations
+	}
+
+	return &BulkJobResult{
+		JobID:           id,
+		Type:            req.Type,
+		TargetDrawNo:    targetDrawNo,
+		Recommendations: recs,
+		GeneratedAt:     time.Now(),
+	}, nil
+}
+
+// GetBulkJob 대량 작업 상태 조회
+func (s *Service) GetBulkJob(userID int64, id string) (*BulkJob, error) {
+	job, err := s.bulkJobs.snapshot(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetBulkJobs 사용자 대량 작업 목록 조회
+func (s *Service) GetBulkJobs(userID int64) (*BulkJobListResponse, error) {
+	jobs, err := s.bulkJobs.list(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &BulkJobListResponse{
+		Jobs:       jobs,
+		TotalCount: len(jobs),
+	}, nil
+}
+
+// GetBulkJobResult 완료된 대량 작업 결과 조회
+func (s *Service) GetBulkJobResult(userID int64, id string) (*BulkJobResult, error) {
+	return s.bulkJobs.result(userID, id)
+}
+
+// CancelBulkJob 대기/실행 중인 대량 작업 취소
+func (s *Service) CancelBulkJob(userID int64, id string) (*BulkJob, error) {
+	job, err := s.bulkJobs.cancelJob(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ========================================
+// ML 모델
+// ========================================
+
+// TrainMLModel 전체 통합 분석 통계로 ML 모델 학습 후 새 버전으로 저장
+// activate=true면 저장과 동시에 활성화하여 추천 엔진에 반영한다.
+func (s *Service) TrainMLModel(ctx context.Context, cfg MLTrainConfig, activate bool) (*MLModel, error) {
+	stats, err := s.repo.GetAllAnalysisStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load analysis stats: %w", err)
+	}
+
+	s.log.Infof("training ml model with %d analysis stat rows", len(stats))
+	start := time.Now()
+
+	model, err := TrainLogisticModel(stats, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to train ml model: %w", err)
+	}
+
+	if err := s.repo.SaveMLModel(ctx, model, activate); err != nil {
+		return nil, fmt.Errorf("failed to save ml model: %w", err)
+	}
+
+	s.log.Infof("ml model v%d trained in %s (samples=%d, log_loss=%.5f, baseline=%.5f, active=%v)",
+		model.Version, time.Since(start), model.Metrics.SampleCount,
+		model.Metrics.LogLoss, model.Metrics.BaselineLogLoss, activate)
+
+	if activate {
+		s.recommender.SetMLModel(model)
+		s.refreshRecommendSnapshot(ctx)
+	}
+	return model, nil
+}
+
+// GetMLModels ML 모델 버전 목록 조회
+func (s *Service) GetMLModels(ctx context.Context, limit int) (*MLModelListResponse, error) {
+	models, totalCount, err := s.repo.GetMLModels(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &MLModelListResponse{
+		Models:     models,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// ActivateMLModel 특정 버전 ML 모델 활성화
+func (s *Service) ActivateMLModel(ctx context.Context, version int) error {
+	if err := s.repo.ActivateMLModel(ctx, version); err != nil {
+		return err
+	}
+	// 새 버전을 로드하도록 캐시 비운 뒤 스냅샷 재생성
+	s.recommender.SetMLModel(nil)
+	s.refreshRecommendSnapshot(ctx)
+	s.log.Infof("ml model v%d activated", version)
+	return nil
+}
+
+// ========================================
+// 추천 A/B 실험
+// ========================================
+
+// runningExperiment 진행 중 실험 (캐시, 없으면 nil)
+func (s *Service) runningExperiment(ctx context.Context) (*Experiment, error) {
+	if c := s.experiment.Load(); c != nil && time.Since(c.loadedAt) < ExperimentCacheTTL {
+		return c.experiment, nil
+	}
+
+	exp, err := s.repo.GetRunningExperiment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.experiment.Store(&experimentCache{experiment: exp, loadedAt: time.Now()})
+	return exp, nil
+}
+
+// AssignExperiment 로그인 사용자의 진행 중 실험 변형 배정 (비로그인이거나 실험이 없으면 nil)
+// 조회 실패 시에도 추천은 계속되도록 nil을 반환한다.
+func (s *Service) AssignExperiment(ctx context.Context, userID *int64) *ExperimentAssignment {
+	if userID == nil {
+		return nil
+	}
+	exp, err := s.runningExperiment(ctx)
+	if err != nil {
+		s.log.Errorf("failed to load running experiment: %v", err)
+		return nil
+	}
+	if exp == nil {
+		return nil
+	}
+	return exp.assign(*userID)
+}
+
+// validateVariantMethods 변형 기본 기법/조합 방법을 레지스트리 기준으로 검사
+func (s *Service) validateVariantMethods(ctx context.Context, variants []ExperimentVariant) error {
+	reg, err := s.methodRegistry(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate method codes: %w", err)
+	}
+	for _, v := range variants {
+		if err := reg.validateVariant(v.Config); err != nil {
+			return fmt.Errorf("variant %s: %w", v.Code, err)
+		}
+	}
+	return nil
+}
+
+// GetExperiments 실험 목록 조회
+func (s *Service) GetExperiments(ctx context.Context) ([]Experiment, error) {
+	experiments, err := s.repo.GetExperiments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experiments: %w", err)
+	}
+	if experiments == nil {
+		experiments = []Experiment{}
+	}
+	return experiments, nil
+}
+
+// GetExperiment 실험 단건 조회
+func (s *Service) GetExperiment(ctx context.Context, id int64) (*Experiment, error) {
+	return s.repo.GetExperiment(ctx, id)
+}
+
+// CreateExperiment 실험 등록 (DRAFT)
+func (s *Service) CreateExperiment(ctx context.Context, req ExperimentRequest) (*Experiment, error) {
+	if err := s.validateVariantMethods(ctx, req.Variants); err != nil {
+		return nil, err
+	}
+
+	exp := &Experiment{
+		Key:         req.Key,
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      ExperimentDraft,
+		Variants:    req.Variants,
+	}
+	if err := s.repo.CreateExperiment(ctx, exp); err != nil {
+		return nil, fmt.Errorf("failed to create experiment: %w", err)
+	}
+	return exp, nil
+}
+
+// UpdateExperiment 실험 수정 (DRAFT 상태만, 키는 변경 불가)
+func (s *Service) UpdateExperiment(ctx context.Context, id int64, req ExperimentRequest) (*Experiment, error) {
+	if _, err := s.repo.GetExperiment(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.validateVariantMethods(ctx, req.Variants); err != nil {
+		return nil, err
+	}
+
+	exp := &Experiment{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Variants:    req.Variants,
+	}
+	if err := s.repo.UpdateExperiment(ctx, exp); err != nil {
+		return nil, fmt.Errorf("failed to update experiment: %w", err)
+	}
+	return s.repo.GetExperiment(ctx, id)
+}
+
+// StartExperiment 실험 시작 (다른 실험이 진행 중이면 ErrExperimentConflict)
+func (s *Service) StartExperiment(ctx context.Context, id int64) (*Experiment, error) {
+	exp, err := s.repo.GetExperiment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// 등록 이후 기법이 비활성화되었을 수 있으므로 시작 시 다시 확인
+	if err := s.validateVariantMethods(ctx, exp.Variants); err != nil {
+		return nil, err
+	}
+	if err := s.repo.StartExperiment(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to start experiment: %w", err)
+	}
+	s.experiment.Store(nil)
+	return s.repo.GetExperiment(ctx, id)
+}
+
+// StopExperiment 실험 종료 (이후 추천은 배정하지 않음)
+func (s *Service) StopExperiment(ctx context.Context, id int64) (*Experiment, error) {
+	if _, err := s.repo.GetExperiment(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.StopExperiment(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to stop experiment: %w", err)
+	}
+	s.experiment.Store(nil)
+	return s.repo.GetExperiment(ctx, id)
+}
+
+// GetExperimentReport 변형별 성과와 대조군 대비 유의성 검정 리포트
+func (s *Service) GetExperimentReport(ctx context.Context, id int64) (*ExperimentReport, error) {
+	exp, err := s.repo.GetExperiment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if exp.StartedAt == nil {
+		return nil, fmt.Errorf("%w: experiment has not started", ErrExperimentConflict)
+	}
+
+	now := time.Now()
+	to := now
+	if exp.StoppedAt != nil {
+		to = *exp.StoppedAt
+	}
+	metrics, err := s.repo.GetExperimentMetrics(ctx, id, *exp.StartedAt, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate experiment metrics: %w", err)
+	}
+	return buildExperimentReport(*exp, metrics, now), nil
+}
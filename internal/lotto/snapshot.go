@@ -0,0 +1,215 @@
+package lotto
+
+import (
+	"context"
+	"time"
+)
+
+// snapshotMethodCodes 통합 분석 통계만으로 계산되어 스냅샷에 미리 담는 기법
+var snapshotMethodCodes = []string{
+	"NUMBER_FREQUENCY", "REAPPEAR_PROB", "FIRST_POSITION", "LAST_POSITION",
+	"PAIR_FREQUENCY", "CONSECUTIVE", "ODD_EVEN_RATIO", "HIGH_LOW_RATIO",
+	"BAYESIAN", "HOT_COLD",
+}
+
+// probVector 번호별(1~45) 확률 벡터 (인덱스 0 미사용)
+// set은 값이 있는 번호의 비트마스크로, 통계가 없는 번호는 맵으로 되돌릴 때 제외한다.
+type probVector struct {
+	p   [TotalNumbers + 1]float64
+	set uint64
+}
+
+// newProbVector 확률 맵을 벡터로 변환
+func newProbVector(probMap map[int]float64) probVector {
+	var v probVector
+	for num, p := range probMap {
+		if num >= 1 && num <= TotalNumbers {
+			v.p[num] = p
+			v.set |= 1 << uint(num)
+		}
+	}
+	return v
+}
+
+// probMap 벡터를 새 확률 맵으로 변환 (호출자가 자유롭게 수정 가능)
+func (v *probVector) probMap() map[int]float64 {
+	m := make(map[int]float64, TotalNumbers)
+	for num := 1; num <= TotalNumbers; num++ {
+		if v.set&(1<<uint(num)) != 0 {
+			m[num] = v.p[num]
+		}
+	}
+	return m
+}
+
+// methodCandidates 순위 기반(하위 호환) 방식의 기법별 후보 번호
+type methodCandidates struct {
+	numbers []int
+	details map[string]interface{}
+}
+
+// probabilitySnapshot 회차별로 미리 계산한 기법별 확률 벡터 (생성 후 읽기 전용)
+// 새 당첨 회차가 들어오거나 분석/ML 모델이 갱신되면 새 스냅샷으로 통째로 교체한다.
+type probabilitySnapshot struct {
+	drawNo     int                         // 생성 시점 최신 당첨 회차
+	stats      []AnalysisStat              // 최신 통합 분석 통계
+	vectors    map[string]probVector       // 통합 분석 통계 기반 기법
+	decay      map[int]probVector          // 반감기별 시간 감쇠 빈도
+	ml         probVector                  // ML_MODEL (활성 모델이 없으면 베이지안 폴백)
+	mlVersion  int                         // ml 계산에 사용한 모델 버전 (0 = 폴백)
+	candidates map[string]methodCandidates // 순위 기반 방식 후보 번호
+	winners    map[uint64]int              // 역대 1등 조합 비트마스크 -> 회차
+	builtAt    time.Time
+}
+
+// fresh 최신 회차와 활성 ML 모델 버전 기준으로 유효한 스냅샷인지 확인 (mlVersion < 0이면 모델 버전은 보지 않음)
+func (s *probabilitySnapshot) fresh(latestDrawNo, mlVersion int) bool {
+	if s == nil || s.drawNo != latestDrawNo {
+		return false
+	}
+	return mlVersion < 0 || s.mlVersion == mlVersion
+}
+
+// methodProbabilities 기법별 확률 맵
+// 스냅샷에 없는 기법은 통합 분석 통계로 계산하며, 미리 계산하지 않은 반감기만 DB를 조회한다.
+func (r *Recommender) methodProbabilities(ctx context.Context, snap *probabilitySnapshot, code string, halfLife int) (map[int]float64, error) {
+	switch code {
+	case MethodMLModel:
+		return snap.ml.probMap(), nil
+	case MethodDecayFrequency:
+		if v, ok := snap.decay[decayHalfLifeOrDefault(halfLife)]; ok {
+			return v.probMap(), nil
+		}
+		return r.getDecayProbabilities(ctx, halfLife)
+	}
+	if v, ok := snap.vectors[code]; ok {
+		return v.probMap(), nil
+	}
+	return r.getMethodProbabilities(code, snap.stats), nil
+}
+
+// currentSnapshot 유효한 스냅샷 반환 (최신 회차나 ML 모델 버전이 바뀌었으면 다시 생성)
+// checkML이면 활성 ML 모델 버전도 확인해 CLI로 학습한 모델도 재시작 없이 반영한다.
+func (r *Recommender) currentSnapshot(ctx context.Context, latestDrawNo int, checkML bool) (*probabilitySnapshot, error) {
+	mlVersion := -1
+	if checkML {
+		v, err := r.repo.GetActiveMLModelVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		mlVersion = v
+	}
+
+	if snap := r.snapshot.Load(); snap.fresh(latestDrawNo, mlVersion) {
+		return snap, nil
+	}
+
+	// 동시에 만료를 감지한 요청은 한 번만 생성
+	r.snapshotMu.Lock()
+	defer r.snapshotMu.Unlock()
+	if snap := r.snapshot.Load(); snap.fresh(latestDrawNo, mlVersion) {
+		return snap, nil
+	}
+	return r.rebuildSnapshotLocked(ctx, latestDrawNo)
+}
+
+// RefreshSnapshot 확률 스냅샷 강제 재생성 (분석 완료, ML 모델 교체 시 호출)
+func (r *Recommender) RefreshSnapshot(ctx context.Context) error {
+	latestDrawNo, err := r.repo.GetLatestDrawNo(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.snapshotMu.Lock()
+	defer r.snapshotMu.Unlock()
+	_, err = r.rebuildSnapshotLocked(ctx, latestDrawNo)
+	return err
+}
+
+// rebuildSnapshotLocked 스냅샷 생성 후 교체 (snapshotMu 보유 상태에서 호출)
+func (r *Recommender) rebuildSnapshotLocked(ctx context.Context, latestDrawNo int) (*probabilitySnapshot, error) {
+	start := time.Now()
+	snap, err := r.buildSnapshot(ctx, latestDrawNo)
+	if err != nil {
+		return nil, err
+	}
+	r.snapshot.Store(snap)
+	r.log.Infof("probability snapshot rebuilt for draw %d in %s (ml v%d)", latestDrawNo, time.Since(start), snap.mlVersion)
+	return snap, nil
+}
+
+// buildSnapshot 기법별 확률 벡터 계산
+func (r *Recommender) buildSnapshot(ctx context.Context, latestDrawNo int) (*probabilitySnapshot, error) {
+	stats, err := r.repo.GetLatestAnalysisStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &probabilitySnapshot{
+		drawNo:     latestDrawNo,
+		stats:      stats,
+		vectors:    make(map[string]probVector, len(snapshotMethodCodes)),
+		decay:      make(map[int]probVector),
+		candidates: make(map[string]methodCandidates, len(snapshotMethodCodes)),
+		builtAt:    time.Now(),
+	}
+
+	for _, code := range snapshotMethodCodes {
+		snap.vectors[code] = newProbVector(r.getMethodProbabilities(code, stats))
+
+		// 실패한 기법은 캐시하지 않고 요청 시 직접 계산 (일시적 DB 오류가 다음 회차까지 남지 않도록)
+		numbers, details, err := r.getMethodCandidates(ctx, code, stats, RecommendRequest{})
+		if err != nil {
+			r.log.Warnf("snapshot: failed to precompute %s candidates for draw %d: %v", code, latestDrawNo, err)
+			continue
+		}
+		snap.candidates[code] = methodCandidates{numbers: numbers, details: details}
+	}
+
+	winners, err := r.repo.GetWinningCombinations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.winners = winnerMasks(winners)
+
+	halfLives := r.analyzer.decayHalfLives()
+	if !r.analyzer.isPrecomputedHalfLife(DefaultDecayHalfLife) {
+		halfLives = append([]int{DefaultDecayHalfLife}, halfLives...)
+	}
+	for _, halfLife := range halfLives {
+		probMap, err := r.getDecayProbabilities(ctx, halfLife)
+		if err != nil {
+			return nil, err
+		}
+		snap.decay[halfLife] = newProbVector(probMap)
+	}
+
+	mlProbs, err := r.getMLProbabilities(ctx, stats)
+	if err != nil {
+		return nil, err
+	}
+	snap.ml = newProbVector(mlProbs)
+	if model := r.cachedMLModel(); model != nil {
+		snap.mlVersion = model.Version
+	}
+
+	return snap, nil
+}
+
+// containsCode 기법 코드 포함 여부
+func containsCode(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotCandidates 순위 기반 방식 후보 번호 (스냅샷에 없는 기법은 직접 계산)
+func (r *Recommender) snapshotCandidates(ctx context.Context, snap *probabilitySnapshot, code string, req RecommendRequest) ([]int, map[string]interface{}, error) {
+	if c, ok := snap.candidates[code]; ok {
+		return c.numbers, c.details, nil
+	}
+	return r.getMethodCandidates(ctx, code, snap.stats, req)
+}
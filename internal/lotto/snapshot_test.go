@@ -0,0 +1,128 @@
+package lotto
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProbVectorRoundTrip(t *testing.T) {
+	in := map[int]float64{1: 0.1, 23: 0.5, 45: 0.4, 46: 1}
+	v := newProbVector(in)
+	out := v.probMap()
+
+	if len(out) != 3 {
+		t.Fatalf("len = %d, want 3 (out-of-range number dropped)", len(out))
+	}
+	for _, n := range []int{1, 23, 45} {
+		if out[n] != in[n] {
+			t.Errorf("prob[%d] = %v, want %v", n, out[n], in[n])
+		}
+	}
+
+	// 반환 맵을 수정해도 벡터는 그대로
+	out[1] = 9
+	if v.probMap()[1] != 0.1 {
+		t.Error("probMap should return an independent copy")
+	}
+}
+
+func TestProbabilitySnapshotFresh(t *testing.T) {
+	var nilSnap *probabilitySnapshot
+	if nilSnap.fresh(100, -1) {
+		t.Error("nil snapshot should not be fresh")
+	}
+
+	snap := &probabilitySnapshot{drawNo: 100, mlVersion: 3}
+	tests := []struct {
+		drawNo, mlVersion int
+		want              bool
+	}{
+		{100, -1, true},
+		{100, 3, true},
+		{100, 4, false},
+		{100, 0, false},
+		{101, -1, false},
+	}
+	for _, tt := range tests {
+		if got := snap.fresh(tt.drawNo, tt.mlVersion); got != tt.want {
+			t.Errorf("fresh(%d, %d) = %v, want %v", tt.drawNo, tt.mlVersion, got, tt.want)
+		}
+	}
+}
+
+func TestMethodProbabilitiesFromSnapshot(t *testing.T) {
+	stats := make([]AnalysisStat, 0, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		stats = append(stats, AnalysisStat{Number: n, TotalProb: float64(n), BayesianPost: float64(100 - n)})
+	}
+
+	r := &Recommender{}
+	snap := &probabilitySnapshot{
+		stats:   stats,
+		vectors: map[string]probVector{"BAYESIAN": newProbVector(r.getMethodProbabilities("BAYESIAN", stats))},
+		decay:   map[int]probVector{DefaultDecayHalfLife: newProbVector(map[int]float64{7: 1})},
+		ml:      newProbVector(map[int]float64{3: 0.5}),
+	}
+
+	ctx := context.Background()
+	got, err := r.methodProbabilities(ctx, snap, "BAYESIAN", 0)
+	if err != nil || got[1] != 99 {
+		t.Errorf("BAYESIAN prob[1] = %v, %v", got[1], err)
+	}
+
+	// 스냅샷에 없는 기법은 통계로 계산 (기본: 출현 확률)
+	got, err = r.methodProbabilities(ctx, snap, "NEW_METHOD", 0)
+	if err != nil || got[45] != 45 {
+		t.Errorf("fallback prob[45] = %v, %v", got[45], err)
+	}
+
+	got, _ = r.methodProbabilities(ctx, snap, MethodDecayFrequency, 0)
+	if got[7] != 1 || len(got) != 1 {
+		t.Errorf("decay probs = %v", got)
+	}
+
+	got, _ = r.methodProbabilities(ctx, snap, MethodMLModel, 0)
+	if got[3] != 0.5 {
+		t.Errorf("ml probs = %v", got)
+	}
+}
+
+func TestCurrentSnapshotReusesFresh(t *testing.T) {
+	r := &Recommender{}
+	snap := &probabilitySnapshot{drawNo: 1200}
+	r.snapshot.Store(snap)
+
+	// 유효한 스냅샷이면 DB 조회 없이 그대로 반환 (repo가 nil이어도 동작)
+	got, err := r.currentSnapshot(context.Background(), 1200, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != snap {
+		t.Error("fresh snapshot should be reused")
+	}
+}
+
+func TestSnapshotCandidatesFallsBackWhenNotCached(t *testing.T) {
+	stats := make([]AnalysisStat, 0, TotalNumbers)
+	for n := 1; n <= TotalNumbers; n++ {
+		stats = append(stats, AnalysisStat{Number: n, BayesianPost: float64(n)})
+	}
+
+	r := &Recommender{}
+	cached := []int{1, 2, 3, 4, 5, 6}
+	snap := &probabilitySnapshot{
+		stats:      stats,
+		candidates: map[string]methodCandidates{"NUMBER_FREQUENCY": {numbers: cached}},
+	}
+
+	got, _, err := r.snapshotCandidates(context.Background(), snap, "NUMBER_FREQUENCY", RecommendRequest{})
+	if err != nil || comboKey(got) != comboKey(cached) {
+		t.Errorf("cached candidates = %v, %v", got, err)
+	}
+
+	// 스냅샷 생성 때 실패해 캐시되지 않은 기법은 요청 시 직접 계산
+	got, _, err = r.snapshotCandidates(context.Background(), snap, "BAYESIAN", RecommendRequest{})
+	if err != nil || len(got) == 0 || got[0] != 45 {
+		t.Errorf("live candidates = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,182 @@
+package lotto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxGamesPerTicket 용지 1장당 최대 게임 수 (A~E)
+const MaxGamesPerTicket = 5
+
+// ticketCheckTimeout 지난 회차 용지 등록 시 당첨 확인 제한 시간
+const ticketCheckTimeout = 10 * time.Second
+
+// 용지 등록 경로
+const (
+	TicketSourceManual = "MANUAL"
+	TicketSourceQR     = "QR"
+)
+
+// 게임 선택 방식
+const (
+	TicketModeManual   = "MANUAL"    // 수동
+	TicketModeAuto     = "AUTO"      // 자동
+	TicketModeSemiAuto = "SEMI_AUTO" // 반자동
+)
+
+// ErrInvalidTicketQR 용지 QR URL 형식 오류
+var ErrInvalidTicketQR = errors.New("invalid ticket qr url")
+
+// WinningChecker 구매 용지 당첨 확인 (notification.Service가 구현)
+type WinningChecker interface {
+	ProcessTicket(ctx context.Context, ticketID int64, drawNo int) error
+}
+
+// Ticket 구매 용지
+type Ticket struct {
+	ID        int64        `json:"id"`
+	UserID    int64        `json:"user_id"`
+	DrawNo    int          `json:"draw_no"`
+	Source    string       `json:"source"` // MANUAL / QR
+	QRURL     *string      `json:"qr_url,omitempty"`
+	Games     []TicketGame `json:"games"`
+	Status    string       `json:"status"` // PENDING / SETTLED
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// TicketGame 용지 내 게임
+type TicketGame struct {
+	ID      int64                  `json:"id"`
+	Slot    string                 `json:"slot"` // A~E
+	Numbers []int                  `json:"numbers"`
+	Mode    string                 `json:"mode"`              // MANUAL / AUTO / SEMI_AUTO
+	Outcome *RecommendationOutcome `json:"outcome,omitempty"` // 당첨 확인 후에만 포함
+}
+
+// TicketGameInput 게임 입력
+type TicketGameInput struct {
+	Numbers []int  `json:"numbers"`
+	Mode    string `json:"mode,omitempty"` // 기본값: MANUAL
+}
+
+// CreateTicketRequest 용지 수동 등록 요청
+type CreateTicketRequest struct {
+	DrawNo int               `json:"draw_no,omitempty"` // 비어있으면 현재 판매 중인 회차
+	Games  []TicketGameInput `json:"games"`
+}
+
+// ImportTicketQRRequest QR 가져오기 요청
+type ImportTicketQRRequest struct {
+	URL string `json:"url"`
+}
+
+// TicketListResponse 용지 목록 응답
+type TicketListResponse struct {
+	Tickets    []Ticket `json:"tickets"`
+	TotalCount int      `json:"total_count"`
+}
+
+// validateTicketGames 게임 수와 번호, 선택 방식 검사 (방식 기본값 설정)
+func validateTicketGames(games []TicketGameInput) error {
+	if len(games) == 0 {
+		return errors.New("at least one game is required")
+	}
+	if len(games) > MaxGamesPerTicket {
+		return fmt.Errorf("maximum %d games per ticket", MaxGamesPerTicket)
+	}
+	for i := range games {
+		if err := ValidateTicket(games[i].Numbers); err != nil {
+			return fmt.Errorf("game %s: %w", ticketSlot(i), err)
+		}
+		switch games[i].Mode {
+		case "":
+			games[i].Mode = TicketModeManual
+		case TicketModeManual, TicketModeAuto, TicketModeSemiAuto:
+		default:
+			return fmt.Errorf("game %s: mode must be MANUAL, AUTO or SEMI_AUTO", ticketSlot(i))
+		}
+	}
+	return nil
+}
+
+// ticketStatus 모든 게임이 당첨 확인되면 SETTLED
+func ticketStatus(games []TicketGame) string {
+	if len(games) == 0 {
+		return RecommendationPending
+	}
+	for _, g := range games {
+		if g.Outcome == nil {
+			return RecommendationPending
+		}
+	}
+	return RecommendationSettled
+}
+
+// ticketSlot 게임 순서를 용지 슬롯 이름으로 변환 (0 -> A)
+func ticketSlot(i int) string {
+	return string(rune('A' + i))
+}
+
+// qrGamePattern QR 게임 인코딩: 방식 문자(m/q/s) + 2자리 번호 6개
+var qrGamePattern = regexp.MustCompile(`([mqs])(\d{12})`)
+
+// ParseTicketQR 동행복권 용지 QR URL 파싱
+// 형식: http://m.dhlottery.co.kr/?v=1150m010203040506q071421283542...
+// v 값은 4자리 회차 + 게임별 방식 문자(m=수동, q=자동, s=반자동)와 12자리 번호로 구성되며,
+// 마지막 게임 뒤에는 용지 일련번호가 이어진다.
+func ParseTicketQR(raw string) (int, []TicketGameInput, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, nil, ErrInvalidTicketQR
+	}
+	host := strings.ToLower(u.Hostname())
+	if host != "dhlottery.co.kr" && !strings.HasSuffix(host, ".dhlottery.co.kr") {
+		return 0, nil, ErrInvalidTicketQR
+	}
+
+	v := u.Query().Get("v")
+	if len(v) < 4 {
+		return 0, nil, ErrInvalidTicketQR
+	}
+	drawNo, err := strconv.Atoi(v[:4])
+	if err != nil || drawNo < 1 {
+		return 0, nil, ErrInvalidTicketQR
+	}
+
+	matches := qrGamePattern.FindAllStringSubmatch(v[4:], -1)
+	if len(matches) == 0 || len(matches) > MaxGamesPerTicket {
+		return 0, nil, ErrInvalidTicketQR
+	}
+
+	games := make([]TicketGameInput, 0, len(matches))
+	for _, m := range matches {
+		nums := make([]int, 0, NumbersPerDraw)
+		for i := 0; i < len(m[2]); i += 2 {
+			n, _ := strconv.Atoi(m[2][i : i+2])
+			nums = append(nums, n)
+		}
+		if err := ValidateTicket(nums); err != nil {
+			return 0, nil, fmt.Errorf("%w: %v", ErrInvalidTicketQR, err)
+		}
+		games = append(games, TicketGameInput{Numbers: nums, Mode: qrGameMode(m[1])})
+	}
+
+	return drawNo, games, nil
+}
+
+func qrGameMode(code string) string {
+	switch code {
+	case "q":
+		return TicketModeAuto
+	case "s":
+		return TicketModeSemiAuto
+	default:
+		return TicketModeManual
+	}
+}
@@ -0,0 +1,83 @@
+package lotto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTicketQR(t *testing.T) {
+	raw := "http://m.dhlottery.co.kr/?v=1150m010203040506q071421283542s0911213345441234567890"
+
+	drawNo, games, err := ParseTicketQR(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if drawNo != 1150 {
+		t.Errorf("draw_no = %d, want 1150", drawNo)
+	}
+
+	want := []TicketGameInput{
+		{Numbers: []int{1, 2, 3, 4, 5, 6}, Mode: TicketModeManual},
+		{Numbers: []int{7, 14, 21, 28, 35, 42}, Mode: TicketModeAuto},
+		{Numbers: []int{9, 11, 21, 33, 45, 44}, Mode: TicketModeSemiAuto},
+	}
+	if !reflect.DeepEqual(games, want) {
+		t.Errorf("games = %+v, want %+v", games, want)
+	}
+}
+
+func TestParseTicketQRInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"other host", "http://example.com/?v=1150m010203040506"},
+		{"lookalike host", "http://dhlottery.co.kr.evil.com/?v=1150m010203040506"},
+		{"missing v", "http://m.dhlottery.co.kr/?x=1"},
+		{"bad draw", "http://m.dhlottery.co.kr/?v=abcdm010203040506"},
+		{"no games", "http://m.dhlottery.co.kr/?v=11501234567890"},
+		{"out of range", "http://m.dhlottery.co.kr/?v=1150m010203040546"},
+		{"duplicate number", "http://m.dhlottery.co.kr/?v=1150q010203040505"},
+		{"too many games", "http://qr.dhlottery.co.kr/?v=1150" +
+			"m010203040506m010203040506m010203040506m010203040506m010203040506m010203040506"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseTicketQR(tt.raw); !errors.Is(err, ErrInvalidTicketQR) {
+				t.Errorf("err = %v, want ErrInvalidTicketQR", err)
+			}
+		})
+	}
+}
+
+func TestValidateTicketGames(t *testing.T) {
+	games := []TicketGameInput{{Numbers: []int{1, 2, 3, 4, 5, 6}}}
+	if err := validateTicketGames(games); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if games[0].Mode != TicketModeManual {
+		t.Errorf("mode = %q, want default %q", games[0].Mode, TicketModeManual)
+	}
+
+	tooMany := make([]TicketGameInput, MaxGamesPerTicket+1)
+	for i := range tooMany {
+		tooMany[i] = TicketGameInput{Numbers: []int{1, 2, 3, 4, 5, 6}}
+	}
+	bad := [][]TicketGameInput{
+		nil,
+		tooMany,
+		{{Numbers: []int{1, 2, 3}}},
+		{{Numbers: []int{1, 2, 3, 4, 5, 6}, Mode: "LUCKY"}},
+	}
+	for i, g := range bad {
+		if err := validateTicketGames(g); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+
+	if got := ticketSlot(4); got != "E" {
+		t.Errorf("slot(4) = %q, want E", got)
+	}
+}
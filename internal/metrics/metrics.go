@@ -13,6 +13,15 @@ var (
     // naive histogram: total duration and count
     totalDurationNs int64
     totalRequests   int64
+    // 자동 정리된 푸시 디바이스 토큰 수 (사유별)
+    prunedTokens = map[string]*int64{
+        "unregistered": new(int64),
+        "invalid":      new(int64),
+        "expired":      new(int64),
+        "replaced":     new(int64),
+        "reassigned":   new(int64),
+    }
+    prunedReasons = []string{"unregistered", "invalid", "expired", "replaced", "reassigned"}
 )
 
 func IncConcurrent() {
@@ -32,6 +41,13 @@ func ObserveRequest(d time.Duration) {
     atomic.AddInt64(&totalRequests, 1)
 }
 
+// AddPrunedTokens 사유별 정리된 디바이스 토큰 수 누적 (알 수 없는 사유는 무시)
+func AddPrunedTokens(reason string, n int64) {
+    if c, ok := prunedTokens[reason]; ok && n > 0 {
+        atomic.AddInt64(c, n)
+    }
+}
+
 func Handler() http.Handler {
     return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
         w.Header().Set("Content-Type", "text/plain; version=0.0.4")
@@ -59,5 +75,11 @@ func Handler() http.Handler {
         fmt.Fprintf(w, "# HELP lottosmash_total_requests Total HTTP requests observed\n")
         fmt.Fprintf(w, "# TYPE lottosmash_total_requests counter\n")
         fmt.Fprintf(w, "lottosmash_total_requests %d\n", cnt)
+
+        fmt.Fprintf(w, "# HELP lottosmash_push_tokens_pruned_total Device tokens deactivated automatically, by reason\n")
+        fmt.Fprintf(w, "# TYPE lottosmash_push_tokens_pruned_total counter\n")
+        for _, reason := range prunedReasons {
+            fmt.Fprintf(w, "lottosmash_push_tokens_pruned_total{reason=%q} %d\n", reason, atomic.LoadInt64(prunedTokens[reason]))
+        }
     })
 }
@@ -57,6 +57,11 @@ func (lrw *loggingResponseWriter) Write(b []byte) (int, error) {
     return n, err
 }
 
+// Unwrap http.ResponseController가 Flush/SetWriteDeadline을 원래 ResponseWriter로 전달하도록
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+    return lrw.ResponseWriter
+}
+
 func Logging(l *logger.Logger) Middleware {
     return func(next http.Handler) http.Handler {
         return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
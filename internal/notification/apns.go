@@ -0,0 +1,214 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// APNs HTTP/2 API 주소
+	APNsProductionURL = "https://api.push.apple.com"
+	APNsSandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// 인증 토큰은 1시간 이내에 갱신해야 하고, 20분보다 자주 갱신하면 거부된다.
+	apnsTokenRefresh = 50 * time.Minute
+)
+
+// APNsOptions APNs 발송기 설정
+type APNsOptions struct {
+	KeyID      string
+	TeamID     string
+	Topic      string // 앱 번들 ID
+	Production bool
+	Endpoint   string // 비우면 Production 값에 따라 production/sandbox 주소 사용
+}
+
+// APNsSender Apple Push Notification service 발송기
+// .p8 키로 서명한 ES256 JWT를 인증 토큰으로 재사용하며, HTTP/2로 발송한다.
+type APNsSender struct {
+	keyID    string
+	teamID   string
+	topic    string
+	key      *ecdsa.PrivateKey
+	endpoint string
+	client   *http.Client
+	now      func() time.Time
+
+	mu       sync.Mutex
+	jwtToken string
+	issuedAt time.Time
+}
+
+// NewAPNsSenderFromFile .p8 키 파일로 발송기 생성
+func NewAPNsSenderFromFile(keyFile string, opts APNsOptions) (*APNsSender, error) {
+	raw, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read apns key: %w", err)
+	}
+	return NewAPNsSender(raw, opts)
+}
+
+// NewAPNsSender .p8 키(PEM)로 발송기 생성
+func NewAPNsSender(keyPEM []byte, opts APNsOptions) (*APNsSender, error) {
+	if opts.KeyID == "" || opts.TeamID == "" || opts.Topic == "" {
+		return nil, errors.New("apns requires keyId, teamId and topic")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse apns key: %w", err)
+	}
+
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = APNsSandboxURL
+		if opts.Production {
+			endpoint = APNsProductionURL
+		}
+	}
+
+	return &APNsSender{
+		keyID:    opts.KeyID,
+		teamID:   opts.TeamID,
+		topic:    opts.Topic,
+		key:      key,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		// 기본 Transport는 TLS 연결에서 HTTP/2를 협상한다.
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+// Send APNs로 알림 발송
+func (a *APNsSender) Send(ctx context.Context, token DeviceToken, msg PushMessage) error {
+	authToken, err := a.token()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{"aps": apsPayload(msg)}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal apns payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint+"/3/device/"+token.Token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	if msg.CollapseID != "" {
+		req.Header.Set("apns-collapse-id", msg.CollapseID)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	pushErr := parseAPNsError(resp)
+	if pushErr.Code == "ExpiredProviderToken" || pushErr.Code == "InvalidProviderToken" {
+		// 다음 발송 시 인증 토큰을 새로 서명
+		a.mu.Lock()
+		a.jwtToken = ""
+		a.mu.Unlock()
+	}
+	return pushErr
+}
+
+// apsPayload APNs aps 딕셔너리 (FCM의 apns 페이로드에도 사용)
+func apsPayload(msg PushMessage) map[string]interface{} {
+	aps := map[string]interface{}{
+		"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+		"sound": "default",
+	}
+	if msg.ThreadID != "" {
+		aps["thread-id"] = msg.ThreadID
+	}
+	if msg.Badge != nil {
+		aps["badge"] = *msg.Badge
+	}
+	return aps
+}
+
+// parseAPNsError APNs 오류 응답({"reason": "..."})을 PushError로 변환
+func parseAPNsError(resp *http.Response) *PushError {
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	_ = json.Unmarshal(raw, &payload)
+
+	pushErr := &PushError{
+		Provider:   "apns",
+		StatusCode: resp.StatusCode,
+		Code:       payload.Reason,
+		Message:    payload.Reason,
+	}
+	if pushErr.Message == "" {
+		pushErr.Message = strings.TrimSpace(string(raw))
+	}
+
+	switch pushErr.Code {
+	case "Unregistered":
+		pushErr.Err = ErrTokenUnregistered
+	case "BadDeviceToken":
+		pushErr.Err = ErrInvalidToken
+	default:
+		// PayloadTooLarge, DeviceTokenNotForTopic(토픽 설정 오류) 등은 토큰 문제가 아님
+		if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusRequestEntityTooLarge {
+			pushErr.Err = ErrInvalidPayload
+		}
+	}
+	return pushErr
+}
+
+// token 캐시된 인증 토큰 반환 (발급 후 apnsTokenRefresh가 지나면 새로 서명)
+func (a *APNsSender) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if a.jwtToken != "" && now.Sub(a.issuedAt) < apnsTokenRefresh {
+		return a.jwtToken, nil
+	}
+
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.teamID,
+		"iat": now.Unix(),
+	})
+	t.Header["kid"] = a.keyID
+	signed, err := t.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("sign apns token: %w", err)
+	}
+
+	a.jwtToken = signed
+	a.issuedAt = now
+	return signed, nil
+}
@@ -0,0 +1,166 @@
+package notification
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// apnsRequest 스텁이 받은 요청 기록
+type apnsRequest struct {
+	proto   int
+	path    string
+	header  http.Header
+	payload map[string]interface{}
+}
+
+func newAPNsStub(t *testing.T, key *ecdsa.PrivateKey, reject map[string]int) (*httptest.Server, *[]apnsRequest) {
+	t.Helper()
+	var requests []apnsRequest
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("authorization"), "bearer ")
+		claims := jwt.MapClaims{}
+		parsed, err := jwt.ParseWithClaims(auth, claims, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || claims["iss"] != "TEAM123" || parsed.Header["kid"] != "KEY123" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"reason": "InvalidProviderToken"}`))
+			return
+		}
+
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		requests = append(requests, apnsRequest{proto: r.ProtoMajor, path: r.URL.Path, header: r.Header, payload: payload})
+
+		device := strings.TrimPrefix(r.URL.Path, "/3/device/")
+		if status, ok := reject[device]; ok {
+			w.WriteHeader(status)
+			reason := map[int]string{http.StatusGone: "Unregistered", http.StatusBadRequest: "BadDeviceToken", http.StatusRequestEntityTooLarge: "PayloadTooLarge"}[status]
+			json.NewEncoder(w).Encode(map[string]string{"reason": reason})
+			return
+		}
+		w.Header().Set("apns-id", "stub-id")
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func newTestAPNsSender(t *testing.T, srv *httptest.Server, key *ecdsa.PrivateKey) *APNsSender {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	sender, err := NewAPNsSender(keyPEM, APNsOptions{KeyID: "KEY123", TeamID: "TEAM123", Topic: "com.example.lottosmash", Endpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender.client = srv.Client()
+	return sender
+}
+
+func TestAPNsSenderSend(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	srv, requests := newAPNsStub(t, key, map[string]int{"gone": http.StatusGone, "bad": http.StatusBadRequest, "big": http.StatusRequestEntityTooLarge})
+	sender := newTestAPNsSender(t, srv, key)
+
+	badge := 3
+	msg := PushMessage{Title: "당첨!", Body: "3개 일치", Data: map[string]string{"draw_no": "1200"}, CollapseID: "winning-1200", ThreadID: "draw-1200", Badge: &badge}
+	for i := 0; i < 2; i++ {
+		if err := sender.Send(context.Background(), DeviceToken{Token: "abc", Platform: PlatformIOS}, msg); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("requests = %d", len(*requests))
+	}
+
+	req := (*requests)[0]
+	if req.proto != 2 {
+		t.Errorf("proto = HTTP/%d, want HTTP/2", req.proto)
+	}
+	if req.path != "/3/device/abc" || req.header.Get("apns-topic") != "com.example.lottosmash" || req.header.Get("apns-collapse-id") != "winning-1200" {
+		t.Errorf("unexpected request: %s %v", req.path, req.header)
+	}
+	aps, _ := req.payload["aps"].(map[string]interface{})
+	if aps["thread-id"] != "draw-1200" || aps["badge"] != float64(3) || req.payload["draw_no"] != "1200" {
+		t.Errorf("payload = %v", req.payload)
+	}
+	if (*requests)[1].header.Get("authorization") != req.header.Get("authorization") {
+		t.Error("provider token should be reused")
+	}
+
+	err := sender.Send(context.Background(), DeviceToken{Token: "gone", Platform: PlatformIOS}, msg)
+	if !errors.Is(err, ErrTokenUnregistered) {
+		t.Errorf("gone: err = %v, want ErrTokenUnregistered", err)
+	}
+	err = sender.Send(context.Background(), DeviceToken{Token: "bad", Platform: PlatformIOS}, msg)
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("bad: err = %v, want ErrInvalidToken", err)
+	}
+	err = sender.Send(context.Background(), DeviceToken{Token: "big", Platform: PlatformIOS}, msg)
+	if !errors.Is(err, ErrInvalidPayload) || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("big: err = %v, want ErrInvalidPayload", err)
+	}
+}
+
+func TestNewAPNsSenderEndpoint(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	opts := APNsOptions{KeyID: "K", TeamID: "T", Topic: "com.example"}
+
+	sandbox, _ := NewAPNsSender(keyPEM, opts)
+	opts.Production = true
+	production, _ := NewAPNsSender(keyPEM, opts)
+	if sandbox.endpoint != APNsSandboxURL || production.endpoint != APNsProductionURL {
+		t.Errorf("endpoints = %s, %s", sandbox.endpoint, production.endpoint)
+	}
+
+	if _, err := NewAPNsSender(keyPEM, APNsOptions{KeyID: "K"}); err == nil {
+		t.Error("missing team/topic should fail")
+	}
+}
+
+type recordingSender struct {
+	name  string
+	calls *[]string
+}
+
+func (s recordingSender) Send(ctx context.Context, token DeviceToken, msg PushMessage) error {
+	*s.calls = append(*s.calls, s.name+":"+token.Platform)
+	return nil
+}
+
+func TestPlatformRouter(t *testing.T) {
+	var calls []string
+	router := NewPlatformRouter(recordingSender{"fcm", &calls})
+	router.Route(PlatformIOS, recordingSender{"apns", &calls})
+
+	for _, platform := range []string{PlatformAndroid, PlatformIOS, PlatformWeb} {
+		router.Send(context.Background(), DeviceToken{Platform: platform}, PushMessage{})
+	}
+	want := "fcm:android,apns:ios,fcm:web"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("routed = %s, want %s", got, want)
+	}
+
+	if err := NewPlatformRouter(nil).Send(context.Background(), DeviceToken{Platform: PlatformWeb}, PushMessage{}); err == nil {
+		t.Error("missing sender should fail")
+	}
+}
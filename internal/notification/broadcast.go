@@ -0,0 +1,385 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/LottoSmash/internal/lotto"
+	"github.com/example/LottoSmash/internal/txid"
+	"github.com/example/LottoSmash/internal/worker"
+)
+
+const (
+	DefaultBroadcastBatch = 500
+
+	// broadcastMaxAge 판매 마감 후 이 시간이 지난 회차는 발표 알림을 보내지 않음 (서버 재시작 후 뒤늦은 수집 등)
+	broadcastMaxAge = 48 * time.Hour
+
+	// broadcastLease 진행 기록 없이 이 시간이 지난 미완료 발송은 다른 선점 시도가 이어받음 (서버 비정상 종료 등)
+	broadcastLease = 10 * time.Minute
+)
+
+// broadcast 회차별 전체 발송 한 건
+type broadcast struct {
+	notifType  string
+	drawNo     int
+	withPicks  bool // 배치마다 사용자의 해당 회차 추천번호를 함께 조회
+	expiresAt  time.Time
+	newMessage func(userID int64, picks [][]int) *PushNotification
+}
+
+// SetJobPool 전체 발송 배치를 실행할 워커 풀 입력 채널 설정
+func (s *Service) SetJobPool(pool chan<- worker.Job) {
+	s.jobPool = pool
+}
+
+// BroadcastDrawPublished 새 회차 당첨번호와 1등 당첨금을 수신 동의한 전체 사용자에게 발송
+// 회차당 한 번만 발송되며, 배치는 워커 풀에서 처리되므로 바로 반환된다.
+func (s *Service) BroadcastDrawPublished(ctx context.Context, drawNo int) error {
+	if time.Since(lotto.DrawSalesCutoff(drawNo)) > broadcastMaxAge {
+		s.log.Infof("draw %d is too old for a published broadcast, skipping", drawNo)
+		return nil
+	}
+	draw, err := s.lottoSvc.GetDrawByNo(ctx, drawNo)
+	if err != nil {
+		return fmt.Errorf("get draw %d: %w", drawNo, err)
+	}
+
+	title, body := drawPublishedMessage(draw)
+	data := broadcastData(TypeDrawPublished, drawNo, map[string]string{
+		"numbers": formatNumbers([]int{draw.Num1, draw.Num2, draw.Num3, draw.Num4, draw.Num5, draw.Num6}),
+		"bonus":   strconv.Itoa(draw.BonusNum),
+	})
+	return s.startBroadcast(ctx, &broadcast{
+		notifType: TypeDrawPublished,
+		drawNo:    drawNo,
+		newMessage: func(userID int64, _ [][]int) *PushNotification {
+			return &PushNotification{
+				UserID:     &userID,
+				Type:       TypeDrawPublished,
+				Title:      title,
+				Body:       body,
+				Data:       &data,
+				CollapseID: fmt.Sprintf("draw-published-%d", drawNo),
+				ThreadID:   fmt.Sprintf("draw-%d", drawNo),
+			}
+		},
+	})
+}
+
+// SendDrawReminders drawNo 회차 판매 마감 전 리마인더 발송 (사용자가 받은 해당 회차 추천번호 포함)
+// 방해 금지 시간 때문에 마감 이후로 미뤄질 사용자에게는 보내지 않는다.
+func (s *Service) SendDrawReminders(ctx context.Context, drawNo int) error {
+	cutoff := lotto.DrawSalesCutoff(drawNo)
+	if !time.Now().Before(cutoff) {
+		s.log.Infof("sales for draw %d already closed, skipping reminders", drawNo)
+		return nil
+	}
+
+	return s.startBroadcast(ctx, &broadcast{
+		notifType: TypeWeeklyReminder,
+		drawNo:    drawNo,
+		withPicks: true,
+		expiresAt: cutoff,
+		newMessage: func(userID int64, picks [][]int) *PushNotification {
+			title, body := reminderMessage(drawNo, cutoff, picks)
+			sets := make([]string, len(picks))
+			for i, p := range picks {
+				sets[i] = formatNumbers(p)
+			}
+			data := broadcastData(TypeWeeklyReminder, drawNo, map[string]string{"picks": strings.Join(sets, ";")})
+			return &PushNotification{
+				UserID:     &userID,
+				Type:       TypeWeeklyReminder,
+				Title:      title,
+				Body:       body,
+				Data:       &data,
+				CollapseID: fmt.Sprintf("draw-reminder-%d", drawNo),
+				ThreadID:   fmt.Sprintf("draw-%d", drawNo),
+				expiresAt:  cutoff,
+			}
+		},
+	})
+}
+
+// startBroadcast 발송을 선점하고 백그라운드에서 배치 제출 시작 (이미 발송한 회차면 무시)
+// 중단된 발송을 선점하면 마지막으로 처리한 사용자 다음부터 이어서 보낸다.
+func (s *Service) startBroadcast(ctx context.Context, b *broadcast) error {
+	cursor, claimed, err := s.repo.ClaimBroadcast(ctx, b.notifType, b.drawNo, broadcastLease)
+	if err != nil {
+		return fmt.Errorf("claim %s broadcast for draw %d: %w", b.notifType, b.drawNo, err)
+	}
+	if !claimed {
+		s.log.Infof("%s broadcast for draw %d already started, skipping", b.notifType, b.drawNo)
+		return nil
+	}
+	if cursor > 0 {
+		s.log.Infof("resuming %s broadcast for draw %d after user %d", b.notifType, b.drawNo, cursor)
+	}
+
+	go s.runBroadcast(ctx, b, cursor)
+	return nil
+}
+
+// runBroadcast 발송 대상 사용자를 배치로 나눠 워커 풀에 제출
+// 앞에서부터 연속으로 끝난 배치까지 커서를 기록하고, 대상 조회가 끝까지 완료되어야 완료로 기록한다.
+// 중간에 ctx가 취소되거나 오류가 나면 선점을 해제해 다음 선점 시 커서부터 재개한다.
+func (s *Service) runBroadcast(ctx context.Context, b *broadcast, cursor int64) {
+	batchSize := s.BroadcastBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBroadcastBatch
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	progress := newBroadcastProgress(cursor)
+	lastUserID := cursor
+	batches := 0
+	scanned := false
+
+	for ctx.Err() == nil {
+		userIDs, err := s.repo.GetBroadcastRecipients(ctx, lastUserID, batchSize, s.unsubscribe != nil)
+		if err != nil {
+			s.log.Errorf("%s broadcast for draw %d: failed to load recipients: %v", b.notifType, b.drawNo, err)
+			break
+		}
+		if len(userIDs) == 0 {
+			scanned = true
+			break
+		}
+		lastUserID = userIDs[len(userIDs)-1]
+		batches++
+
+		seq := progress.add(lastUserID)
+		wg.Add(1)
+		task := func(ctx context.Context) (interface{}, error) {
+			defer wg.Done()
+			sent, err := s.sendBroadcastBatch(ctx, b, userIDs)
+			if err != nil {
+				// 끝나지 않은 배치는 커서에 반영하지 않음 (재개 시 다시 발송)
+				return nil, err
+			}
+			if upTo, recipients, ok := progress.done(seq, sent); ok {
+				if err := s.repo.AdvanceBroadcast(context.Background(), b.notifType, b.drawNo, upTo, recipients); err != nil {
+					s.log.Errorf("%s broadcast for draw %d: failed to record progress: %v", b.notifType, b.drawNo, err)
+				}
+			}
+			return nil, nil
+		}
+		if !s.submitBatch(ctx, task) {
+			wg.Done()
+			break
+		}
+		if len(userIDs) < batchSize {
+			scanned = true
+			break
+		}
+	}
+	wg.Wait()
+
+	if !scanned || !progress.complete() {
+		if err := s.repo.ReleaseBroadcast(context.Background(), b.notifType, b.drawNo); err != nil {
+			s.log.Errorf("failed to release %s broadcast for draw %d: %v", b.notifType, b.drawNo, err)
+		}
+		s.log.Warnf("%s broadcast for draw %d interrupted after user %d (%d recipients), will resume on next claim",
+			b.notifType, b.drawNo, progress.cursor(), progress.recipients())
+		return
+	}
+
+	if err := s.repo.CompleteBroadcast(context.Background(), b.notifType, b.drawNo); err != nil {
+		s.log.Errorf("failed to complete %s broadcast for draw %d: %v", b.notifType, b.drawNo, err)
+	}
+	s.log.Infof("%s broadcast for draw %d completed: %d recipients in %d batches (%s)",
+		b.notifType, b.drawNo, progress.recipients(), batches, time.Since(start).Round(time.Millisecond))
+}
+
+// broadcastProgress 제출 순서대로 앞에서부터 연속으로 끝난 배치까지의 사용자 ID 커서
+// 배치는 워커 풀에서 순서 없이 끝나므로, 앞 배치가 끝나지 않았으면 뒤 배치가 끝나도 커서를 옮기지 않는다.
+type broadcastProgress struct {
+	mu    sync.Mutex
+	last  int64   // 연속 완료된 마지막 사용자 ID
+	ends  []int64 // 배치별 마지막 사용자 ID
+	sent  []int   // 배치별 알림 생성 수 (-1 = 미완료)
+	next  int     // 커서에 반영하지 않은 첫 배치
+	total int     // 커서에 반영한 알림 생성 수
+}
+
+func newBroadcastProgress(cursor int64) *broadcastProgress {
+	return &broadcastProgress{last: cursor}
+}
+
+// add 제출할 배치 등록 후 순번 반환
+func (p *broadcastProgress) add(lastUserID int64) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ends = append(p.ends, lastUserID)
+	p.sent = append(p.sent, -1)
+	return len(p.ends) - 1
+}
+
+// done 배치 완료 기록, 커서가 움직였으면 새 커서와 새로 반영된 알림 생성 수 반환
+func (p *broadcastProgress) done(seq, sent int) (int64, int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent[seq] = sent
+
+	added := 0
+	advanced := false
+	for p.next < len(p.ends) && p.sent[p.next] >= 0 {
+		p.last = p.ends[p.next]
+		added += p.sent[p.next]
+		p.next++
+		advanced = true
+	}
+	p.total += added
+	return p.last, added, advanced
+}
+
+// complete 제출한 배치가 모두 커서에 반영되었는지
+func (p *broadcastProgress) complete() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.next == len(p.ends)
+}
+
+func (p *broadcastProgress) cursor() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last
+}
+
+func (p *broadcastProgress) recipients() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.total
+}
+
+// submitBatch 배치 작업을 워커 풀에 제출 (풀이 가득 차면 자리가 날 때까지 대기)
+// 워커 풀이 없으면 현재 고루틴에서 실행한다. ctx가 취소되어 제출하지 못하면 false.
+func (s *Service) submitBatch(ctx context.Context, task worker.TaskFunc) bool {
+	if s.jobPool == nil {
+		task(ctx)
+		return true
+	}
+	select {
+	case s.jobPool <- worker.Job{
+		Type: worker.JobTypeTask,
+		TxID: txid.NewID(),
+		Ctx:  ctx,
+		Task: task,
+	}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sendBroadcastBatch 한 배치의 사용자에게 알림을 큐에 넣고, 알림을 생성한 사용자 수 반환
+// 알림 설정(과 필요하면 추천번호)은 배치 단위로 한 번에 조회한다.
+// 설정 조회에 실패하거나 ctx가 취소되어 배치를 끝내지 못하면 오류를 반환한다.
+func (s *Service) sendBroadcastBatch(ctx context.Context, b *broadcast, userIDs []int64) (int, error) {
+	prefs, err := s.repo.GetPreferencesByUserIDs(ctx, userIDs)
+	if err != nil {
+		s.log.Errorf("%s broadcast for draw %d: failed to load preferences: %v", b.notifType, b.drawNo, err)
+		return 0, err
+	}
+
+	var picks map[int64][][]int
+	if b.withPicks {
+		if picks, err = s.repo.GetPicksByDrawNo(ctx, userIDs, b.drawNo); err != nil {
+			// 추천번호 없이 기본 문구로 발송
+			s.log.Errorf("%s broadcast for draw %d: failed to load picks: %v", b.notifType, b.drawNo, err)
+		}
+	}
+
+	now := time.Now()
+	var sent int
+	var wake bool
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		pn := b.newMessage(userID, picks[userID])
+		ready, err := s.enqueueWithPrefs(ctx, pn, prefs[userID], now)
+		if err != nil {
+			s.log.Errorf("%s broadcast for draw %d: user %d: %v", b.notifType, b.drawNo, userID, err)
+			continue
+		}
+		if pn.ID > 0 { // 수신 거부 등으로 건너뛰면 저장되지 않음
+			sent++
+		}
+		wake = wake || ready
+	}
+	if wake {
+		s.dispatcher.Wake()
+	}
+	return sent, ctx.Err()
+}
+
+// broadcastData 전체 발송 알림의 data 필드 (이메일 템플릿에서도 사용)
+func broadcastData(notifType string, drawNo int, extra map[string]string) string {
+	data := map[string]string{
+		"type":    notifType,
+		"draw_no": strconv.Itoa(drawNo),
+	}
+	for k, v := range extra {
+		if v != "" {
+			data[k] = v
+		}
+	}
+	b, _ := json.Marshal(data)
+	return string(b)
+}
+
+// drawPublishedMessage 당첨번호 발표 알림 문구
+func drawPublishedMessage(draw *lotto.LottoDraw) (string, string) {
+	title := fmt.Sprintf("%d회 로또 당첨번호 발표", draw.DrawNo)
+	body := fmt.Sprintf("%s + 보너스 %d", joinNumbers([]int{draw.Num1, draw.Num2, draw.Num3, draw.Num4, draw.Num5, draw.Num6}), draw.BonusNum)
+	if draw.FirstWinners > 0 {
+		body += fmt.Sprintf(" | 1등 %s (%d명)", formatKRW(draw.FirstPerGame), draw.FirstWinners)
+	} else {
+		body += " | 1등 당첨자 없음"
+	}
+	return title, body
+}
+
+// reminderMessage 판매 마감 리마인더 문구 (추천번호가 있으면 첫 번째 조합과 개수 표시)
+func reminderMessage(drawNo int, cutoff time.Time, picks [][]int) (string, string) {
+	title := fmt.Sprintf("%d회 로또 판매 마감 %s", drawNo, cutoff.Format("15:04"))
+	switch len(picks) {
+	case 0:
+		return title, "마감 전에 이번 주 추천번호를 받아보세요."
+	case 1:
+		return title, fmt.Sprintf("내 번호 %s 구매하셨나요?", joinNumbers(picks[0]))
+	default:
+		return title, fmt.Sprintf("내 번호 %s 외 %d개 조합, 구매하셨나요?", joinNumbers(picks[0]), len(picks)-1)
+	}
+}
+
+func joinNumbers(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatKRW 금액을 억/만원 단위로 표기 (예: 2345678900 → "23억 4567만원")
+func formatKRW(amount int64) string {
+	eok, man := amount/100000000, amount%100000000/10000
+	switch {
+	case eok > 0 && man > 0:
+		return fmt.Sprintf("%d억 %d만원", eok, man)
+	case eok > 0:
+		return fmt.Sprintf("%d억원", eok)
+	case man > 0:
+		return fmt.Sprintf("%d만원", man)
+	default:
+		return fmt.Sprintf("%d원", amount)
+	}
+}
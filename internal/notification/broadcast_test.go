@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/example/LottoSmash/internal/lotto"
+)
+
+func TestFormatKRW(t *testing.T) {
+	tests := []struct {
+		amount int64
+		want   string
+	}{
+		{2345678900, "23억 4567만원"},
+		{3000000000, "30억원"},
+		{50000, "5만원"},
+		{5000, "5000원"},
+	}
+	for _, tt := range tests {
+		if got := formatKRW(tt.amount); got != tt.want {
+			t.Errorf("formatKRW(%d) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestDrawPublishedMessage(t *testing.T) {
+	draw := &lotto.LottoDraw{DrawNo: 1200, Num1: 1, Num2: 2, Num3: 3, Num4: 4, Num5: 5, Num6: 6, BonusNum: 7,
+		FirstWinners: 12, FirstPerGame: 2345678900}
+	title, body := drawPublishedMessage(draw)
+	if title != "1200회 로또 당첨번호 발표" || body != "1, 2, 3, 4, 5, 6 + 보너스 7 | 1등 23억 4567만원 (12명)" {
+		t.Errorf("message = %q / %q", title, body)
+	}
+
+	draw.FirstWinners = 0
+	if _, body := drawPublishedMessage(draw); !strings.HasSuffix(body, "1등 당첨자 없음") {
+		t.Errorf("no winners body = %q", body)
+	}
+}
+
+func TestReminderMessage(t *testing.T) {
+	cutoff := lotto.DrawSalesCutoff(1200)
+	title, body := reminderMessage(1200, cutoff, [][]int{{1, 2, 3, 4, 5, 6}, {7, 8, 9, 10, 11, 12}})
+	if title != "1200회 로또 판매 마감 20:00" {
+		t.Errorf("title = %q", title)
+	}
+	if body != "내 번호 1, 2, 3, 4, 5, 6 외 1개 조합, 구매하셨나요?" {
+		t.Errorf("body = %q", body)
+	}
+	if _, body := reminderMessage(1200, cutoff, nil); !strings.Contains(body, "추천번호") {
+		t.Errorf("no picks body = %q", body)
+	}
+}
+
+func TestBroadcastProgress(t *testing.T) {
+	p := newBroadcastProgress(100)
+	first := p.add(600)
+	second := p.add(1100)
+	third := p.add(1300)
+
+	// 뒤 배치가 먼저 끝나도 앞 배치가 끝나기 전에는 커서를 옮기지 않음
+	if _, _, ok := p.done(second, 40); ok {
+		t.Error("cursor advanced past an unfinished batch")
+	}
+	cursor, added, ok := p.done(first, 50)
+	if !ok || cursor != 1100 || added != 90 {
+		t.Errorf("after first batch: cursor = %d, added = %d, ok = %v", cursor, added, ok)
+	}
+	if p.complete() {
+		t.Error("third batch still pending")
+	}
+
+	// 중단된 배치는 done을 호출하지 않으므로 커서는 마지막 연속 완료 배치에 머묾
+	if p.cursor() != 1100 || p.recipients() != 90 {
+		t.Errorf("cursor = %d, recipients = %d", p.cursor(), p.recipients())
+	}
+
+	if cursor, _, _ := p.done(third, 0); cursor != 1300 || !p.complete() {
+		t.Errorf("after last batch: cursor = %d, complete = %v", cursor, p.complete())
+	}
+}
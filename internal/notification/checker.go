@@ -3,13 +3,24 @@ package notification
 import "github.com/example/LottoSmash/internal/lotto"
 
 // CheckWinning 추천번호와 당첨번호를 비교하여 당첨 결과를 반환
-// 한국 로또 6/45 등수 기준:
-//   - 1등: 6개 번호 일치
-//   - 2등: 5개 번호 일치 + 보너스 번호 일치
-//   - 3등: 5개 번호 일치
-//   - 4등: 4개 번호 일치
-//   - 5등: 3개 번호 일치
+// 등수 기준은 lotto.PrizeRank 참고
 func CheckWinning(draw *lotto.LottoDraw, rec RecommendationRow) WinningCheck {
+	wc := matchNumbers(draw, rec.Numbers)
+	wc.RecommendationID = &rec.ID
+	wc.UserID = rec.UserID
+	return wc
+}
+
+// CheckTicketWinning 구매 용지 게임과 당첨번호를 비교하여 당첨 결과를 반환
+func CheckTicketWinning(draw *lotto.LottoDraw, game TicketGameRow) WinningCheck {
+	wc := matchNumbers(draw, game.Numbers)
+	wc.TicketGameID = &game.ID
+	wc.UserID = &game.UserID
+	return wc
+}
+
+// matchNumbers 번호 6개와 당첨번호 비교 (대상 ID는 호출자가 설정)
+func matchNumbers(draw *lotto.LottoDraw, numbers []int) WinningCheck {
 	winNums := map[int]bool{
 		draw.Num1: true,
 		draw.Num2: true,
@@ -20,14 +31,14 @@ func CheckWinning(draw *lotto.LottoDraw, rec RecommendationRow) WinningCheck {
 	}
 
 	var matched []int
-	for _, n := range rec.Numbers {
+	for _, n := range numbers {
 		if winNums[n] {
 			matched = append(matched, n)
 		}
 	}
 
 	bonusMatched := false
-	for _, n := range rec.Numbers {
+	for _, n := range numbers {
 		if n == draw.BonusNum && !winNums[n] {
 			bonusMatched = true
 			break
@@ -36,33 +47,16 @@ func CheckWinning(draw *lotto.LottoDraw, rec RecommendationRow) WinningCheck {
 
 	matchCount := len(matched)
 	var prizeRank *int
-
-	switch {
-	case matchCount == 6:
-		rank := 1
-		prizeRank = &rank
-	case matchCount == 5 && bonusMatched:
-		rank := 2
-		prizeRank = &rank
-	case matchCount == 5:
-		rank := 3
-		prizeRank = &rank
-	case matchCount == 4:
-		rank := 4
-		prizeRank = &rank
-	case matchCount == 3:
-		rank := 5
+	if rank := lotto.PrizeRank(matchCount, bonusMatched); rank > 0 {
 		prizeRank = &rank
 	}
 
 	return WinningCheck{
-		RecommendationID: rec.ID,
-		UserID:           rec.UserID,
-		DrawNo:           draw.DrawNo,
-		MatchedNumbers:   matched,
-		MatchedCount:     matchCount,
-		BonusMatched:     bonusMatched,
-		PrizeRank:        prizeRank,
+		DrawNo:         draw.DrawNo,
+		MatchedNumbers: matched,
+		MatchedCount:   matchCount,
+		BonusMatched:   bonusMatched,
+		PrizeRank:      prizeRank,
 	}
 }
 
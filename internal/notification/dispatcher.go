@@ -0,0 +1,239 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/example/LottoSmash/internal/logger"
+	"github.com/example/LottoSmash/internal/metrics"
+)
+
+// 디스패처 기본값
+const (
+	DefaultDispatchInterval = 5 * time.Second
+	DefaultDispatchBatch    = 50
+	DefaultMaxAttempts      = 6
+	DefaultRetryBaseDelay   = 30 * time.Second
+	DefaultRetryMaxDelay    = time.Hour
+
+	deliveryLease = 2 * time.Minute // 발송 중 점유 시간 (지나면 다른 디스패처가 다시 점유)
+)
+
+// deliveryStore 디스패처가 사용하는 발송 큐 저장소 (테스트 시 대체)
+type deliveryStore interface {
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]claimedDelivery, error)
+	MarkDeliverySent(ctx context.Context, id int64) error
+	ScheduleDeliveryRetry(ctx context.Context, id int64, delay time.Duration, errMsg string) error
+	MarkDeliveryDead(ctx context.Context, id int64, errMsg string) error
+	RefreshNotificationStatus(ctx context.Context, notificationID int64) error
+	DeactivateTokenByID(ctx context.Context, id int64, reason string) (bool, error)
+
+	ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]claimedEmail, error)
+	MarkEmailSent(ctx context.Context, id int64) error
+	ScheduleEmailRetry(ctx context.Context, id int64, delay time.Duration, errMsg string) error
+	MarkEmailDead(ctx context.Context, id int64, errMsg string) error
+}
+
+// DispatcherOptions 발송 큐 처리 설정 (0이면 기본값)
+type DispatcherOptions struct {
+	Interval       time.Duration // 큐 확인 주기
+	BatchSize      int           // 한 번에 점유해 동시에 발송할 작업 수
+	MaxAttempts    int           // 이 횟수만큼 실패하면 dead
+	RetryBaseDelay time.Duration // 첫 재시도 대기 (이후 2배씩 증가)
+	RetryMaxDelay  time.Duration // 재시도 대기 상한
+}
+
+func (o DispatcherOptions) withDefaults() DispatcherOptions {
+	if o.Interval <= 0 {
+		o.Interval = DefaultDispatchInterval
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultDispatchBatch
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.RetryBaseDelay <= 0 {
+		o.RetryBaseDelay = DefaultRetryBaseDelay
+	}
+	if o.RetryMaxDelay <= 0 {
+		o.RetryMaxDelay = DefaultRetryMaxDelay
+	}
+	return o
+}
+
+// retryDelay attempt번째 실패 후 재시도까지 대기 시간 (지수 백오프)
+func (o DispatcherOptions) retryDelay(attempt int) time.Duration {
+	delay := o.RetryBaseDelay
+	for i := 1; i < attempt && delay < o.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > o.RetryMaxDelay {
+		delay = o.RetryMaxDelay
+	}
+	return delay
+}
+
+// Dispatcher push_deliveries / email_deliveries 큐를 주기적으로 비우는 백그라운드 발송기
+// 여러 서버가 동시에 실행해도 SKIP LOCKED로 같은 작업을 중복 점유하지 않는다.
+type Dispatcher struct {
+	store deliveryStore
+	push  PushSender
+	log   *logger.Logger
+	opts  DispatcherOptions
+	wake  chan struct{}
+
+	email    EmailSender // nil이면 이메일 큐를 처리하지 않음
+	renderer *EmailRenderer
+}
+
+func NewDispatcher(repo *Repository, push PushSender, log *logger.Logger, opts DispatcherOptions) *Dispatcher {
+	return &Dispatcher{
+		store: repo,
+		push:  push,
+		log:   log,
+		opts:  opts.withDefaults(),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// Wake 새 작업이 쌓였음을 알려 다음 주기를 기다리지 않고 발송
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 큐 처리 루프 시작. ctx가 취소될 때까지 블록된다.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+
+	d.log.Infof("push dispatcher started (interval: %s, batch: %d, max attempts: %d)",
+		d.opts.Interval, d.opts.BatchSize, d.opts.MaxAttempts)
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.log.Infof("push dispatcher stopping")
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+		d.drain(ctx)
+	}
+}
+
+// drain 발송 시각이 된 작업이 없을 때까지 배치 단위로 처리
+func (d *Dispatcher) drain(ctx context.Context) {
+	d.drainQueue(ctx, "push", d.dispatchOnce)
+	if d.email != nil {
+		d.drainQueue(ctx, "email", d.dispatchEmailOnce)
+	}
+}
+
+func (d *Dispatcher) drainQueue(ctx context.Context, name string, dispatch func(context.Context) (int, error)) {
+	for ctx.Err() == nil {
+		n, err := dispatch(ctx)
+		if err != nil {
+			d.log.Errorf("%s dispatcher: %v", name, err)
+			return
+		}
+		if n < d.opts.BatchSize {
+			return
+		}
+	}
+}
+
+// dispatchOnce 한 배치를 점유해 동시에 발송하고, 관련 알림 상태를 갱신
+func (d *Dispatcher) dispatchOnce(ctx context.Context) (int, error) {
+	claimed, err := d.store.ClaimDueDeliveries(ctx, d.opts.BatchSize, deliveryLease)
+	if err != nil {
+		return 0, err
+	}
+	if len(claimed) == 0 {
+		return 0, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := range claimed {
+		wg.Add(1)
+		go func(c *claimedDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, c)
+		}(&claimed[i])
+	}
+	wg.Wait()
+
+	refreshed := make(map[int64]bool)
+	for _, c := range claimed {
+		if refreshed[c.NotificationID] {
+			continue
+		}
+		refreshed[c.NotificationID] = true
+		if err := d.store.RefreshNotificationStatus(ctx, c.NotificationID); err != nil {
+			d.log.Errorf("failed to refresh notification %d status: %v", c.NotificationID, err)
+		}
+	}
+	return len(claimed), nil
+}
+
+// deliver 작업 하나를 발송하고 결과에 따라 sent / 재시도 / dead 처리
+func (d *Dispatcher) deliver(ctx context.Context, c *claimedDelivery) {
+	var err error
+	if !c.Token.IsActive {
+		err = d.store.MarkDeliveryDead(ctx, c.ID, "device token deactivated")
+	} else if sendErr := d.push.Send(ctx, c.Token, c.Message); sendErr == nil {
+		err = d.store.MarkDeliverySent(ctx, c.ID)
+	} else if !retryable(sendErr) || c.Attempts >= d.opts.MaxAttempts {
+		d.log.Warnf("push delivery %d dead after %d attempt(s): %v", c.ID, c.Attempts, sendErr)
+		err = d.store.MarkDeliveryDead(ctx, c.ID, sendErr.Error())
+		d.pruneToken(ctx, c.Token, sendErr)
+	} else {
+		delay := d.opts.retryDelay(c.Attempts)
+		d.log.Infof("push delivery %d failed (attempt %d), retrying in %s: %v", c.ID, c.Attempts, delay, sendErr)
+		err = d.store.ScheduleDeliveryRetry(ctx, c.ID, delay, sendErr.Error())
+	}
+	if err != nil {
+		// 상태 저장에 실패해도 lease가 지나면 다시 점유된다.
+		d.log.Errorf("failed to update push delivery %d: %v", c.ID, err)
+	}
+}
+
+// pruneToken 제공자가 등록 해제되었거나 잘못되었다고 응답한 토큰 비활성화
+// 이후 같은 토큰으로 쌓인 발송 작업은 점유 시 비활성 토큰으로 dead 처리된다.
+// ErrInvalidPayload(메시지 크기/형식 오류)는 모든 토큰에서 똑같이 실패하므로 토큰을 건드리지 않는다.
+func (d *Dispatcher) pruneToken(ctx context.Context, token DeviceToken, sendErr error) {
+	var reason string
+	switch {
+	case errors.Is(sendErr, ErrTokenUnregistered):
+		reason = TokenReasonUnregistered
+	case errors.Is(sendErr, ErrInvalidToken):
+		reason = TokenReasonInvalid
+	default:
+		return
+	}
+
+	pruned, err := d.store.DeactivateTokenByID(ctx, token.ID, reason)
+	if err != nil {
+		d.log.Errorf("failed to deactivate device token %d: %v", token.ID, err)
+		return
+	}
+	if pruned {
+		metrics.AddPrunedTokens(reason, 1)
+		d.log.Infof("deactivated %s device token %d of user %d (%s)", token.Platform, token.ID, token.UserID, reason)
+	}
+}
+
+// retryable 다시 시도할 만한 오류인지
+// 제공자가 거부한 오류는 한도 초과/서버 오류만 재시도하고, 네트워크 오류 등은 재시도한다.
+func retryable(err error) bool {
+	var pushErr *PushError
+	if errors.As(err, &pushErr) {
+		return pushErr.Retryable()
+	}
+	return true
+}
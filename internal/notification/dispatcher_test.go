@@ -0,0 +1,230 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/example/LottoSmash/internal/logger"
+)
+
+// --- mock store ---
+
+type mockDeliveryStore struct {
+	mu        sync.Mutex
+	due       []claimedDelivery
+	sent      []int64
+	dead      map[int64]string
+	retries   map[int64]time.Duration
+	refreshed []int64
+	pruned    map[int64]string
+
+	dueEmails []claimedEmail
+}
+
+func newMockDeliveryStore(due ...claimedDelivery) *mockDeliveryStore {
+	return &mockDeliveryStore{due: due, dead: make(map[int64]string), retries: make(map[int64]time.Duration), pruned: make(map[int64]string)}
+}
+
+func (m *mockDeliveryStore) ClaimDueDeliveries(_ context.Context, limit int, _ time.Duration) ([]claimedDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if limit > len(m.due) {
+		limit = len(m.due)
+	}
+	claimed := m.due[:limit]
+	m.due = m.due[limit:]
+	return claimed, nil
+}
+
+func (m *mockDeliveryStore) MarkDeliverySent(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, id)
+	return nil
+}
+
+func (m *mockDeliveryStore) ScheduleDeliveryRetry(_ context.Context, id int64, delay time.Duration, _ string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[id] = delay
+	return nil
+}
+
+func (m *mockDeliveryStore) MarkDeliveryDead(_ context.Context, id int64, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dead[id] = errMsg
+	return nil
+}
+
+func (m *mockDeliveryStore) RefreshNotificationStatus(_ context.Context, notificationID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshed = append(m.refreshed, notificationID)
+	return nil
+}
+
+func (m *mockDeliveryStore) DeactivateTokenByID(_ context.Context, id int64, reason string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, already := m.pruned[id]
+	m.pruned[id] = reason
+	return !already, nil
+}
+
+// 이메일 작업도 같은 sent/retries/dead 기록을 사용 (테스트에서 ID가 겹치지 않게 구성)
+func (m *mockDeliveryStore) ClaimDueEmails(_ context.Context, limit int, _ time.Duration) ([]claimedEmail, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if limit > len(m.dueEmails) {
+		limit = len(m.dueEmails)
+	}
+	claimed := m.dueEmails[:limit]
+	m.dueEmails = m.dueEmails[limit:]
+	return claimed, nil
+}
+
+func (m *mockDeliveryStore) MarkEmailSent(ctx context.Context, id int64) error {
+	return m.MarkDeliverySent(ctx, id)
+}
+
+func (m *mockDeliveryStore) ScheduleEmailRetry(ctx context.Context, id int64, delay time.Duration, errMsg string) error {
+	return m.ScheduleDeliveryRetry(ctx, id, delay, errMsg)
+}
+
+func (m *mockDeliveryStore) MarkEmailDead(ctx context.Context, id int64, errMsg string) error {
+	return m.MarkDeliveryDead(ctx, id, errMsg)
+}
+
+// tokenErrSender 토큰 값별로 지정한 오류를 반환하는 발송기
+type tokenErrSender map[string]error
+
+func (s tokenErrSender) Send(_ context.Context, token DeviceToken, _ PushMessage) error {
+	return s[token.Token]
+}
+
+func newTestDispatcher(t *testing.T, store deliveryStore, push PushSender, opts DispatcherOptions) *Dispatcher {
+	t.Helper()
+	lg, err := logger.New(t.TempDir(), "debug")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { lg.Close() })
+	return &Dispatcher{store: store, push: push, log: lg, opts: opts.withDefaults(), wake: make(chan struct{}, 1)}
+}
+
+func delivery(id, notificationID int64, token string, attempts int) claimedDelivery {
+	return claimedDelivery{
+		ID:             id,
+		NotificationID: notificationID,
+		Attempts:       attempts,
+		Token:          DeviceToken{ID: id, Token: token, IsActive: true},
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	opts := DispatcherOptions{RetryBaseDelay: 30 * time.Second, RetryMaxDelay: 10 * time.Minute}.withDefaults()
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, w := range want {
+		if got := opts.retryDelay(i + 1); got != w {
+			t.Errorf("retryDelay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
+
+// 한 토큰이 실패해도 다른 토큰 발송은 성공으로 기록되고, 오류 종류에 따라 재시도/dead 처리
+func TestDispatchOnceOutcomes(t *testing.T) {
+	inactive := delivery(5, 2, "inactive", 1)
+	inactive.Token.IsActive = false
+
+	store := newMockDeliveryStore(
+		delivery(1, 1, "ok", 1),
+		delivery(2, 1, "unavailable", 2),
+		delivery(3, 1, "unregistered", 1),
+		delivery(4, 2, "unavailable", 3),
+		inactive,
+	)
+	push := tokenErrSender{
+		"unavailable":  &PushError{Provider: "fcm", StatusCode: 503},
+		"unregistered": &PushError{Provider: "fcm", StatusCode: 404, Err: ErrTokenUnregistered},
+	}
+	d := newTestDispatcher(t, store, push, DispatcherOptions{BatchSize: 10, MaxAttempts: 3, RetryBaseDelay: time.Second})
+
+	n, err := d.dispatchOnce(context.Background())
+	if err != nil || n != 5 {
+		t.Fatalf("dispatchOnce = %d, %v", n, err)
+	}
+
+	if len(store.sent) != 1 || store.sent[0] != 1 {
+		t.Errorf("sent = %v, want [1]", store.sent)
+	}
+	if delay, ok := store.retries[2]; !ok || delay != 2*time.Second {
+		t.Errorf("delivery 2 retry = %v (scheduled %v), want 2s", delay, ok)
+	}
+	for _, id := range []int64{3, 4, 5} {
+		if _, ok := store.dead[id]; !ok {
+			t.Errorf("delivery %d should be dead", id)
+		}
+	}
+	if len(store.refreshed) != 2 {
+		t.Errorf("refreshed notifications = %v, want 2 distinct", store.refreshed)
+	}
+	// 등록 해제된 토큰만 비활성화 (재시도 초과는 토큰 문제가 아님)
+	if len(store.pruned) != 1 || store.pruned[3] != TokenReasonUnregistered {
+		t.Errorf("pruned = %v, want token 3 unregistered", store.pruned)
+	}
+}
+
+func TestDispatchOncePayloadErrorKeepsToken(t *testing.T) {
+	store := newMockDeliveryStore(
+		delivery(1, 1, "too-large", 1),
+		delivery(2, 1, "bad-token", 1),
+	)
+	push := tokenErrSender{
+		"too-large": &PushError{Provider: "webpush", Code: "EncryptionFailed", Message: "payload too large", Err: ErrInvalidPayload},
+		"bad-token": &PushError{Provider: "apns", StatusCode: 400, Code: "BadDeviceToken", Err: ErrInvalidToken},
+	}
+	d := newTestDispatcher(t, store, push, DispatcherOptions{BatchSize: 10, MaxAttempts: 3, RetryBaseDelay: time.Second})
+
+	if _, err := d.dispatchOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int64{1, 2} {
+		if _, ok := store.dead[id]; !ok {
+			t.Errorf("delivery %d should be dead", id)
+		}
+	}
+	// 메시지 오류는 다른 기기에서도 똑같이 실패하므로 토큰은 유지
+	if len(store.pruned) != 1 || store.pruned[2] != TokenReasonInvalid {
+		t.Errorf("pruned = %v, want only token 2 invalid", store.pruned)
+	}
+}
+
+func TestDrainProcessesAllBatches(t *testing.T) {
+	var due []claimedDelivery
+	for i := int64(1); i <= 7; i++ {
+		due = append(due, delivery(i, i, "ok", 1))
+	}
+	store := newMockDeliveryStore(due...)
+	d := newTestDispatcher(t, store, tokenErrSender{}, DispatcherOptions{BatchSize: 3})
+
+	d.drain(context.Background())
+	if len(store.sent) != 7 {
+		t.Errorf("sent %d, want 7", len(store.sent))
+	}
+}
+
+func TestRetryableNetworkError(t *testing.T) {
+	if !retryable(errors.New("connection reset")) {
+		t.Error("network errors should be retried")
+	}
+	if retryable(&PushError{StatusCode: 400, Err: ErrInvalidPayload}) {
+		t.Error("invalid argument should not be retried")
+	}
+	if !retryable(&PushError{StatusCode: 429}) {
+		t.Error("rate limit should be retried")
+	}
+}
@@ -0,0 +1,266 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// EmailSender HTML 이메일 발송기 (auth.SMTPEmailSender / auth.NoopEmailSender)
+type EmailSender interface {
+	SendHTMLEmail(to, subject, htmlBody string, headers map[string]string) error
+}
+
+// ========================================
+// 수신 거부 토큰
+// ========================================
+
+// UnsubscribeTokens 이메일 수신 거부 링크용 서명 토큰 ("<userID>.<HMAC-SHA256>")
+// 로그인 없이 링크만으로 수신 거부할 수 있어야 하므로 만료 없이 서명만 검증한다.
+type UnsubscribeTokens struct {
+	key []byte
+}
+
+func NewUnsubscribeTokens(key string) *UnsubscribeTokens {
+	return &UnsubscribeTokens{key: []byte(key)}
+}
+
+// Sign 사용자 수신 거부 토큰 생성
+func (t *UnsubscribeTokens) Sign(userID int64) string {
+	id := strconv.FormatInt(userID, 10)
+	return id + "." + base64.RawURLEncoding.EncodeToString(t.mac(id))
+}
+
+// Verify 토큰 서명 검증 후 사용자 ID 반환
+func (t *UnsubscribeTokens) Verify(token string) (int64, error) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, t.mac(id)) {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, ErrInvalidUnsubscribeToken
+	}
+	return userID, nil
+}
+
+func (t *UnsubscribeTokens) mac(id string) []byte {
+	h := hmac.New(sha256.New, t.key)
+	h.Write([]byte("unsubscribe:" + id))
+	return h.Sum(nil)
+}
+
+// ========================================
+// 템플릿
+// ========================================
+
+const emailLayout = `{{define "layout"}}<!DOCTYPE html>
+<html lang="ko">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>{{.Title}}</title></head>
+<body style="margin:0;padding:0;background:#f4f5f7;font-family:'Apple SD Gothic Neo','Malgun Gothic',sans-serif;color:#222;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0"><tr><td align="center" style="padding:24px 12px;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="max-width:560px;background:#fff;border-radius:12px;">
+<tr><td style="padding:24px 28px 8px;font-size:14px;font-weight:bold;color:#e8590c;">LottoSmash</td></tr>
+<tr><td style="padding:0 28px 24px;">
+<h1 style="margin:8px 0 16px;font-size:20px;">{{.Title}}</h1>
+{{template "content" .}}
+{{if .AppURL}}<p style="margin:24px 0 0;"><a href="{{.AppURL}}" style="display:inline-block;padding:10px 20px;background:#e8590c;color:#fff;border-radius:6px;text-decoration:none;">LottoSmash 열기</a></p>{{end}}
+</td></tr>
+<tr><td style="padding:16px 28px;border-top:1px solid #eee;font-size:12px;color:#888;">
+이 메일은 LottoSmash 알림 설정에 따라 발송되었습니다.<br>
+더 이상 받지 않으려면 <a href="{{.UnsubscribeURL}}" style="color:#888;">이메일 수신 거부</a>를 눌러주세요.
+</td></tr>
+</table>
+</td></tr></table>
+</body>
+</html>{{end}}
+{{define "balls"}}<p style="margin:0 0 16px;">{{range .}}<span style="display:inline-block;width:34px;height:34px;line-height:34px;margin:0 4px 4px 0;border-radius:17px;background:#fff3bf;text-align:center;font-weight:bold;">{{.}}</span>{{end}}</p>{{end}}`
+
+// emailContents 알림 유형별 본문 (없는 유형은 generic)
+var emailContents = map[string]string{
+	// 당첨 결과
+	TypeWinningResult: `{{define "content"}}<p style="margin:0 0 12px;font-size:16px;">{{.Body}}</p>
+{{if .PrizeRank}}<p style="margin:0 0 12px;font-size:28px;font-weight:bold;color:#e8590c;">{{.DrawNo}}회 {{.PrizeRank}}</p>{{end}}
+<p style="margin:0;color:#555;">당첨금 수령 기한은 지급 개시일로부터 1년입니다. 앱에서 당첨 내역을 확인하세요.</p>{{end}}`,
+
+	// 회차 당첨번호 요약
+	TypeDrawPublished: `{{define "content"}}{{if .Numbers}}{{template "balls" .Numbers}}{{if .Bonus}}<p style="margin:0 0 16px;color:#555;">보너스 번호 <b>{{.Bonus}}</b></p>{{end}}{{end}}
+<p style="margin:0;">{{.Body}}</p>{{end}}`,
+
+	// 주간 리마인더 (내 추천번호 요약)
+	TypeWeeklyReminder: `{{define "content"}}<p style="margin:0 0 16px;">{{.Body}}</p>
+{{if .Picks}}<p style="margin:0 0 8px;font-weight:bold;">{{.DrawNo}}회 내 번호</p>{{range .Picks}}{{template "balls" .}}{{end}}{{end}}{{end}}`,
+
+	"": `{{define "content"}}<p style="margin:0;">{{.Body}}</p>{{end}}`,
+}
+
+var emailTemplates = func() map[string]*template.Template {
+	base := template.Must(template.New("email").Parse(emailLayout))
+	tmpls := make(map[string]*template.Template, len(emailContents))
+	for notifType, content := range emailContents {
+		tmpls[notifType] = template.Must(template.Must(base.Clone()).Parse(content))
+	}
+	return tmpls
+}()
+
+// emailView 템플릿 데이터
+type emailView struct {
+	Title          string
+	Body           string
+	DrawNo         string
+	PrizeRank      string
+	Numbers        []int
+	Bonus          string
+	Picks          [][]int
+	AppURL         string
+	UnsubscribeURL string
+}
+
+// EmailRenderer 알림을 HTML 이메일로 변환
+type EmailRenderer struct {
+	baseURL string // 서비스 주소 (앱 링크, 수신 거부 링크)
+	tokens  *UnsubscribeTokens
+}
+
+func NewEmailRenderer(baseURL string, tokens *UnsubscribeTokens) *EmailRenderer {
+	return &EmailRenderer{baseURL: strings.TrimRight(baseURL, "/"), tokens: tokens}
+}
+
+// UnsubscribeURL 사용자 수신 거부 링크
+func (r *EmailRenderer) UnsubscribeURL(userID int64) string {
+	return r.baseURL + "/api/notifications/email/unsubscribe?token=" + url.QueryEscape(r.tokens.Sign(userID))
+}
+
+// Render 제목, HTML 본문, 추가 헤더(원클릭 수신 거부, RFC 8058) 생성
+func (r *EmailRenderer) Render(c *claimedEmail) (string, string, map[string]string, error) {
+	tmpl, ok := emailTemplates[c.Type]
+	if !ok {
+		tmpl = emailTemplates[""]
+	}
+
+	data := c.Message.Data
+	view := emailView{
+		Title:          c.Message.Title,
+		Body:           c.Message.Body,
+		DrawNo:         data["draw_no"],
+		Numbers:        parseNumbers(data["numbers"]),
+		Bonus:          data["bonus"],
+		AppURL:         r.baseURL,
+		UnsubscribeURL: r.UnsubscribeURL(c.UserID),
+	}
+	if rank, err := strconv.Atoi(data["prize_rank"]); err == nil {
+		view.PrizeRank = PrizeRankName(rank)
+	}
+	if picks := data["picks"]; picks != "" {
+		for _, set := range strings.Split(picks, ";") {
+			view.Picks = append(view.Picks, parseNumbers(set))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout", view); err != nil {
+		return "", "", nil, fmt.Errorf("render %s email: %w", c.Type, err)
+	}
+	headers := map[string]string{
+		"List-Unsubscribe":      "<" + view.UnsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return "[LottoSmash] " + c.Message.Title, buf.String(), headers, nil
+}
+
+// parseNumbers "1,2,3" 형식 번호 목록 파싱 (잘못된 값은 무시)
+func parseNumbers(s string) []int {
+	if s == "" {
+		return nil
+	}
+	var nums []int
+	for _, part := range strings.Split(s, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			nums = append(nums, n)
+		}
+	}
+	return nums
+}
+
+// formatNumbers 번호 목록을 "1,2,3" 형식으로 (알림 data 필드용)
+func formatNumbers(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ========================================
+// 발송 (Dispatcher)
+// ========================================
+
+// SetEmailSender 이메일 발송 큐 처리 활성화 (설정하지 않으면 email_deliveries를 처리하지 않음)
+func (d *Dispatcher) SetEmailSender(sender EmailSender, renderer *EmailRenderer) {
+	d.email = sender
+	d.renderer = renderer
+}
+
+// dispatchEmailOnce 이메일 한 배치를 점유해 발송하고, 관련 알림 상태를 갱신
+// SMTP 연결 수를 제한하기 위해 배치 안에서는 순서대로 보낸다.
+func (d *Dispatcher) dispatchEmailOnce(ctx context.Context) (int, error) {
+	claimed, err := d.store.ClaimDueEmails(ctx, d.opts.BatchSize, deliveryLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range claimed {
+		c := &claimed[i]
+		d.deliverEmail(ctx, c)
+		if err := d.store.RefreshNotificationStatus(ctx, c.NotificationID); err != nil {
+			d.log.Errorf("failed to refresh notification %d status: %v", c.NotificationID, err)
+		}
+	}
+	return len(claimed), nil
+}
+
+// deliverEmail 이메일 작업 하나를 발송하고 결과에 따라 sent / 재시도 / dead 처리
+func (d *Dispatcher) deliverEmail(ctx context.Context, c *claimedEmail) {
+	var err error
+	subject, body, headers, renderErr := d.renderer.Render(c)
+	if renderErr != nil {
+		d.log.Errorf("email delivery %d: %v", c.ID, renderErr)
+		err = d.store.MarkEmailDead(ctx, c.ID, renderErr.Error())
+	} else if sendErr := d.email.SendHTMLEmail(c.Email, subject, body, headers); sendErr == nil {
+		err = d.store.MarkEmailSent(ctx, c.ID)
+	} else if !retryableEmail(sendErr) || c.Attempts >= d.opts.MaxAttempts {
+		d.log.Warnf("email delivery %d dead after %d attempt(s): %v", c.ID, c.Attempts, sendErr)
+		err = d.store.MarkEmailDead(ctx, c.ID, sendErr.Error())
+	} else {
+		delay := d.opts.retryDelay(c.Attempts)
+		d.log.Infof("email delivery %d failed (attempt %d), retrying in %s: %v", c.ID, c.Attempts, delay, sendErr)
+		err = d.store.ScheduleEmailRetry(ctx, c.ID, delay, sendErr.Error())
+	}
+	if err != nil {
+		d.log.Errorf("failed to update email delivery %d: %v", c.ID, err)
+	}
+}
+
+// retryableEmail SMTP 영구 오류(5xx, 잘못된 주소 등)가 아니면 재시도
+func retryableEmail(err error) bool {
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code < 500
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"context"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnsubscribeTokens(t *testing.T) {
+	tokens := NewUnsubscribeTokens("secret")
+	token := tokens.Sign(42)
+
+	userID, err := tokens.Verify(token)
+	if err != nil || userID != 42 {
+		t.Fatalf("Verify = %d, %v", userID, err)
+	}
+
+	invalid := []string{
+		"",
+		"42",
+		"43" + token[2:],                       // 다른 사용자 ID
+		token[:len(token)-2] + "AA",            // 서명 변조
+		NewUnsubscribeTokens("other").Sign(42), // 다른 키
+	}
+	for i, tok := range invalid {
+		if _, err := tokens.Verify(tok); err != ErrInvalidUnsubscribeToken {
+			t.Errorf("case %d: err = %v, want ErrInvalidUnsubscribeToken", i, err)
+		}
+	}
+}
+
+func TestEmailRendererTemplates(t *testing.T) {
+	r := NewEmailRenderer("https://lotto.example.com/", NewUnsubscribeTokens("secret"))
+
+	c := &claimedEmail{
+		UserID: 7,
+		Type:   TypeWeeklyReminder,
+		Message: PushMessage{
+			Title: "1200회 로또 판매 마감 20:00",
+			Body:  "<b>내 번호</b> 구매하셨나요?",
+			Data:  map[string]string{"draw_no": "1200", "picks": "1,2,3,4,5,6;7,8,9,10,11,12"},
+		},
+	}
+	subject, body, headers, err := r.Render(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "[LottoSmash] 1200회 로또 판매 마감 20:00" {
+		t.Errorf("subject = %q", subject)
+	}
+	if !strings.Contains(body, "&lt;b&gt;내 번호") {
+		t.Error("body text must be HTML-escaped")
+	}
+	if !strings.Contains(body, "1200회 내 번호") || !strings.Contains(body, ">12</span>") {
+		t.Error("weekly digest should list saved picks")
+	}
+
+	// 수신 거부 링크와 원클릭 헤더가 같은 서명 토큰을 가리킴
+	link := strings.Trim(headers["List-Unsubscribe"], "<>")
+	u, err := url.Parse(link)
+	if err != nil || u.Host != "lotto.example.com" || u.Path != "/api/notifications/email/unsubscribe" {
+		t.Fatalf("List-Unsubscribe = %q", headers["List-Unsubscribe"])
+	}
+	if userID, err := r.tokens.Verify(u.Query().Get("token")); err != nil || userID != 7 {
+		t.Errorf("unsubscribe token user = %d, %v", userID, err)
+	}
+	if headers["List-Unsubscribe-Post"] != "List-Unsubscribe=One-Click" {
+		t.Errorf("headers = %v", headers)
+	}
+
+	c.Type = TypeWinningResult
+	c.Message.Data = map[string]string{"draw_no": "1200", "prize_rank": "3"}
+	if _, body, _, err := r.Render(c); err != nil || !strings.Contains(body, "1200회 3등") {
+		t.Errorf("winning email missing rank (err = %v)", err)
+	}
+
+	c.Type = "unknown"
+	if _, _, _, err := r.Render(c); err != nil {
+		t.Errorf("unknown type should use generic template: %v", err)
+	}
+}
+
+// emailErrSender 주소별로 지정한 오류를 반환하는 이메일 발송기
+type emailErrSender map[string]error
+
+func (s emailErrSender) SendHTMLEmail(to, _, _ string, _ map[string]string) error {
+	return s[to]
+}
+
+func TestDispatchEmailOnceOutcomes(t *testing.T) {
+	email := func(id int64, addr string, attempts int) claimedEmail {
+		return claimedEmail{ID: id, NotificationID: id, UserID: id, Email: addr, Attempts: attempts, Type: TypeDrawPublished}
+	}
+	store := newMockDeliveryStore()
+	store.dueEmails = []claimedEmail{
+		email(1, "ok@example.com", 1),
+		email(2, "busy@example.com", 1),
+		email(3, "unknown@example.com", 1),
+	}
+	sender := emailErrSender{
+		"busy@example.com":    &textproto.Error{Code: 421, Msg: "try again later"},
+		"unknown@example.com": &textproto.Error{Code: 550, Msg: "no such user"},
+	}
+	d := newTestDispatcher(t, store, tokenErrSender{}, DispatcherOptions{BatchSize: 10, RetryBaseDelay: time.Second})
+	d.SetEmailSender(sender, NewEmailRenderer("https://lotto.example.com", NewUnsubscribeTokens("secret")))
+
+	d.drain(context.Background())
+
+	if len(store.sent) != 1 || store.sent[0] != 1 {
+		t.Errorf("sent = %v, want [1]", store.sent)
+	}
+	if _, ok := store.retries[2]; !ok {
+		t.Error("temporary SMTP error should be retried")
+	}
+	if _, ok := store.dead[3]; !ok {
+		t.Error("permanent SMTP error should be dead")
+	}
+	if len(store.refreshed) != 3 {
+		t.Errorf("refreshed = %v", store.refreshed)
+	}
+}
@@ -0,0 +1,315 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultFCMBaseURL FCM HTTP v1 API 기본 주소
+	DefaultFCMBaseURL = "https://fcm.googleapis.com"
+
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	fcmTokenLifetime   = time.Hour
+	fcmTokenRefresh    = time.Minute // 만료 1분 전 갱신
+	fcmErrorType       = "type.googleapis.com/google.firebase.fcm.v1.FcmError"
+	fcmBadRequestType  = "type.googleapis.com/google.rpc.BadRequest"
+)
+
+// fcmCredentials 서비스 계정 키 파일 (Firebase 콘솔에서 발급)
+type fcmCredentials struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// FCMSender Firebase Cloud Messaging HTTP v1 발송기
+// 서비스 계정 JWT를 OAuth2 액세스 토큰으로 교환해 만료 전까지 재사용한다.
+type FCMSender struct {
+	projectID   string
+	clientEmail string
+	keyID       string
+	key         *rsa.PrivateKey
+	tokenURL    string
+	baseURL     string
+	client      *http.Client
+	now         func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSenderFromFile 서비스 계정 키 파일로 발송기 생성
+func NewFCMSenderFromFile(credentialsFile, baseURL string) (*FCMSender, error) {
+	raw, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("read fcm credentials: %w", err)
+	}
+	return NewFCMSender(raw, baseURL)
+}
+
+// NewFCMSender 서비스 계정 키 JSON으로 발송기 생성
+// baseURL을 비우면 DefaultFCMBaseURL을 사용하며, 테스트 시 로컬 스텁 서버 주소를 지정한다.
+func NewFCMSender(credentialsJSON []byte, baseURL string) (*FCMSender, error) {
+	var creds fcmCredentials
+	if err := json.Unmarshal(credentialsJSON, &creds); err != nil {
+		return nil, fmt.Errorf("parse fcm credentials: %w", err)
+	}
+	if creds.ProjectID == "" || creds.ClientEmail == "" || creds.PrivateKey == "" {
+		return nil, errors.New("fcm credentials require project_id, client_email and private_key")
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(creds.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse fcm private key: %w", err)
+	}
+
+	if baseURL == "" {
+		baseURL = DefaultFCMBaseURL
+	}
+	tokenURL := creds.TokenURI
+	if tokenURL == "" {
+		tokenURL = fcmDefaultTokenURL
+	}
+
+	return &FCMSender{
+		projectID:   creds.ProjectID,
+		clientEmail: creds.ClientEmail,
+		keyID:       creds.PrivateKeyID,
+		key:         key,
+		tokenURL:    tokenURL,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+	}, nil
+}
+
+// Send 디바이스 플랫폼별 페이로드로 메시지 발송
+func (f *FCMSender) Send(ctx context.Context, token DeviceToken, msg PushMessage) error {
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"message": fcmMessage(token, msg)})
+	if err != nil {
+		return fmt.Errorf("marshal fcm message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", f.baseURL, url.PathEscape(f.projectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	pushErr := parseFCMError(resp)
+	if resp.StatusCode == http.StatusUnauthorized {
+		// 액세스 토큰이 폐기되었을 수 있으므로 다음 발송 시 다시 발급
+		f.mu.Lock()
+		f.accessToken = ""
+		f.mu.Unlock()
+	}
+	return pushErr
+}
+
+// fcmMessage 플랫폼별 메시지 구성
+// android: 높은 우선순위 알림, ios: APNs alert 페이로드, web: Web Push 알림
+// 배지 수는 ios는 aps.badge, android는 notification_count로 전달한다.
+func fcmMessage(token DeviceToken, msg PushMessage) map[string]interface{} {
+	m := map[string]interface{}{"token": token.Token}
+	if len(msg.Data) > 0 {
+		m["data"] = msg.Data
+	}
+
+	switch token.Platform {
+	case PlatformIOS:
+		headers := map[string]string{"apns-priority": "10"}
+		if msg.CollapseID != "" {
+			headers["apns-collapse-id"] = msg.CollapseID
+		}
+		m["apns"] = map[string]interface{}{
+			"headers": headers,
+			"payload": map[string]interface{}{"aps": apsPayload(msg)},
+		}
+	case PlatformWeb:
+		headers := map[string]string{"Urgency": "high"}
+		if msg.CollapseID != "" {
+			headers["Topic"] = msg.CollapseID
+		}
+		m["webpush"] = map[string]interface{}{
+			"headers":      headers,
+			"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+		}
+	default:
+		notification := map[string]interface{}{
+			"title": msg.Title,
+			"body":  msg.Body,
+			"sound": "default",
+		}
+		if msg.Badge != nil {
+			notification["notification_count"] = *msg.Badge
+		}
+		android := map[string]interface{}{
+			"priority":     "HIGH",
+			"notification": notification,
+		}
+		if msg.CollapseID != "" {
+			android["collapse_key"] = msg.CollapseID
+		}
+		m["android"] = android
+	}
+	return m
+}
+
+// parseFCMError FCM 오류 응답을 PushError로 변환
+// details의 FcmError errorCode(UNREGISTERED 등)가 있으면 우선하고, 없으면 status(INVALID_ARGUMENT 등)를 사용한다.
+func parseFCMError(resp *http.Response) *PushError {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				Type            string `json:"@type"`
+				ErrorCode       string `json:"errorCode"`
+				FieldViolations []struct {
+					Field string `json:"field"`
+				} `json:"fieldViolations"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(raw, &payload)
+
+	pushErr := &PushError{
+		Provider:   "fcm",
+		StatusCode: resp.StatusCode,
+		Code:       payload.Error.Status,
+		Message:    payload.Error.Message,
+	}
+	badToken := strings.Contains(pushErr.Message, "not a valid FCM registration token")
+	for _, d := range payload.Error.Details {
+		if d.Type == fcmErrorType && d.ErrorCode != "" {
+			pushErr.Code = d.ErrorCode
+		}
+		if d.Type == fcmBadRequestType {
+			for _, v := range d.FieldViolations {
+				if v.Field == "message.token" {
+					badToken = true
+				}
+			}
+		}
+	}
+	if pushErr.Message == "" {
+		pushErr.Message = strings.TrimSpace(string(raw))
+	}
+
+	switch pushErr.Code {
+	case "UNREGISTERED", "NOT_FOUND":
+		pushErr.Err = ErrTokenUnregistered
+	case "INVALID_ARGUMENT":
+		// 토큰 필드 위반이면 잘못된 토큰, 그 외는 메시지 형식 오류
+		if badToken {
+			pushErr.Err = ErrInvalidToken
+		} else {
+			pushErr.Err = ErrInvalidPayload
+		}
+	case "SENDER_ID_MISMATCH":
+		// 다른 프로젝트의 토큰이거나 프로젝트 설정 오류이므로 토큰을 지우지 않음
+		pushErr.Err = ErrInvalidPayload
+	}
+	return pushErr
+}
+
+// token 캐시된 액세스 토큰 반환 (만료 임박 시 서비스 계정 JWT로 새로 발급)
+func (f *FCMSender) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	if f.accessToken != "" && now.Add(fcmTokenRefresh).Before(f.expiresAt) {
+		return f.accessToken, nil
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   f.clientEmail,
+		"scope": fcmScope,
+		"aud":   f.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(fcmTokenLifetime).Unix(),
+	}
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if f.keyID != "" {
+		assertion.Header["kid"] = f.keyID
+	}
+	signed, err := assertion.SignedString(f.key)
+	if err != nil {
+		return "", fmt.Errorf("sign fcm assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fcm token exchange: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return "", fmt.Errorf("fcm token exchange failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("decode fcm token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", errors.New("fcm token response has no access_token")
+	}
+
+	f.accessToken = tok.AccessToken
+	f.expiresAt = now.Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return f.accessToken, nil
+}
@@ -0,0 +1,193 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fcmStub 토큰 발급과 메시지 발송을 흉내 내는 로컬 FCM 서버
+type fcmStub struct {
+	*httptest.Server
+	key        *rsa.PrivateKey
+	tokenCalls atomic.Int32
+	messages   []map[string]interface{}
+	failWith   string // 비어 있지 않으면 이 응답으로 발송 실패
+	failStatus int
+}
+
+func newFCMStub(t *testing.T) *fcmStub {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := &fcmStub{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		stub.tokenCalls.Add(1)
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			http.Error(w, "bad grant", http.StatusBadRequest)
+			return
+		}
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(r.Form.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || claims["scope"] != fcmScope || claims["iss"] != "push@test.iam.gserviceaccount.com" {
+			http.Error(w, "bad assertion", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "stub-token", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v1/projects/test-project/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer stub-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if stub.failWith != "" {
+			w.WriteHeader(stub.failStatus)
+			w.Write([]byte(stub.failWith))
+			return
+		}
+		var body struct {
+			Message map[string]interface{} `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		stub.messages = append(stub.messages, body.Message)
+		w.Write([]byte(`{"name": "projects/test-project/messages/1"}`))
+	})
+	stub.Server = httptest.NewServer(mux)
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *fcmStub) sender(t *testing.T) *FCMSender {
+	t.Helper()
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(s.key)})
+	creds, _ := json.Marshal(fcmCredentials{
+		Type:        "service_account",
+		ProjectID:   "test-project",
+		PrivateKey:  string(keyPEM),
+		ClientEmail: "push@test.iam.gserviceaccount.com",
+		TokenURI:    s.URL + "/token",
+	})
+	sender, err := NewFCMSender(creds, s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sender
+}
+
+func TestFCMSenderPlatformPayloads(t *testing.T) {
+	stub := newFCMStub(t)
+	sender := stub.sender(t)
+	msg := PushMessage{Title: "당첨!", Body: "3개 일치", Data: map[string]string{"draw_no": "1200"}}
+
+	for _, platform := range []string{PlatformAndroid, PlatformIOS, PlatformWeb} {
+		if err := sender.Send(context.Background(), DeviceToken{Token: "tok-" + platform, Platform: platform}, msg); err != nil {
+			t.Fatalf("%s: %v", platform, err)
+		}
+	}
+
+	if got := stub.tokenCalls.Load(); got != 1 {
+		t.Errorf("token exchanged %d times, want 1 (cached)", got)
+	}
+	if len(stub.messages) != 3 {
+		t.Fatalf("messages = %d", len(stub.messages))
+	}
+	for i, key := range []string{"android", "apns", "webpush"} {
+		m := stub.messages[i]
+		if _, ok := m[key]; !ok {
+			t.Errorf("message %d missing %s config: %v", i, key, m)
+		}
+		if data, _ := m["data"].(map[string]interface{}); data["draw_no"] != "1200" {
+			t.Errorf("message %d data = %v", i, m["data"])
+		}
+	}
+}
+
+func TestFCMMessageBadge(t *testing.T) {
+	badge := 7
+	msg := PushMessage{Title: "당첨!", Body: "3개 일치", Badge: &badge}
+
+	ios := fcmMessage(DeviceToken{Token: "ios", Platform: PlatformIOS}, msg)
+	aps := ios["apns"].(map[string]interface{})["payload"].(map[string]interface{})["aps"].(map[string]interface{})
+	if aps["badge"] != 7 {
+		t.Errorf("aps = %v, want badge 7", aps)
+	}
+
+	android := fcmMessage(DeviceToken{Token: "android", Platform: PlatformAndroid}, msg)
+	notification := android["android"].(map[string]interface{})["notification"].(map[string]interface{})
+	if notification["notification_count"] != 7 {
+		t.Errorf("android notification = %v, want notification_count 7", notification)
+	}
+
+	// 배지 수를 모르면 기기의 배지를 건드리지 않는다
+	msg.Badge = nil
+	aps = apsPayload(msg)
+	if _, ok := aps["badge"]; ok {
+		t.Errorf("aps = %v, want no badge", aps)
+	}
+}
+
+func TestFCMSenderErrorMapping(t *testing.T) {
+	stub := newFCMStub(t)
+	sender := stub.sender(t)
+	token := DeviceToken{Token: "dead", Platform: PlatformAndroid}
+
+	tests := []struct {
+		status    int
+		body      string
+		want      error
+		retryable bool
+	}{
+		{http.StatusNotFound, `{"error": {"code": 404, "status": "NOT_FOUND", "message": "Requested entity was not found.",
+			"details": [{"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError", "errorCode": "UNREGISTERED"}]}}`, ErrTokenUnregistered, false},
+		{http.StatusBadRequest, `{"error": {"code": 400, "status": "INVALID_ARGUMENT", "message": "The registration token is not a valid FCM registration token"}}`, ErrInvalidToken, false},
+		{http.StatusBadRequest, `{"error": {"code": 400, "status": "INVALID_ARGUMENT", "message": "Invalid value",
+			"details": [{"@type": "type.googleapis.com/google.rpc.BadRequest", "fieldViolations": [{"field": "message.token", "description": "Invalid registration token"}]}]}}`, ErrInvalidToken, false},
+		{http.StatusBadRequest, `{"error": {"code": 400, "status": "INVALID_ARGUMENT", "message": "Message is too big",
+			"details": [{"@type": "type.googleapis.com/google.rpc.BadRequest", "fieldViolations": [{"field": "message", "description": "Message is too big"}]}]}}`, ErrInvalidPayload, false},
+		{http.StatusServiceUnavailable, `{"error": {"code": 503, "status": "UNAVAILABLE", "message": "try later"}}`, nil, true},
+	}
+	for _, tt := range tests {
+		stub.failStatus, stub.failWith = tt.status, tt.body
+		err := sender.Send(context.Background(), token, PushMessage{Title: "t"})
+
+		var pushErr *PushError
+		if !errors.As(err, &pushErr) {
+			t.Fatalf("status %d: err = %v, want *PushError", tt.status, err)
+		}
+		if tt.want == ErrInvalidPayload && errors.Is(err, ErrInvalidToken) {
+			t.Errorf("status %d: payload error must not be treated as a bad token: %v", tt.status, err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("status %d: err = %v, want %v", tt.status, err, tt.want)
+		}
+		if pushErr.Retryable() != tt.retryable {
+			t.Errorf("status %d: retryable = %v", tt.status, pushErr.Retryable())
+		}
+	}
+}
+
+func TestNewFCMSenderInvalidCredentials(t *testing.T) {
+	if _, err := NewFCMSender([]byte(`{"project_id": "p"}`), ""); err == nil {
+		t.Error("missing fields should fail")
+	}
+	if _, err := NewFCMSender([]byte(`{"project_id": "p", "client_email": "e", "private_key": "not a key"}`), ""); err == nil {
+		t.Error("invalid key should fail")
+	}
+}
@@ -1,11 +1,23 @@
 package notification
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
 	"net/http"
 	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/example/LottoSmash/internal/auth"
 )
 
+// maxRequeueIDs 한 번에 재시도할 수 있는 발송 작업 수
+const maxRequeueIDs = 500
+
 type Handler struct {
 	service *Service
 }
@@ -28,15 +40,12 @@ func (h *Handler) RegisterDeviceToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Token == "" {
-		h.errorResponse(w, http.StatusBadRequest, "token is required")
+	if err := validateRegisterTokenRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if req.Platform == "" {
-		req.Platform = "android"
-	}
 
-	if err := h.service.RegisterDeviceToken(r.Context(), userID, req.Token, req.Platform); err != nil {
+	if err := h.service.RegisterDeviceToken(r.Context(), userID, req.Token, req.Platform, req.Subscription, req.OldToken); err != nil {
 		h.errorResponse(w, http.StatusInternalServerError, "failed to register device token")
 		return
 	}
@@ -44,6 +53,16 @@ func (h *Handler) RegisterDeviceToken(w http.ResponseWriter, r *http.Request) {
 	h.jsonResponse(w, http.StatusOK, map[string]string{"message": "device token registered"})
 }
 
+// GetWebPushKey GET /api/notifications/webpush/public-key
+func (h *Handler) GetWebPushKey(w http.ResponseWriter, r *http.Request) {
+	key := h.service.WebPushPublicKey()
+	if key == "" {
+		h.errorResponse(w, http.StatusNotFound, "web push is not enabled")
+		return
+	}
+	h.jsonResponse(w, http.StatusOK, WebPushKeyResponse{PublicKey: key})
+}
+
 // DeleteDeviceToken DELETE /api/notifications/device-token
 func (h *Handler) DeleteDeviceToken(w http.ResponseWriter, r *http.Request) {
 	userID, ok := getUserID(r)
@@ -71,7 +90,8 @@ func (h *Handler) DeleteDeviceToken(w http.ResponseWriter, r *http.Request) {
 	h.jsonResponse(w, http.StatusOK, map[string]string{"message": "device token deleted"})
 }
 
-// GetNotifications GET /api/notifications?limit=20&offset=0
+// GetNotifications GET /api/notifications?filter=unread&limit=20&offset=0
+// filter: 생략(보관하지 않은 알림), unread, archived
 func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
 	userID, ok := getUserID(r)
 	if !ok {
@@ -79,20 +99,118 @@ func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter := r.URL.Query().Get("filter")
+	if filter != FilterInbox && filter != FilterUnread && filter != FilterArchived {
+		h.errorResponse(w, http.StatusBadRequest, "filter must be unread or archived")
+		return
+	}
 	limit, offset := parsePagination(r)
 
-	notifications, totalCount, err := h.service.GetNotifications(r.Context(), userID, limit, offset)
+	notifications, totalCount, err := h.service.GetNotifications(r.Context(), userID, filter, limit, offset)
 	if err != nil {
 		h.errorResponse(w, http.StatusInternalServerError, "failed to get notifications")
 		return
 	}
+	unread, err := h.service.CountUnread(r.Context(), userID)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to count unread notifications")
+		return
+	}
 
 	h.jsonResponse(w, http.StatusOK, NotificationListResponse{
 		Notifications: notifications,
 		TotalCount:    totalCount,
+		UnreadCount:   unread,
 	})
 }
 
+// GetUnreadCount GET /api/notifications/unread-count
+func (h *Handler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r)
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	unread, err := h.service.CountUnread(r.Context(), userID)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to count unread notifications")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, UnreadCountResponse{UnreadCount: unread})
+}
+
+// MarkRead POST /api/notifications/{id}/read
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	h.updateNotification(w, r, h.service.MarkRead)
+}
+
+// ArchiveNotification POST /api/notifications/{id}/archive
+func (h *Handler) ArchiveNotification(w http.ResponseWriter, r *http.Request) {
+	h.updateNotification(w, r, h.service.ArchiveNotification)
+}
+
+// DeleteNotification DELETE /api/notifications/{id}
+func (h *Handler) DeleteNotification(w http.ResponseWriter, r *http.Request) {
+	h.updateNotification(w, r, h.service.DeleteNotification)
+}
+
+// updateNotification 알림 한 건의 상태를 바꾸고 남은 읽지 않은 알림 수를 응답
+func (h *Handler) updateNotification(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, userID, id int64) (int, error)) {
+	userID, ok := getUserID(r)
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id < 1 {
+		h.errorResponse(w, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	unread, err := update(r.Context(), userID, id)
+	if errors.Is(err, ErrNotificationNotFound) {
+		h.errorResponse(w, http.StatusNotFound, "notification not found")
+		return
+	}
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to update notification")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, UnreadCountResponse{UnreadCount: unread})
+}
+
+// MarkAllRead POST /api/notifications/read-all
+// body는 생략 가능. before_id를 보내면 그 이하 알림만 읽음 처리한다 (목록을 불러온 뒤 도착한 알림 제외).
+func (h *Handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r)
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req MarkAllReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.BeforeID < 0 {
+		h.errorResponse(w, http.StatusBadRequest, "before_id must not be negative")
+		return
+	}
+
+	updated, unread, err := h.service.MarkAllRead(r.Context(), userID, req.BeforeID)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to mark notifications as read")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, MarkReadResponse{Updated: updated, UnreadCount: unread})
+}
+
 // GetWinnings GET /api/notifications/winnings?limit=20&offset=0
 func (h *Handler) GetWinnings(w http.ResponseWriter, r *http.Request) {
 	userID, ok := getUserID(r)
@@ -115,6 +233,135 @@ func (h *Handler) GetWinnings(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GetPreferences GET /api/notifications/preferences
+func (h *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r)
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(r.Context(), userID)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to get notification preferences")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, prefs)
+}
+
+// UpdatePreferences PUT /api/notifications/preferences
+func (h *Handler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r)
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validatePreferencesRequest(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(r.Context(), userID, &req)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to update notification preferences")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, prefs)
+}
+
+// GetDeliveries GET /api/admin/notifications/deliveries?status=dead&limit=20&offset=0
+func (h *Handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", DeliveryPending, DeliverySending, DeliverySent, DeliveryDead:
+	default:
+		h.errorResponse(w, http.StatusBadRequest, "status must be one of pending, sending, sent, dead")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	resp, err := h.service.GetDeliveries(r.Context(), status, limit, offset)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to get deliveries")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, resp)
+}
+
+// RequeueDeliveries POST /api/admin/notifications/deliveries/requeue
+func (h *Handler) RequeueDeliveries(w http.ResponseWriter, r *http.Request) {
+	var req RequeueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.IDs) == 0 || len(req.IDs) > maxRequeueIDs {
+		h.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("ids must contain 1 to %d delivery ids", maxRequeueIDs))
+		return
+	}
+
+	requeued, err := h.service.RequeueDeliveries(r.Context(), req.IDs)
+	if err != nil {
+		h.errorResponse(w, http.StatusInternalServerError, "failed to requeue deliveries")
+		return
+	}
+
+	h.jsonResponse(w, http.StatusOK, RequeueResponse{Requeued: requeued})
+}
+
+// unsubscribePage 수신 거부 확인/완료 페이지 (메일 클라이언트가 링크를 미리 열어도 해지되지 않도록 GET은 확인만)
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="ko">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>LottoSmash 이메일 수신 거부</title></head>
+<body style="font-family:'Apple SD Gothic Neo','Malgun Gothic',sans-serif;max-width:480px;margin:48px auto;padding:0 16px;color:#222;">
+<h1 style="font-size:20px;">LottoSmash 이메일 수신 거부</h1>
+{{if .Done}}<p>이메일 알림 수신이 해지되었습니다. 앱의 알림 설정에서 언제든 다시 켤 수 있습니다.</p>
+{{else if .Invalid}}<p>유효하지 않은 수신 거부 링크입니다.</p>
+{{else}}<p>LottoSmash 알림 이메일을 더 이상 받지 않으시겠습니까?</p>
+<form method="post"><input type="hidden" name="token" value="{{.Token}}"><button type="submit" style="padding:10px 20px;">수신 거부</button></form>{{end}}
+</body>
+</html>`))
+
+// EmailUnsubscribe GET/POST /api/notifications/email/unsubscribe?token=...
+// GET은 확인 페이지, POST는 수신 거부 처리 (RFC 8058 원클릭 요청 포함). 로그인 없이 서명 토큰으로 처리한다.
+func (h *Handler) EmailUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.FormValue("token")
+	view := struct {
+		Token         string
+		Done, Invalid bool
+	}{Token: token}
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		err := h.service.UnsubscribeEmail(r.Context(), token)
+		switch {
+		case errors.Is(err, ErrInvalidUnsubscribeToken):
+			status, view.Invalid = http.StatusBadRequest, true
+		case err != nil:
+			http.Error(w, "failed to unsubscribe", http.StatusInternalServerError)
+			return
+		default:
+			view.Done = true
+		}
+	} else if token == "" {
+		status, view.Invalid = http.StatusBadRequest, true
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	unsubscribePage.Execute(w, view)
+}
+
 func (h *Handler) jsonResponse(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -126,12 +373,35 @@ func (h *Handler) errorResponse(w http.ResponseWriter, status int, message strin
 }
 
 func getUserID(r *http.Request) (int64, bool) {
-	val := r.Context().Value("user_id")
-	if val == nil {
-		return 0, false
+	return auth.GetUserID(r.Context())
+}
+
+// validateRegisterTokenRequest 토큰 등록 요청 검증
+// web 플랫폼은 구독 정보가 필요하며, token은 구독 endpoint로 채운다.
+func validateRegisterTokenRequest(req *RegisterTokenRequest) error {
+	if req.Platform == "" {
+		req.Platform = PlatformAndroid
+	}
+	if !ValidPlatform(req.Platform) {
+		return errors.New("platform must be one of android, ios, web")
+	}
+
+	if req.Platform == PlatformWeb {
+		if req.Subscription == nil {
+			return errors.New("subscription is required for web platform")
+		}
+		if err := req.Subscription.Validate(); err != nil {
+			return err
+		}
+		req.Token = req.Subscription.Endpoint
+		return nil
+	}
+
+	req.Subscription = nil
+	if req.Token == "" {
+		return errors.New("token is required")
 	}
-	userID, ok := val.(int64)
-	return userID, ok
+	return nil
 }
 
 func parsePagination(r *http.Request) (int, int) {
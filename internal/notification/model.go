@@ -2,21 +2,39 @@ package notification
 
 import "time"
 
-// DeviceToken FCM 디바이스 토큰
+// DeviceToken 푸시 디바이스 토큰 (FCM/APNs 토큰, web이면 구독 endpoint)
 type DeviceToken struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	Token     string    `json:"token"`
-	Platform  string    `json:"platform"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           int64                `json:"id"`
+	UserID       int64                `json:"user_id"`
+	Token        string               `json:"token"`
+	Platform     string               `json:"platform"`
+	Subscription *WebPushSubscription `json:"subscription,omitempty"` // platform이 web일 때만
+	IsActive     bool                 `json:"is_active"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
 }
 
-// WinningCheck 추천번호 당첨 확인 결과
+// 토큰 비활성화 사유 (device_tokens.deactivated_reason)
+const (
+	TokenReasonUser         = "user"         // 사용자가 삭제
+	TokenReasonUnregistered = "unregistered" // 제공자가 등록 해제된 토큰으로 응답
+	TokenReasonInvalid      = "invalid"      // 제공자가 잘못된 토큰으로 응답
+	TokenReasonExpired      = "expired"      // 오랫동안 다시 등록되지 않음
+	TokenReasonReplaced     = "replaced"     // 토큰 갱신으로 새 값으로 대체
+	TokenReasonReassigned   = "reassigned"   // 같은 토큰이 다른 사용자로 등록됨
+)
+
+// TokenRegistration 토큰 등록 시 정리된 기존 토큰 수
+type TokenRegistration struct {
+	Replaced   int64 // 갱신 전 토큰 (old_token)
+	Reassigned int64 // 다른 사용자에게 등록되어 있던 같은 토큰
+}
+
+// WinningCheck 추천번호/구매 용지 게임 당첨 확인 결과
 type WinningCheck struct {
 	ID               int64     `json:"id"`
-	RecommendationID int64     `json:"recommendation_id"`
+	RecommendationID *int64    `json:"recommendation_id,omitempty"`
+	TicketGameID     *int64    `json:"ticket_game_id,omitempty"`
 	UserID           *int64    `json:"user_id,omitempty"`
 	DrawNo           int       `json:"draw_no"`
 	MatchedNumbers   []int     `json:"matched_numbers"`
@@ -26,6 +44,29 @@ type WinningCheck struct {
 	CreatedAt        time.Time `json:"created_at"`
 }
 
+// 알림 상태 (push_notifications.status, 토큰별 발송 결과를 집계)
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationPartial = "partial" // 일부 토큰만 발송 성공
+	NotificationFailed  = "failed"
+)
+
+// 토큰별 발송 상태 (push_deliveries.status)
+const (
+	DeliveryPending = "pending" // 발송 또는 재시도 대기
+	DeliverySending = "sending" // 디스패처가 점유 중 (next_attempt_at까지)
+	DeliverySent    = "sent"
+	DeliveryDead    = "dead" // 최대 시도 초과 또는 영구 오류
+)
+
+// 알림 목록 필터 (GET /api/notifications?filter=)
+const (
+	FilterInbox    = ""         // 보관하지 않은 알림 (기본)
+	FilterUnread   = "unread"   // 보관하지 않은 읽지 않은 알림
+	FilterArchived = "archived" // 보관한 알림
+)
+
 // PushNotification 푸시 알림 기록
 type PushNotification struct {
 	ID           int64      `json:"id"`
@@ -34,16 +75,71 @@ type PushNotification struct {
 	Title        string     `json:"title"`
 	Body         string     `json:"body"`
 	Data         *string    `json:"data,omitempty"`
+	CollapseID   string     `json:"-"`
+	ThreadID     string     `json:"-"`
 	Status       string     `json:"status"`
 	ErrorMessage *string    `json:"error_message,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
 	SentAt       *time.Time `json:"sent_at,omitempty"`
+	DeliverAfter *time.Time `json:"deliver_after,omitempty"` // 방해 금지 시간으로 미뤄진 경우
+	ReadAt       *time.Time `json:"read_at,omitempty"`       // NULL이면 읽지 않음
+	ArchivedAt   *time.Time `json:"archived_at,omitempty"`
+
+	deferBy   time.Duration // 저장 시 deliver_after 계산용
+	expiresAt time.Time     // 이 시각 이후로 미뤄져야 하면 보내지 않음 (판매 마감 리마인더 등)
+}
+
+// PushDelivery 토큰별 발송 기록 (관리자 조회용)
+type PushDelivery struct {
+	ID             int64      `json:"id"`
+	NotificationID int64      `json:"notification_id"`
+	DeviceTokenID  int64      `json:"device_token_id"`
+	UserID         *int64     `json:"user_id,omitempty"`
+	Type           string     `json:"type"`
+	Title          string     `json:"title"`
+	Platform       string     `json:"platform"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastError      *string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+}
+
+// claimedDelivery 디스패처가 점유한 발송 작업
+type claimedDelivery struct {
+	ID             int64
+	NotificationID int64
+	Attempts       int // 이번 시도 포함
+	Token          DeviceToken
+	Message        PushMessage
+}
+
+// claimedEmail 디스패처가 점유한 이메일 발송 작업
+type claimedEmail struct {
+	ID             int64
+	NotificationID int64
+	UserID         int64
+	Email          string
+	Attempts       int // 이번 시도 포함
+	Type           string
+	Message        PushMessage
 }
 
 // RegisterTokenRequest 디바이스 토큰 등록 요청
+// platform이 web이면 token 대신 브라우저 PushSubscription JSON을 subscription으로 보낸다.
+// 토큰이 갱신된 경우 이전 값을 old_token으로 보내면 기존 토큰을 대체한다.
 type RegisterTokenRequest struct {
-	Token    string `json:"token"`
-	Platform string `json:"platform"`
+	Token        string               `json:"token"`
+	Platform     string               `json:"platform"`
+	Subscription *WebPushSubscription `json:"subscription,omitempty"`
+	OldToken     string               `json:"old_token,omitempty"`
+}
+
+// WebPushKeyResponse 브라우저 구독용 VAPID 공개키 응답
+type WebPushKeyResponse struct {
+	PublicKey string `json:"public_key"`
 }
 
 // DeleteTokenRequest 디바이스 토큰 삭제 요청
@@ -55,6 +151,40 @@ type DeleteTokenRequest struct {
 type NotificationListResponse struct {
 	Notifications []PushNotification `json:"notifications"`
 	TotalCount    int                `json:"total_count"`
+	UnreadCount   int                `json:"unread_count"`
+}
+
+// UnreadCountResponse 읽지 않은 알림 수 응답 (앱 배지)
+type UnreadCountResponse struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+// MarkAllReadRequest 모두 읽음 처리 요청 (before_id 생략 시 전체)
+type MarkAllReadRequest struct {
+	BeforeID int64 `json:"before_id,omitempty"`
+}
+
+// MarkReadResponse 모두 읽음 처리 응답
+type MarkReadResponse struct {
+	Updated     int `json:"updated"`
+	UnreadCount int `json:"unread_count"`
+}
+
+// DeliveryListResponse 관리자 발송 기록 목록 응답
+type DeliveryListResponse struct {
+	Deliveries   []PushDelivery `json:"deliveries"`
+	TotalCount   int            `json:"total_count"`
+	StatusCounts map[string]int `json:"status_counts"`
+}
+
+// RequeueRequest 발송 실패(dead) 재시도 요청
+type RequeueRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// RequeueResponse 재시도 대기열에 다시 넣은 건수
+type RequeueResponse struct {
+	Requeued int `json:"requeued"`
 }
 
 // WinningListResponse 당첨 결과 목록 응답
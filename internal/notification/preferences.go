@@ -0,0 +1,221 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 알림 유형 (push_notifications.type)
+const (
+	TypeWinningResult  = "winning_result"
+	TypeDrawPublished  = "draw_published"
+	TypeWeeklyReminder = "weekly_reminder"
+	TypeMarketing      = "marketing"
+)
+
+// 알림 채널
+const (
+	ChannelPush  = "push"
+	ChannelEmail = "email"
+)
+
+const (
+	DefaultTimezone = "Asia/Seoul"
+
+	// 광고성 정보는 야간(21:00~08:00, 수신자 시간대)에 보내지 않는다 (정보통신망법 제50조).
+	marketingNightStart = 21 * 60
+	marketingNightEnd   = 8 * 60
+)
+
+// NotificationPreferences 사용자별 알림 설정
+type NotificationPreferences struct {
+	UserID               int64      `json:"-"`
+	WinningResult        bool       `json:"winning_result"`
+	DrawPublished        bool       `json:"draw_published"`
+	WeeklyReminder       bool       `json:"weekly_reminder"`
+	Marketing            bool       `json:"marketing"`
+	MarketingConsentedAt *time.Time `json:"marketing_consented_at,omitempty"`
+	PushEnabled          bool       `json:"push_enabled"`
+	EmailEnabled         bool       `json:"email_enabled"`
+	EmailFallback        bool       `json:"email_fallback"` // 활성 디바이스 토큰이 없으면 이메일로 대신 수신
+	QuietHoursEnabled    bool       `json:"quiet_hours_enabled"`
+	QuietStart           string     `json:"quiet_start"` // HH:MM
+	QuietEnd             string     `json:"quiet_end"`   // HH:MM
+	Timezone             string     `json:"timezone"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+}
+
+// DefaultPreferences 설정을 저장한 적 없는 사용자의 기본값 (광고성 정보는 미동의)
+func DefaultPreferences(userID int64) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:         userID,
+		WinningResult:  true,
+		DrawPublished:  true,
+		WeeklyReminder: true,
+		PushEnabled:    true,
+		EmailFallback:  true,
+		QuietStart:     "22:00",
+		QuietEnd:       "08:00",
+		Timezone:       DefaultTimezone,
+	}
+}
+
+// Allows 해당 유형의 알림 수신 여부
+func (p *NotificationPreferences) Allows(notifType string) bool {
+	switch notifType {
+	case TypeWinningResult:
+		return p.WinningResult
+	case TypeDrawPublished:
+		return p.DrawPublished
+	case TypeWeeklyReminder:
+		return p.WeeklyReminder
+	case TypeMarketing:
+		return p.Marketing && p.MarketingConsentedAt != nil
+	default:
+		return true
+	}
+}
+
+// Channels 수신할 채널 목록
+func (p *NotificationPreferences) Channels() []string {
+	var channels []string
+	if p.PushEnabled {
+		channels = append(channels, ChannelPush)
+	}
+	if p.EmailEnabled {
+		channels = append(channels, ChannelEmail)
+	}
+	return channels
+}
+
+// DeferUntil now에 생성된 알림을 미뤄야 하면 발송 가능 시각을, 아니면 zero time을 반환
+// 사용자가 설정한 방해 금지 시간과, 광고성 알림의 야간 제한을 함께 적용한다.
+func (p *NotificationPreferences) DeferUntil(notifType string, now time.Time) time.Time {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(DefaultTimezone)
+	}
+	local := now.In(loc)
+
+	var until time.Time
+	if p.QuietHoursEnabled {
+		start, errStart := parseClock(p.QuietStart)
+		end, errEnd := parseClock(p.QuietEnd)
+		if errStart == nil && errEnd == nil {
+			until = windowEnd(local, start, end)
+		}
+	}
+	if notifType == TypeMarketing {
+		if night := windowEnd(local, marketingNightStart, marketingNightEnd); night.After(until) {
+			until = night
+		}
+	}
+	return until
+}
+
+// windowEnd local이 [start, end) 구간(하루 중 분, 자정을 넘을 수 있음) 안이면 구간이 끝나는 시각
+func windowEnd(local time.Time, start, end int) time.Time {
+	if start == end {
+		return time.Time{}
+	}
+	minute := local.Hour()*60 + local.Minute()
+	y, m, d := local.Date()
+	endToday := time.Date(y, m, d, end/60, end%60, 0, 0, local.Location())
+
+	if start < end {
+		if minute >= start && minute < end {
+			return endToday
+		}
+		return time.Time{}
+	}
+	// 자정을 넘는 구간 (예: 22:00~08:00)
+	if minute >= start {
+		return time.Date(y, m, d+1, end/60, end%60, 0, 0, local.Location())
+	}
+	if minute < end {
+		return endToday
+	}
+	return time.Time{}
+}
+
+// parseClock "HH:MM"을 하루 중 분으로 변환
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// UpdatePreferencesRequest 알림 설정 변경 요청 (보낸 항목만 변경)
+type UpdatePreferencesRequest struct {
+	WinningResult     *bool   `json:"winning_result"`
+	DrawPublished     *bool   `json:"draw_published"`
+	WeeklyReminder    *bool   `json:"weekly_reminder"`
+	Marketing         *bool   `json:"marketing"`
+	PushEnabled       *bool   `json:"push_enabled"`
+	EmailEnabled      *bool   `json:"email_enabled"`
+	EmailFallback     *bool   `json:"email_fallback"`
+	QuietHoursEnabled *bool   `json:"quiet_hours_enabled"`
+	QuietStart        *string `json:"quiet_start"`
+	QuietEnd          *string `json:"quiet_end"`
+	Timezone          *string `json:"timezone"`
+}
+
+// validatePreferencesRequest 시각 형식과 시간대 검증
+func validatePreferencesRequest(req *UpdatePreferencesRequest) error {
+	if req.QuietStart != nil {
+		if _, err := parseClock(*req.QuietStart); err != nil {
+			return fmt.Errorf("quiet_start: %w", err)
+		}
+	}
+	if req.QuietEnd != nil {
+		if _, err := parseClock(*req.QuietEnd); err != nil {
+			return fmt.Errorf("quiet_end: %w", err)
+		}
+	}
+	if req.Timezone != nil {
+		if *req.Timezone == "" || *req.Timezone == "Local" {
+			return errors.New("timezone must be an IANA name such as Asia/Seoul")
+		}
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", *req.Timezone)
+		}
+	}
+	return nil
+}
+
+// apply 변경 요청 반영. 광고성 정보 수신을 새로 켜면 동의 일시를 기록하고, 끄면 지운다.
+func (req *UpdatePreferencesRequest) apply(p *NotificationPreferences, now time.Time) {
+	setBool := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setBool(&p.WinningResult, req.WinningResult)
+	setBool(&p.DrawPublished, req.DrawPublished)
+	setBool(&p.WeeklyReminder, req.WeeklyReminder)
+	setBool(&p.PushEnabled, req.PushEnabled)
+	setBool(&p.EmailEnabled, req.EmailEnabled)
+	setBool(&p.EmailFallback, req.EmailFallback)
+	setBool(&p.QuietHoursEnabled, req.QuietHoursEnabled)
+
+	if req.Marketing != nil {
+		if *req.Marketing && !p.Marketing {
+			p.MarketingConsentedAt = &now
+		} else if !*req.Marketing {
+			p.MarketingConsentedAt = nil
+		}
+		p.Marketing = *req.Marketing
+	}
+	if req.QuietStart != nil {
+		p.QuietStart = *req.QuietStart
+	}
+	if req.QuietEnd != nil {
+		p.QuietEnd = *req.QuietEnd
+	}
+	if req.Timezone != nil {
+		p.Timezone = *req.Timezone
+	}
+}
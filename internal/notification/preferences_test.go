@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func seoulTime(t *testing.T, hour, minute int) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	return time.Date(2026, 3, 14, hour, minute, 0, 0, loc)
+}
+
+func TestDeferUntilQuietHours(t *testing.T) {
+	prefs := DefaultPreferences(1)
+	prefs.QuietHoursEnabled = true // 22:00 ~ 08:00
+
+	tests := []struct {
+		name       string
+		hour, min  int
+		wantDefer  bool
+		wantDay    int
+		wantHour   int
+		wantMinute int
+	}{
+		{"before quiet", 21, 59, false, 0, 0, 0},
+		{"start of quiet", 22, 0, true, 15, 8, 0},
+		{"after midnight", 2, 30, true, 14, 8, 0},
+		{"end of quiet", 8, 0, false, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until := prefs.DeferUntil(TypeWinningResult, seoulTime(t, tt.hour, tt.min))
+			if until.IsZero() != !tt.wantDefer {
+				t.Fatalf("deferred = %v, want %v", !until.IsZero(), tt.wantDefer)
+			}
+			if tt.wantDefer && (until.Day() != tt.wantDay || until.Hour() != tt.wantHour || until.Minute() != tt.wantMinute) {
+				t.Errorf("until = %s", until)
+			}
+		})
+	}
+
+	// 같은 날 안의 구간
+	prefs.QuietStart, prefs.QuietEnd = "13:00", "14:30"
+	if until := prefs.DeferUntil(TypeWinningResult, seoulTime(t, 13, 10)); until.Hour() != 14 || until.Minute() != 30 {
+		t.Errorf("daytime window until = %s", until)
+	}
+
+	// 설정을 끄면 미루지 않음
+	prefs.QuietHoursEnabled = false
+	if until := prefs.DeferUntil(TypeWinningResult, seoulTime(t, 13, 10)); !until.IsZero() {
+		t.Errorf("disabled quiet hours deferred until %s", until)
+	}
+}
+
+func TestDeferUntilUserTimezone(t *testing.T) {
+	prefs := DefaultPreferences(1)
+	prefs.QuietHoursEnabled = true
+	prefs.Timezone = "America/New_York"
+
+	// 서울 12:00 = 뉴욕 23:00 (3월 14일, EDT) → 뉴욕 08:00까지
+	until := prefs.DeferUntil(TypeWinningResult, seoulTime(t, 12, 0))
+	ny, _ := time.LoadLocation("America/New_York")
+	if local := until.In(ny); until.IsZero() || local.Hour() != 8 || local.Day() != 14 {
+		t.Errorf("until = %s", until.In(ny))
+	}
+}
+
+func TestMarketingConsentAndNightRestriction(t *testing.T) {
+	prefs := DefaultPreferences(1)
+	if prefs.Allows(TypeMarketing) {
+		t.Fatal("marketing must be opt-in")
+	}
+
+	now := seoulTime(t, 21, 30)
+	on := true
+	req := &UpdatePreferencesRequest{Marketing: &on}
+	req.apply(prefs, now)
+	if !prefs.Allows(TypeMarketing) || prefs.MarketingConsentedAt == nil {
+		t.Fatalf("marketing opt-in not recorded: %+v", prefs)
+	}
+
+	// 방해 금지 시간을 꺼도 광고성 알림은 야간(21:00~08:00)에 보내지 않음
+	until := prefs.DeferUntil(TypeMarketing, now)
+	if until.IsZero() || until.Hour() != 8 || until.Day() != 15 {
+		t.Errorf("marketing until = %s", until)
+	}
+	if until := prefs.DeferUntil(TypeWinningResult, now); !until.IsZero() {
+		t.Errorf("winning result deferred until %s", until)
+	}
+
+	off := false
+	(&UpdatePreferencesRequest{Marketing: &off}).apply(prefs, now)
+	if prefs.Allows(TypeMarketing) || prefs.MarketingConsentedAt != nil {
+		t.Errorf("marketing opt-out not applied: %+v", prefs)
+	}
+}
+
+func TestValidatePreferencesRequest(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	valid := &UpdatePreferencesRequest{QuietStart: str("23:30"), QuietEnd: str("07:00"), Timezone: str("Asia/Tokyo")}
+	if err := validatePreferencesRequest(valid); err != nil {
+		t.Errorf("valid request: %v", err)
+	}
+
+	invalid := []*UpdatePreferencesRequest{
+		{QuietStart: str("25:00")},
+		{QuietEnd: str("7pm")},
+		{Timezone: str("Mars/Olympus")},
+		{Timezone: str("")},
+	}
+	for i, req := range invalid {
+		if err := validatePreferencesRequest(req); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -2,13 +2,95 @@ package notification
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/example/LottoSmash/internal/logger"
 )
 
+// 디바이스 플랫폼 (device_tokens.platform)
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+	PlatformWeb     = "web"
+)
+
+// ValidPlatform 지원하는 플랫폼인지 확인
+func ValidPlatform(platform string) bool {
+	return platform == PlatformAndroid || platform == PlatformIOS || platform == PlatformWeb
+}
+
+// 발송 실패 원인 (PushError가 감싸며 errors.Is로 확인)
+// 토큰 자체가 원인인 오류(ErrTokenUnregistered, ErrInvalidToken)만 토큰을 비활성화한다.
+// ErrInvalidPayload는 메시지 문제일 수 있으므로 해당 발송만 dead 처리하고 토큰은 그대로 둔다.
+var (
+	ErrTokenUnregistered = errors.New("device token is no longer registered")
+	ErrInvalidToken      = errors.New("invalid push token")
+	ErrInvalidPayload    = errors.New("push message rejected")
+)
+
+// PushMessage 발송할 알림 내용
+type PushMessage struct {
+	Title      string
+	Body       string
+	Data       map[string]string
+	CollapseID string // 같은 값의 이전 알림을 대체 (회차별로 지정)
+	ThreadID   string // iOS 알림 센터 그룹 (회차별로 지정)
+	Badge      *int   // 앱 아이콘 배지 (읽지 않은 알림 수, nil이면 변경하지 않음)
+}
+
 // PushSender push 알림 발송 인터페이스
+// 디바이스 플랫폼에 맞는 페이로드로 발송하며, 실패 시 가능하면 *PushError를 반환한다.
 type PushSender interface {
-	Send(ctx context.Context, token string, title string, body string, data map[string]string) error
+	Send(ctx context.Context, token DeviceToken, msg PushMessage) error
+}
+
+// PushError 푸시 제공자가 거부한 발송 오류
+type PushError struct {
+	Provider   string // fcm, apns
+	StatusCode int    // HTTP 상태 코드
+	Code       string // 제공자 오류 코드 (예: UNREGISTERED, INVALID_ARGUMENT)
+	Message    string
+	Err        error // ErrTokenUnregistered / ErrInvalidToken / ErrInvalidPayload (해당 없으면 nil)
+}
+
+func (e *PushError) Error() string {
+	return fmt.Sprintf("%s push failed (%d %s): %s", e.Provider, e.StatusCode, e.Code, e.Message)
+}
+
+func (e *PushError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable 일시적인 오류라 다시 시도할 만한지 (한도 초과, 서버 오류)
+func (e *PushError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// PlatformRouter 디바이스 플랫폼별로 발송기를 선택하는 PushSender
+// 플랫폼 전용 발송기가 없으면 기본 발송기(FCM 등)로 보낸다.
+type PlatformRouter struct {
+	fallback PushSender
+	senders  map[string]PushSender
+}
+
+func NewPlatformRouter(fallback PushSender) *PlatformRouter {
+	return &PlatformRouter{fallback: fallback, senders: make(map[string]PushSender)}
+}
+
+// Route 플랫폼 전용 발송기 지정 (예: ios → APNs)
+func (r *PlatformRouter) Route(platform string, sender PushSender) {
+	r.senders[platform] = sender
+}
+
+func (r *PlatformRouter) Send(ctx context.Context, token DeviceToken, msg PushMessage) error {
+	if sender, ok := r.senders[token.Platform]; ok {
+		return sender.Send(ctx, token, msg)
+	}
+	if r.fallback == nil {
+		return fmt.Errorf("no push sender for platform %q", token.Platform)
+	}
+	return r.fallback.Send(ctx, token, msg)
 }
 
 // NoopPushSender FCM 미설정 시 사용하는 더미 구현체 (로그만 출력)
@@ -20,7 +102,7 @@ func NewNoopPushSender(log *logger.Logger) *NoopPushSender {
 	return &NoopPushSender{log: log}
 }
 
-func (n *NoopPushSender) Send(ctx context.Context, token string, title string, body string, data map[string]string) error {
-	n.log.Infof("[NoopPush] token=%s title=%q body=%q data=%v", token, title, body, data)
+func (n *NoopPushSender) Send(ctx context.Context, token DeviceToken, msg PushMessage) error {
+	n.log.Infof("[NoopPush] platform=%s token=%s title=%q body=%q data=%v", token.Platform, token.Token, msg.Title, msg.Body, msg.Data)
 	return nil
 }
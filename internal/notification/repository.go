@@ -3,11 +3,16 @@ package notification
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/lib/pq"
 )
 
+var ErrNotificationNotFound = errors.New("notification not found")
+
 type Repository struct {
 	db *sql.DB
 }
@@ -21,31 +26,124 @@ func NewRepository(db *sql.DB) *Repository {
 // ========================================
 
 // UpsertDeviceToken 디바이스 토큰 등록 또는 갱신
-func (r *Repository) UpsertDeviceToken(ctx context.Context, userID int64, token, platform string) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO device_tokens (user_id, token, platform, is_active, updated_at)
-		VALUES ($1, $2, $3, true, NOW())
-		ON CONFLICT (user_id, token)
-		DO UPDATE SET platform = $3, is_active = true, updated_at = NOW()`,
-		userID, token, platform,
+// Web Push 구독이면 subscription에 PushSubscription JSON을 함께 저장한다.
+// oldToken이 있으면(토큰 갱신) 기존 행의 값을 새 토큰으로 바꾸고, 같은 토큰이 다른 사용자에게
+// 활성 상태로 남아 있으면 기기 소유자가 바뀐 것이므로 비활성화한다.
+func (r *Repository) UpsertDeviceToken(ctx context.Context, userID int64, token, platform string, subscription *WebPushSubscription, oldToken string) (TokenRegistration, error) {
+	var result TokenRegistration
+	var subJSON []byte
+	if subscription != nil {
+		var err error
+		if subJSON, err = json.Marshal(subscription); err != nil {
+			return result, err
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE device_tokens SET is_active = false, deactivated_reason = $3, updated_at = NOW()
+		WHERE token = $1 AND user_id <> $2 AND is_active = true`,
+		token, userID, TokenReasonReassigned,
 	)
-	return err
+	if err != nil {
+		return result, err
+	}
+	result.Reassigned, _ = res.RowsAffected()
+
+	replacedInPlace := false
+	if oldToken != "" && oldToken != token {
+		// 새 토큰 행이 아직 없으면 기존 행을 그대로 갱신 (대기 중인 발송 작업도 새 토큰으로 발송됨)
+		res, err := tx.ExecContext(ctx, `
+			UPDATE device_tokens
+			SET token = $3, platform = $4, subscription = $5, is_active = true, deactivated_reason = NULL,
+			    last_seen_at = NOW(), updated_at = NOW()
+			WHERE user_id = $1 AND token = $2
+			  AND NOT EXISTS (SELECT 1 FROM device_tokens WHERE user_id = $1 AND token = $3)`,
+			userID, oldToken, token, platform, subJSON,
+		)
+		if err != nil {
+			return result, err
+		}
+		n, _ := res.RowsAffected()
+		replacedInPlace = n > 0
+		result.Replaced = n
+
+		if !replacedInPlace {
+			res, err := tx.ExecContext(ctx, `
+				UPDATE device_tokens SET is_active = false, deactivated_reason = $3, updated_at = NOW()
+				WHERE user_id = $1 AND token = $2 AND is_active = true`,
+				userID, oldToken, TokenReasonReplaced,
+			)
+			if err != nil {
+				return result, err
+			}
+			result.Replaced, _ = res.RowsAffected()
+		}
+	}
+
+	if !replacedInPlace {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO device_tokens (user_id, token, platform, subscription, is_active, last_seen_at, updated_at)
+			VALUES ($1, $2, $3, $4, true, NOW(), NOW())
+			ON CONFLICT (user_id, token)
+			DO UPDATE SET platform = $3, subscription = $4, is_active = true, deactivated_reason = NULL,
+			              last_seen_at = NOW(), updated_at = NOW()`,
+			userID, token, platform, subJSON,
+		)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, tx.Commit()
 }
 
-// DeactivateDeviceToken 디바이스 토큰 비활성화
+// DeactivateDeviceToken 디바이스 토큰 비활성화 (사용자 요청)
 func (r *Repository) DeactivateDeviceToken(ctx context.Context, userID int64, token string) error {
 	_, err := r.db.ExecContext(ctx, `
-		UPDATE device_tokens SET is_active = false, updated_at = NOW()
+		UPDATE device_tokens SET is_active = false, deactivated_reason = $3, updated_at = NOW()
 		WHERE user_id = $1 AND token = $2`,
-		userID, token,
+		userID, token, TokenReasonUser,
 	)
 	return err
 }
 
+// DeactivateTokenByID 제공자가 거부한 토큰 비활성화 (이미 비활성이면 false)
+func (r *Repository) DeactivateTokenByID(ctx context.Context, id int64, reason string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE device_tokens SET is_active = false, deactivated_reason = $2, updated_at = NOW()
+		WHERE id = $1 AND is_active = true`,
+		id, reason,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ExpireStaleTokens 마지막 등록 후 days일 이상 지난 활성 토큰 비활성화
+func (r *Repository) ExpireStaleTokens(ctx context.Context, days int) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE device_tokens SET is_active = false, deactivated_reason = $2, updated_at = NOW()
+		WHERE is_active = true AND last_seen_at < NOW() - make_interval(days => $1)`,
+		days, TokenReasonExpired,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // GetActiveTokensByUserID 사용자의 활성 디바이스 토큰 조회
 func (r *Repository) GetActiveTokensByUserID(ctx context.Context, userID int64) ([]DeviceToken, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, token, platform, is_active, created_at, updated_at
+		SELECT id, user_id, token, platform, subscription, is_active, created_at, updated_at
 		FROM device_tokens
 		WHERE user_id = $1 AND is_active = true`,
 		userID,
@@ -58,33 +156,244 @@ func (r *Repository) GetActiveTokensByUserID(ctx context.Context, userID int64)
 	var tokens []DeviceToken
 	for rows.Next() {
 		var t DeviceToken
-		if err := rows.Scan(&t.ID, &t.UserID, &t.Token, &t.Platform, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		var subJSON []byte
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Token, &t.Platform, &subJSON, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
 			return nil, err
 		}
+		if subJSON != nil {
+			t.Subscription = &WebPushSubscription{}
+			if err := json.Unmarshal(subJSON, t.Subscription); err != nil {
+				return nil, fmt.Errorf("decode subscription of token %d: %w", t.ID, err)
+			}
+		}
 		tokens = append(tokens, t)
 	}
 	return tokens, rows.Err()
 }
 
+// ========================================
+// NotificationPreferences
+// ========================================
+
+// GetPreferences 사용자 알림 설정 조회 (저장한 적 없으면 sql.ErrNoRows)
+func (r *Repository) GetPreferences(ctx context.Context, userID int64) (*NotificationPreferences, error) {
+	p := &NotificationPreferences{UserID: userID}
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		SELECT winning_result, draw_published, weekly_reminder, marketing, marketing_consented_at,
+		       push_enabled, email_enabled, email_fallback, quiet_hours_enabled, quiet_start, quiet_end, timezone, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1`,
+		userID,
+	).Scan(
+		&p.WinningResult, &p.DrawPublished, &p.WeeklyReminder, &p.Marketing, &p.MarketingConsentedAt,
+		&p.PushEnabled, &p.EmailEnabled, &p.EmailFallback, &p.QuietHoursEnabled, &p.QuietStart, &p.QuietEnd, &p.Timezone, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.UpdatedAt = &updatedAt
+	return p, nil
+}
+
+// SavePreferences 사용자 알림 설정 저장
+func (r *Repository) SavePreferences(ctx context.Context, p *NotificationPreferences) error {
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO notification_preferences (
+			user_id, winning_result, draw_published, weekly_reminder, marketing, marketing_consented_at,
+			push_enabled, email_enabled, email_fallback, quiet_hours_enabled, quiet_start, quiet_end, timezone, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			winning_result = $2, draw_published = $3, weekly_reminder = $4, marketing = $5,
+			marketing_consented_at = $6, push_enabled = $7, email_enabled = $8, email_fallback = $9,
+			quiet_hours_enabled = $10, quiet_start = $11, quiet_end = $12, timezone = $13, updated_at = NOW()
+		RETURNING updated_at`,
+		p.UserID, p.WinningResult, p.DrawPublished, p.WeeklyReminder, p.Marketing, p.MarketingConsentedAt,
+		p.PushEnabled, p.EmailEnabled, p.EmailFallback, p.QuietHoursEnabled, p.QuietStart, p.QuietEnd, p.Timezone,
+	).Scan(&updatedAt)
+	if err != nil {
+		return err
+	}
+	p.UpdatedAt = &updatedAt
+	return nil
+}
+
+// GetPreferencesByUserIDs 여러 사용자의 알림 설정 조회 (저장한 적 없는 사용자는 기본값)
+func (r *Repository) GetPreferencesByUserIDs(ctx context.Context, userIDs []int64) (map[int64]*NotificationPreferences, error) {
+	prefs := make(map[int64]*NotificationPreferences, len(userIDs))
+	for _, id := range userIDs {
+		prefs[id] = DefaultPreferences(id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, winning_result, draw_published, weekly_reminder, marketing, marketing_consented_at,
+		       push_enabled, email_enabled, email_fallback, quiet_hours_enabled, quiet_start, quiet_end, timezone, updated_at
+		FROM notification_preferences
+		WHERE user_id = ANY($1)`,
+		pq.Array(userIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := &NotificationPreferences{}
+		var updatedAt time.Time
+		if err := rows.Scan(
+			&p.UserID, &p.WinningResult, &p.DrawPublished, &p.WeeklyReminder, &p.Marketing, &p.MarketingConsentedAt,
+			&p.PushEnabled, &p.EmailEnabled, &p.EmailFallback, &p.QuietHoursEnabled, &p.QuietStart, &p.QuietEnd, &p.Timezone, &updatedAt,
+		); err != nil {
+			return nil, err
+		}
+		p.UpdatedAt = &updatedAt
+		prefs[p.UserID] = p
+	}
+	return prefs, rows.Err()
+}
+
+// ========================================
+// Broadcast (회차별 전체 발송)
+// ========================================
+
+// ClaimBroadcast 회차별 전체 발송 선점 (이미 완료했거나 다른 서버가 진행 중이면 false)
+// 완료되지 않은 발송은 선점이 해제되었거나 lease 동안 진행 기록이 없으면 다시 선점하고,
+// 이어서 보낼 사용자 ID 커서(last_user_id)를 반환한다.
+func (r *Repository) ClaimBroadcast(ctx context.Context, notifType string, drawNo int, lease time.Duration) (int64, bool, error) {
+	var cursor int64
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO notification_broadcasts (type, draw_no, claimed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (type, draw_no) DO UPDATE SET claimed_at = NOW()
+		WHERE notification_broadcasts.completed_at IS NULL
+		  AND (notification_broadcasts.claimed_at IS NULL
+		       OR notification_broadcasts.claimed_at < NOW() - $3 * INTERVAL '1 second')
+		RETURNING last_user_id`,
+		notifType, drawNo, int(lease.Seconds()),
+	).Scan(&cursor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return cursor, err == nil, err
+}
+
+// AdvanceBroadcast 앞에서부터 처리 완료한 배치까지 커서 이동, 알림을 생성한 사용자 수 누적 (선점 갱신)
+func (r *Repository) AdvanceBroadcast(ctx context.Context, notifType string, drawNo int, lastUserID int64, recipients int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notification_broadcasts
+		SET last_user_id = GREATEST(last_user_id, $3), recipients = recipients + $4, claimed_at = NOW()
+		WHERE type = $1 AND draw_no = $2`,
+		notifType, drawNo, lastUserID, recipients,
+	)
+	return err
+}
+
+// CompleteBroadcast 발송 대상 사용자를 끝까지 처리했음을 기록
+func (r *Repository) CompleteBroadcast(ctx context.Context, notifType string, drawNo int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notification_broadcasts SET completed_at = NOW()
+		WHERE type = $1 AND draw_no = $2`,
+		notifType, drawNo,
+	)
+	return err
+}
+
+// ReleaseBroadcast 중단된 발송의 선점 해제 (다음 선점 시 커서부터 재개)
+func (r *Repository) ReleaseBroadcast(ctx context.Context, notifType string, drawNo int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notification_broadcasts SET claimed_at = NULL
+		WHERE type = $1 AND draw_no = $2 AND completed_at IS NULL`,
+		notifType, drawNo,
+	)
+	return err
+}
+
+// GetBroadcastRecipients 전체 발송 대상 사용자 ID를 afterUserID 다음부터 limit명 조회 (ID 순)
+// 활성 디바이스 토큰이 있는 사용자와, withEmail이면 이메일이 있는 사용자도 포함한다.
+func (r *Repository) GetBroadcastRecipients(ctx context.Context, afterUserID int64, limit int, withEmail bool) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id FROM users u
+		WHERE u.id > $1
+		  AND (EXISTS (SELECT 1 FROM device_tokens t WHERE t.user_id = u.id AND t.is_active = true)
+		       OR ($3 AND u.email IS NOT NULL AND u.email <> ''))
+		ORDER BY u.id
+		LIMIT $2`,
+		afterUserID, limit, withEmail,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetPicksByDrawNo 사용자별로 drawNo 회차 대상으로 받은 추천번호 조회 (추천 순)
+func (r *Repository) GetPicksByDrawNo(ctx context.Context, userIDs []int64, drawNo int) (map[int64][][]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, numbers FROM lotto_recommendations
+		WHERE user_id = ANY($1) AND draw_no = $2
+		ORDER BY id`,
+		pq.Array(userIDs), drawNo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	picks := make(map[int64][][]int)
+	for rows.Next() {
+		var userID int64
+		var numbers pq.Int64Array
+		if err := rows.Scan(&userID, &numbers); err != nil {
+			return nil, err
+		}
+		nums := make([]int, len(numbers))
+		for i, n := range numbers {
+			nums[i] = int(n)
+		}
+		picks[userID] = append(picks[userID], nums)
+	}
+	return picks, rows.Err()
+}
+
 // ========================================
 // WinningCheck
 // ========================================
 
 // SaveWinningCheck 당첨 확인 결과 저장
-func (r *Repository) SaveWinningCheck(ctx context.Context, wc *WinningCheck) error {
-	return r.db.QueryRowContext(ctx, `
-		INSERT INTO winning_checks (recommendation_id, user_id, draw_no, matched_numbers, matched_count, bonus_matched, prize_rank)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+// 추천/게임당 한 번만 저장되며, 이미 확인된 대상이면 false를 반환한다.
+func (r *Repository) SaveWinningCheck(ctx context.Context, wc *WinningCheck) (bool, error) {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO winning_checks (recommendation_id, ticket_game_id, user_id, draw_no, matched_numbers, matched_count, bonus_matched, prize_rank)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT DO NOTHING
 		RETURNING id, created_at`,
-		wc.RecommendationID, wc.UserID, wc.DrawNo,
+		wc.RecommendationID, wc.TicketGameID, wc.UserID, wc.DrawNo,
 		pq.Array(wc.MatchedNumbers), wc.MatchedCount, wc.BonusMatched, wc.PrizeRank,
 	).Scan(&wc.ID, &wc.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // GetWinnersByDrawNo 특정 회차의 당첨자 조회 (1~5등)
 func (r *Repository) GetWinnersByDrawNo(ctx context.Context, drawNo int) ([]WinningCheck, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, recommendation_id, user_id, draw_no, matched_numbers, matched_count, bonus_matched, prize_rank, created_at
+		SELECT id, recommendation_id, ticket_game_id, user_id, draw_no, matched_numbers, matched_count, bonus_matched, prize_rank, created_at
 		FROM winning_checks
 		WHERE draw_no = $1 AND prize_rank IS NOT NULL
 		ORDER BY prize_rank ASC`,
@@ -115,7 +424,7 @@ func (r *Repository) GetWinningsByUserID(ctx context.Context, userID int64, limi
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, recommendation_id, user_id, draw_no, matched_numbers, matched_count, bonus_matched, prize_rank, created_at
+		SELECT id, recommendation_id, ticket_game_id, user_id, draw_no, matched_numbers, matched_count, bonus_matched, prize_rank, created_at
 		FROM winning_checks
 		WHERE user_id = $1 AND prize_rank IS NOT NULL
 		ORDER BY created_at DESC
@@ -134,25 +443,12 @@ func (r *Repository) GetWinningsByUserID(ctx context.Context, userID int64, limi
 	return checks, totalCount, nil
 }
 
-// CheckAlreadyProcessed 해당 회차가 이미 처리되었는지 확인
-func (r *Repository) CheckAlreadyProcessed(ctx context.Context, drawNo int) (bool, error) {
-	var count int
-	err := r.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM winning_checks WHERE draw_no = $1`,
-		drawNo,
-	).Scan(&count)
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
-
 func scanWinningChecks(rows *sql.Rows) ([]WinningCheck, error) {
 	var checks []WinningCheck
 	for rows.Next() {
 		var wc WinningCheck
 		if err := rows.Scan(
-			&wc.ID, &wc.RecommendationID, &wc.UserID, &wc.DrawNo,
+			&wc.ID, &wc.RecommendationID, &wc.TicketGameID, &wc.UserID, &wc.DrawNo,
 			pq.Array(&wc.MatchedNumbers), &wc.MatchedCount, &wc.BonusMatched,
 			&wc.PrizeRank, &wc.CreatedAt,
 		); err != nil {
@@ -170,11 +466,12 @@ func scanWinningChecks(rows *sql.Rows) ([]WinningCheck, error) {
 // SavePushNotification 푸시 알림 기록 저장
 func (r *Repository) SavePushNotification(ctx context.Context, pn *PushNotification) error {
 	return r.db.QueryRowContext(ctx, `
-		INSERT INTO push_notifications (user_id, type, title, body, data, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at`,
-		pn.UserID, pn.Type, pn.Title, pn.Body, pn.Data, pn.Status,
-	).Scan(&pn.ID, &pn.CreatedAt)
+		INSERT INTO push_notifications (user_id, type, title, body, data, collapse_id, thread_id, status, deliver_after)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8,
+		        CASE WHEN $9::float8 > 0 THEN NOW() + make_interval(secs => $9::float8) END)
+		RETURNING id, created_at, deliver_after`,
+		pn.UserID, pn.Type, pn.Title, pn.Body, pn.Data, pn.CollapseID, pn.ThreadID, pn.Status, pn.deferBy.Seconds(),
+	).Scan(&pn.ID, &pn.CreatedAt, &pn.DeliverAfter)
 }
 
 // UpdatePushStatus 푸시 알림 상태 업데이트
@@ -192,15 +489,23 @@ func (r *Repository) UpdatePushStatus(ctx context.Context, id int64, status stri
 	return err
 }
 
-// GetNotificationsByUserID 사용자의 알림 목록 조회
-func (r *Repository) GetNotificationsByUserID(ctx context.Context, userID int64, limit, offset int) ([]PushNotification, int, error) {
+// GetNotificationsByUserID 사용자 알림 목록 조회 (filter: FilterInbox / FilterUnread / FilterArchived)
+func (r *Repository) GetNotificationsByUserID(ctx context.Context, userID int64, filter string, limit, offset int) ([]PushNotification, int, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
+	where := "user_id = $1 AND archived_at IS NULL"
+	switch filter {
+	case FilterUnread:
+		where += " AND read_at IS NULL"
+	case FilterArchived:
+		where = "user_id = $1 AND archived_at IS NOT NULL"
+	}
+
 	var totalCount int
 	err := r.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM push_notifications WHERE user_id = $1`,
+		SELECT COUNT(*) FROM push_notifications WHERE `+where,
 		userID,
 	).Scan(&totalCount)
 	if err != nil {
@@ -208,9 +513,9 @@ func (r *Repository) GetNotificationsByUserID(ctx context.Context, userID int64,
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, type, title, body, data, status, error_message, created_at, sent_at
+		SELECT id, user_id, type, title, body, data, status, error_message, created_at, sent_at, read_at, archived_at
 		FROM push_notifications
-		WHERE user_id = $1
+		WHERE `+where+`
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3`,
 		userID, limit, offset,
@@ -226,6 +531,7 @@ func (r *Repository) GetNotificationsByUserID(ctx context.Context, userID int64,
 		if err := rows.Scan(
 			&pn.ID, &pn.UserID, &pn.Type, &pn.Title, &pn.Body,
 			&pn.Data, &pn.Status, &pn.ErrorMessage, &pn.CreatedAt, &pn.SentAt,
+			&pn.ReadAt, &pn.ArchivedAt,
 		); err != nil {
 			return nil, 0, err
 		}
@@ -237,18 +543,467 @@ func (r *Repository) GetNotificationsByUserID(ctx context.Context, userID int64,
 	return notifications, totalCount, nil
 }
 
+// GetNotificationsSince afterID 이후 생성된 알림 조회 (오래된 순, 스트림 재접속 시 재전송용)
+func (r *Repository) GetNotificationsSince(ctx context.Context, userID, afterID int64, limit int) ([]PushNotification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, type, title, body, data, status, error_message, created_at, sent_at, read_at, archived_at
+		FROM push_notifications
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3`,
+		userID, afterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []PushNotification
+	for rows.Next() {
+		var pn PushNotification
+		if err := rows.Scan(
+			&pn.ID, &pn.UserID, &pn.Type, &pn.Title, &pn.Body,
+			&pn.Data, &pn.Status, &pn.ErrorMessage, &pn.CreatedAt, &pn.SentAt,
+			&pn.ReadAt, &pn.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, pn)
+	}
+	return notifications, rows.Err()
+}
+
+// ========================================
+// 읽음/보관 상태
+// ========================================
+
+// CountUnread 보관하지 않은 읽지 않은 알림 수 (앱 배지)
+func (r *Repository) CountUnread(ctx context.Context, userID int64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM push_notifications
+		WHERE user_id = $1 AND read_at IS NULL AND archived_at IS NULL`,
+		userID,
+	).Scan(&count)
+	return count, err
+}
+
+// MarkNotificationRead 알림 읽음 처리 (이미 읽은 알림은 처음 읽은 시각 유지)
+func (r *Repository) MarkNotificationRead(ctx context.Context, userID, id int64) error {
+	return r.updateNotificationState(ctx, `
+		UPDATE push_notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2`,
+		id, userID,
+	)
+}
+
+// MarkAllNotificationsRead 읽지 않은 알림 모두 읽음 처리
+// beforeID가 0보다 크면 그 이하 알림만 처리한다 (목록을 불러온 뒤 새로 온 알림은 남겨둠).
+func (r *Repository) MarkAllNotificationsRead(ctx context.Context, userID, beforeID int64) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE push_notifications SET read_at = NOW()
+		WHERE user_id = $1 AND read_at IS NULL AND ($2::BIGINT = 0 OR id <= $2::BIGINT)`,
+		userID, beforeID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// ArchiveNotification 알림 보관 (기본 목록과 배지에서 제외, 읽음으로 처리)
+func (r *Repository) ArchiveNotification(ctx context.Context, userID, id int64) error {
+	return r.updateNotificationState(ctx, `
+		UPDATE push_notifications
+		SET archived_at = COALESCE(archived_at, NOW()), read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2`,
+		id, userID,
+	)
+}
+
+// DeleteNotification 알림 삭제 (발송 대기 중인 push/이메일 작업도 함께 삭제됨)
+func (r *Repository) DeleteNotification(ctx context.Context, userID, id int64) error {
+	return r.updateNotificationState(ctx, `
+		DELETE FROM push_notifications WHERE id = $1 AND user_id = $2`,
+		id, userID,
+	)
+}
+
+// updateNotificationState 사용자 알림 한 건 변경 (대상이 없으면 ErrNotificationNotFound)
+func (r *Repository) updateNotificationState(ctx context.Context, query string, id, userID int64) error {
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// ========================================
+// PushDelivery (발송 큐)
+// ========================================
+
+// EnqueueDeliveries 사용자의 활성 디바이스 토큰마다 발송 작업 생성
+// delay가 있으면(방해 금지 시간) 그 이후에 발송 대상이 된다.
+func (r *Repository) EnqueueDeliveries(ctx context.Context, notificationID, userID int64, delay time.Duration) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO push_deliveries (notification_id, device_token_id, next_attempt_at)
+		SELECT $1, id, NOW() + make_interval(secs => $3) FROM device_tokens
+		WHERE user_id = $2 AND is_active = true
+		ON CONFLICT (notification_id, device_token_id) DO NOTHING`,
+		notificationID, userID, delay.Seconds(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// ClaimDueDeliveries 발송 시각이 된 작업을 최대 limit개 점유
+// 다른 디스패처가 잠근 행은 건너뛰며(SKIP LOCKED), 점유한 행은 lease 동안 sending 상태가 된다.
+// 발송 도중 서버가 종료되면 lease가 지난 sending 행을 다시 점유한다.
+func (r *Repository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]claimedDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH due AS (
+			SELECT id FROM push_deliveries
+			WHERE status IN ('pending', 'sending') AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE push_deliveries d
+		SET status = 'sending', attempts = d.attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2), updated_at = NOW()
+		FROM due, push_notifications n, device_tokens t
+		WHERE d.id = due.id AND n.id = d.notification_id AND t.id = d.device_token_id
+		RETURNING d.id, d.notification_id, d.attempts,
+		          n.title, n.body, n.data, COALESCE(n.collapse_id, ''), COALESCE(n.thread_id, ''),
+		          t.id, t.user_id, t.token, t.platform, t.subscription, t.is_active,
+		          (SELECT COUNT(*) FROM push_notifications u
+		           WHERE u.user_id = t.user_id AND u.read_at IS NULL AND u.archived_at IS NULL)`,
+		limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []claimedDelivery
+	for rows.Next() {
+		var c claimedDelivery
+		var data, subJSON []byte
+		var badge int
+		if err := rows.Scan(
+			&c.ID, &c.NotificationID, &c.Attempts,
+			&c.Message.Title, &c.Message.Body, &data, &c.Message.CollapseID, &c.Message.ThreadID,
+			&c.Token.ID, &c.Token.UserID, &c.Token.Token, &c.Token.Platform, &subJSON, &c.Token.IsActive,
+			&badge,
+		); err != nil {
+			return nil, err
+		}
+		c.Message.Badge = &badge
+		if data != nil {
+			if err := json.Unmarshal(data, &c.Message.Data); err != nil {
+				return nil, fmt.Errorf("decode data of notification %d: %w", c.NotificationID, err)
+			}
+		}
+		if subJSON != nil {
+			c.Token.Subscription = &WebPushSubscription{}
+			if err := json.Unmarshal(subJSON, c.Token.Subscription); err != nil {
+				return nil, fmt.Errorf("decode subscription of token %d: %w", c.Token.ID, err)
+			}
+		}
+		claimed = append(claimed, c)
+	}
+	return claimed, rows.Err()
+}
+
+// MarkDeliverySent 발송 성공 처리
+func (r *Repository) MarkDeliverySent(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE push_deliveries
+		SET status = 'sent', last_error = NULL, sent_at = NOW(), updated_at = NOW()
+		WHERE id = $1`,
+		id,
+	)
+	return err
+}
+
+// ScheduleDeliveryRetry delay 후 다시 발송하도록 대기 상태로 되돌림
+func (r *Repository) ScheduleDeliveryRetry(ctx context.Context, id int64, delay time.Duration, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE push_deliveries
+		SET status = 'pending', next_attempt_at = NOW() + make_interval(secs => $2), last_error = $3, updated_at = NOW()
+		WHERE id = $1`,
+		id, delay.Seconds(), errMsg,
+	)
+	return err
+}
+
+// MarkDeliveryDead 더 이상 재시도하지 않음 (최대 시도 초과 또는 영구 오류)
+func (r *Repository) MarkDeliveryDead(ctx context.Context, id int64, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE push_deliveries
+		SET status = 'dead', last_error = $2, updated_at = NOW()
+		WHERE id = $1`,
+		id, errMsg,
+	)
+	return err
+}
+
+// RefreshNotificationStatus 토큰별·이메일 발송 상태를 집계해 알림 상태 갱신
+// 대기 중인 작업이 있으면 pending, 모두 성공이면 sent, 일부만 성공이면 partial, 모두 실패면 failed
+func (r *Repository) RefreshNotificationStatus(ctx context.Context, notificationID int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH d AS (
+			SELECT status, last_error, updated_at FROM push_deliveries WHERE notification_id = $1
+			UNION ALL
+			SELECT status, last_error, updated_at FROM email_deliveries WHERE notification_id = $1
+		)
+		UPDATE push_notifications n
+		SET status = CASE
+		        WHEN agg.waiting > 0 THEN 'pending'
+		        WHEN agg.dead = 0 THEN 'sent'
+		        WHEN agg.sent > 0 THEN 'partial'
+		        ELSE 'failed'
+		    END,
+		    sent_at = CASE WHEN agg.sent > 0 THEN COALESCE(n.sent_at, NOW()) ELSE n.sent_at END,
+		    error_message = agg.last_error
+		FROM (
+			SELECT COUNT(*) FILTER (WHERE status IN ('pending', 'sending')) AS waiting,
+			       COUNT(*) FILTER (WHERE status = 'sent') AS sent,
+			       COUNT(*) FILTER (WHERE status = 'dead') AS dead,
+			       (SELECT last_error FROM d WHERE status = 'dead' ORDER BY updated_at DESC LIMIT 1) AS last_error
+			FROM d
+		) agg
+		WHERE n.id = $1 AND agg.waiting + agg.sent + agg.dead > 0`,
+		notificationID,
+	)
+	return err
+}
+
+// GetDeliveries 발송 기록 조회 (status가 비어 있으면 전체, 최근 갱신 순)
+func (r *Repository) GetDeliveries(ctx context.Context, status string, limit, offset int) ([]PushDelivery, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var totalCount int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM push_deliveries WHERE ($1 = '' OR status = $1)`,
+		status,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT d.id, d.notification_id, d.device_token_id, n.user_id, n.type, n.title, t.platform,
+		       d.status, d.attempts, d.next_attempt_at, d.last_error, d.created_at, d.updated_at, d.sent_at
+		FROM push_deliveries d
+		JOIN push_notifications n ON n.id = d.notification_id
+		JOIN device_tokens t ON t.id = d.device_token_id
+		WHERE ($1 = '' OR d.status = $1)
+		ORDER BY d.updated_at DESC, d.id DESC
+		LIMIT $2 OFFSET $3`,
+		status, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var deliveries []PushDelivery
+	for rows.Next() {
+		var d PushDelivery
+		if err := rows.Scan(
+			&d.ID, &d.NotificationID, &d.DeviceTokenID, &d.UserID, &d.Type, &d.Title, &d.Platform,
+			&d.Status, &d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &d.SentAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, totalCount, nil
+}
+
+// GetDeliveryStatusCounts 상태별 발송 작업 수
+func (r *Repository) GetDeliveryStatusCounts(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT status, COUNT(*) FROM push_deliveries GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		counts[status] = n
+	}
+	return counts, rows.Err()
+}
+
+// RequeueDeadDeliveries dead 상태인 작업을 시도 횟수를 초기화해 즉시 재발송 대기로 전환
+// 다시 대기열에 들어간 작업의 알림 ID 목록(중복 제거)을 반환한다.
+func (r *Repository) RequeueDeadDeliveries(ctx context.Context, ids []int64) ([]int64, int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE push_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+		WHERE id = ANY($1) AND status = 'dead'
+		RETURNING notification_id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var notificationIDs []int64
+	seen := make(map[int64]bool)
+	requeued := 0
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, 0, err
+		}
+		requeued++
+		if !seen[id] {
+			seen[id] = true
+			notificationIDs = append(notificationIDs, id)
+		}
+	}
+	return notificationIDs, requeued, rows.Err()
+}
+
+// ========================================
+// EmailDelivery (이메일 발송 큐)
+// ========================================
+
+// EnqueueEmailDelivery 사용자의 이메일 주소로 알림 발송 작업 생성 (delay만큼 미룸)
+// 이메일이 없는 사용자(게스트 등)면 false
+func (r *Repository) EnqueueEmailDelivery(ctx context.Context, notificationID, userID int64, delay time.Duration) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO email_deliveries (notification_id, user_id, email, next_attempt_at)
+		SELECT $1, id, email, NOW() + make_interval(secs => $3)
+		FROM users
+		WHERE id = $2 AND email IS NOT NULL AND email <> ''
+		ON CONFLICT (notification_id) DO NOTHING`,
+		notificationID, userID, delay.Seconds(),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// ClaimDueEmails 발송 시각이 된 이메일 작업을 lease 동안 점유 (ClaimDueDeliveries와 같은 방식)
+func (r *Repository) ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]claimedEmail, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH due AS (
+			SELECT id FROM email_deliveries
+			WHERE status IN ('pending', 'sending') AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE email_deliveries d
+		SET status = 'sending', attempts = d.attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2), updated_at = NOW()
+		FROM due, push_notifications n
+		WHERE d.id = due.id AND n.id = d.notification_id
+		RETURNING d.id, d.notification_id, d.user_id, d.email, d.attempts,
+		          n.type, n.title, n.body, n.data`,
+		limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []claimedEmail
+	for rows.Next() {
+		var c claimedEmail
+		var data []byte
+		if err := rows.Scan(
+			&c.ID, &c.NotificationID, &c.UserID, &c.Email, &c.Attempts,
+			&c.Type, &c.Message.Title, &c.Message.Body, &data,
+		); err != nil {
+			return nil, err
+		}
+		if data != nil {
+			if err := json.Unmarshal(data, &c.Message.Data); err != nil {
+				return nil, fmt.Errorf("decode data of notification %d: %w", c.NotificationID, err)
+			}
+		}
+		claimed = append(claimed, c)
+	}
+	return claimed, rows.Err()
+}
+
+// MarkEmailSent 이메일 발송 성공 처리
+func (r *Repository) MarkEmailSent(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE email_deliveries
+		SET status = 'sent', last_error = NULL, sent_at = NOW(), updated_at = NOW()
+		WHERE id = $1`,
+		id,
+	)
+	return err
+}
+
+// ScheduleEmailRetry 이메일 발송 실패, delay 후 재시도
+func (r *Repository) ScheduleEmailRetry(ctx context.Context, id int64, delay time.Duration, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE email_deliveries
+		SET status = 'pending', last_error = $2,
+		    next_attempt_at = NOW() + make_interval(secs => $3), updated_at = NOW()
+		WHERE id = $1`,
+		id, errMsg, delay.Seconds(),
+	)
+	return err
+}
+
+// MarkEmailDead 이메일 발송 포기
+func (r *Repository) MarkEmailDead(ctx context.Context, id int64, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE email_deliveries
+		SET status = 'dead', last_error = $2, updated_at = NOW()
+		WHERE id = $1`,
+		id, errMsg,
+	)
+	return err
+}
+
 // ========================================
 // Recommendations 조회 (lotto_recommendations 테이블)
 // ========================================
 
-// GetRecentRecommendations 최근 7일간의 추천 기록 조회
-func (r *Repository) GetRecentRecommendations(ctx context.Context, since time.Time) ([]RecommendationRow, error) {
+// GetUncheckedRecommendationsByDrawNo 대상 회차가 drawNo이고 아직 당첨 확인되지 않은 추천 기록 조회
+func (r *Repository) GetUncheckedRecommendationsByDrawNo(ctx context.Context, drawNo int) ([]RecommendationRow, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, method_codes, numbers, bonus_number, confidence, created_at
-		FROM lotto_recommendations
-		WHERE created_at >= $1 AND user_id IS NOT NULL
-		ORDER BY created_at DESC`,
-		since,
+		SELECT r.id, r.user_id, r.draw_no, r.method_codes, r.numbers, r.bonus_number, r.confidence, r.created_at
+		FROM lotto_recommendations r
+		WHERE r.draw_no = $1 AND r.user_id IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM winning_checks wc WHERE wc.recommendation_id = r.id)
+		ORDER BY r.id ASC`,
+		drawNo,
 	)
 	if err != nil {
 		return nil, err
@@ -259,7 +1014,7 @@ func (r *Repository) GetRecentRecommendations(ctx context.Context, since time.Ti
 	for rows.Next() {
 		var rec RecommendationRow
 		if err := rows.Scan(
-			&rec.ID, &rec.UserID, pq.Array(&rec.MethodCodes), pq.Array(&rec.Numbers),
+			&rec.ID, &rec.UserID, &rec.DrawNo, pq.Array(&rec.MethodCodes), pq.Array(&rec.Numbers),
 			&rec.BonusNumber, &rec.Confidence, &rec.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -269,10 +1024,72 @@ func (r *Repository) GetRecentRecommendations(ctx context.Context, since time.Ti
 	return recs, rows.Err()
 }
 
+// ========================================
+// 구매 용지 게임 조회 (lotto_ticket_games 테이블)
+// ========================================
+
+// GetUncheckedTicketGamesByDrawNo 회차가 drawNo이고 아직 당첨 확인되지 않은 구매 게임 조회
+func (r *Repository) GetUncheckedTicketGamesByDrawNo(ctx context.Context, drawNo int) ([]TicketGameRow, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.id, t.id, t.user_id, t.draw_no, g.numbers
+		FROM lotto_ticket_games g
+		JOIN lotto_tickets t ON t.id = g.ticket_id
+		WHERE t.draw_no = $1
+		  AND NOT EXISTS (SELECT 1 FROM winning_checks wc WHERE wc.ticket_game_id = g.id)
+		ORDER BY g.id ASC`,
+		drawNo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanTicketGames(rows)
+}
+
+// GetUncheckedTicketGamesByTicketID 용지 한 장의 미확인 게임 조회 (지난 회차 용지 등록 시)
+func (r *Repository) GetUncheckedTicketGamesByTicketID(ctx context.Context, ticketID int64) ([]TicketGameRow, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.id, t.id, t.user_id, t.draw_no, g.numbers
+		FROM lotto_ticket_games g
+		JOIN lotto_tickets t ON t.id = g.ticket_id
+		WHERE t.id = $1
+		  AND NOT EXISTS (SELECT 1 FROM winning_checks wc WHERE wc.ticket_game_id = g.id)
+		ORDER BY g.id ASC`,
+		ticketID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanTicketGames(rows)
+}
+
+func scanTicketGames(rows *sql.Rows) ([]TicketGameRow, error) {
+	defer rows.Close()
+
+	var games []TicketGameRow
+	for rows.Next() {
+		var g TicketGameRow
+		if err := rows.Scan(&g.ID, &g.TicketID, &g.UserID, &g.DrawNo, pq.Array(&g.Numbers)); err != nil {
+			return nil, err
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
+// TicketGameRow lotto_ticket_games 테이블 조회 결과
+type TicketGameRow struct {
+	ID       int64
+	TicketID int64
+	UserID   int64
+	DrawNo   int
+	Numbers  []int
+}
+
 // RecommendationRow lotto_recommendations 테이블 조회 결과
 type RecommendationRow struct {
 	ID          int64
 	UserID      *int64
+	DrawNo      int // 대상 회차
 	MethodCodes []string
 	Numbers     []int
 	BonusNumber *int
@@ -2,82 +2,130 @@ package notification
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/example/LottoSmash/internal/logger"
 	"github.com/example/LottoSmash/internal/lotto"
+	"github.com/example/LottoSmash/internal/metrics"
+	"github.com/example/LottoSmash/internal/worker"
 )
 
 type Service struct {
-	repo     *Repository
-	lottoSvc *lotto.Service
-	push     PushSender
-	log      *logger.Logger
+	repo       *Repository
+	lottoSvc   *lotto.Service
+	dispatcher *Dispatcher
+	log        *logger.Logger
+
+	webPushKey string            // VAPID 공개키 (Web Push 미설정 시 빈 값)
+	jobPool    chan<- worker.Job // 전체 발송 배치를 실행할 워커 풀 (nil이면 직접 실행)
+
+	unsubscribe *UnsubscribeTokens // 이메일 채널 (nil이면 이메일을 보내지 않음)
+	hub         *Hub               // 실시간 스트림 (nil이면 발행하지 않음)
+
+	BroadcastBatchSize int // 전체 발송 시 한 배치에서 처리할 사용자 수
 }
 
-func NewService(repo *Repository, lottoSvc *lotto.Service, push PushSender, log *logger.Logger) *Service {
+func NewService(repo *Repository, lottoSvc *lotto.Service, dispatcher *Dispatcher, log *logger.Logger) *Service {
 	return &Service{
-		repo:     repo,
-		lottoSvc: lottoSvc,
-		push:     push,
-		log:      log,
+		repo:       repo,
+		lottoSvc:   lottoSvc,
+		dispatcher: dispatcher,
+		log:        log,
 	}
 }
 
-// ProcessNewDraw 새 당첨번호 발표 시 추천번호 대조 및 알림 발송
+// ProcessNewDraw 새 당첨번호 발표 시 추천번호/구매 용지 대조 및 알림 발송
+// 대상 회차가 drawNo인 추천과 구매 게임 중 아직 확인되지 않은 것만 처리하므로 여러 번 호출해도 안전하다.
 func (s *Service) ProcessNewDraw(ctx context.Context, drawNo int) error {
-	// 이미 처리된 회차인지 확인
-	processed, err := s.repo.CheckAlreadyProcessed(ctx, drawNo)
+	// 당첨번호 조회
+	draw, err := s.lottoSvc.GetDrawByNo(ctx, drawNo)
 	if err != nil {
-		return fmt.Errorf("check already processed: %w", err)
+		return fmt.Errorf("get draw %d: %w", drawNo, err)
 	}
-	if processed {
-		s.log.Infof("draw %d already processed for winning checks, skipping", drawNo)
-		return nil
+
+	// 대상 회차 추천 기록 및 구매 게임 조회
+	recs, err := s.repo.GetUncheckedRecommendationsByDrawNo(ctx, drawNo)
+	if err != nil {
+		return fmt.Errorf("get recommendations for draw %d: %w", drawNo, err)
+	}
+	games, err := s.repo.GetUncheckedTicketGamesByDrawNo(ctx, drawNo)
+	if err != nil {
+		return fmt.Errorf("get ticket games for draw %d: %w", drawNo, err)
 	}
 
-	// 당첨번호 조회
+	s.log.Infof("processing draw %d: checking %d recommendations, %d ticket games", drawNo, len(recs), len(games))
+
+	checks := make([]WinningCheck, 0, len(recs)+len(games))
+	for _, rec := range recs {
+		checks = append(checks, CheckWinning(draw, rec))
+	}
+	for _, game := range games {
+		checks = append(checks, CheckTicketWinning(draw, game))
+	}
+
+	checkedCount, winnersCount := s.saveWinningChecks(ctx, checks, draw)
+	s.log.Infof("draw %d winning check completed: %d checked, %d winners found", drawNo, checkedCount, winnersCount)
+	return nil
+}
+
+// ProcessTicket 이미 추첨된 회차의 구매 용지 한 장만 당첨 확인 (lotto.WinningChecker 구현)
+// 당첨번호가 아직 수집되지 않았으면 lotto.ErrDrawNotFound를 반환하며, 이후 ProcessNewDraw에서 처리된다.
+func (s *Service) ProcessTicket(ctx context.Context, ticketID int64, drawNo int) error {
 	draw, err := s.lottoSvc.GetDrawByNo(ctx, drawNo)
 	if err != nil {
 		return fmt.Errorf("get draw %d: %w", drawNo, err)
 	}
 
-	// 최근 7일간 추천 기록 조회
-	since := time.Now().AddDate(0, 0, -7)
-	recs, err := s.repo.GetRecentRecommendations(ctx, since)
+	games, err := s.repo.GetUncheckedTicketGamesByTicketID(ctx, ticketID)
 	if err != nil {
-		return fmt.Errorf("get recent recommendations: %w", err)
+		return fmt.Errorf("get games of ticket %d: %w", ticketID, err)
 	}
 
-	s.log.Infof("processing draw %d: checking %d recommendations from last 7 days", drawNo, len(recs))
+	checks := make([]WinningCheck, 0, len(games))
+	for _, game := range games {
+		checks = append(checks, CheckTicketWinning(draw, game))
+	}
 
-	var winnersCount int
-	for _, rec := range recs {
-		result := CheckWinning(draw, rec)
+	checkedCount, winnersCount := s.saveWinningChecks(ctx, checks, draw)
+	s.log.Infof("ticket %d (draw %d) winning check completed: %d checked, %d winners found", ticketID, drawNo, checkedCount, winnersCount)
+	return nil
+}
+
+// saveWinningChecks 당첨 확인 결과 저장 후 당첨자에게 알림 (이미 확인된 대상은 건너뜀)
+func (s *Service) saveWinningChecks(ctx context.Context, checks []WinningCheck, draw *lotto.LottoDraw) (int, int) {
+	var checkedCount, winnersCount int
+	for i := range checks {
+		result := &checks[i]
 
-		// 당첨 결과 저장
-		if err := s.repo.SaveWinningCheck(ctx, &result); err != nil {
-			s.log.Errorf("failed to save winning check for recommendation %d: %v", rec.ID, err)
+		// 당첨 결과 저장 (이미 확인된 대상이면 건너뜀)
+		saved, err := s.repo.SaveWinningCheck(ctx, result)
+		if err != nil {
+			s.log.Errorf("failed to save winning check (recommendation %v, ticket game %v): %v",
+				derefID(result.RecommendationID), derefID(result.TicketGameID), err)
+			continue
+		}
+		if !saved {
 			continue
 		}
+		checkedCount++
 
 		// 당첨자에게 push 알림 발송
 		if result.PrizeRank != nil {
 			winnersCount++
-			if err := s.sendWinningNotification(ctx, &result, draw); err != nil {
+			if err := s.sendWinningNotification(ctx, result, draw); err != nil {
 				s.log.Errorf("failed to send notification to user %v: %v", result.UserID, err)
 			}
 		}
 	}
-
-	s.log.Infof("draw %d winning check completed: %d recommendations checked, %d winners found", drawNo, len(recs), winnersCount)
-	return nil
+	return checkedCount, winnersCount
 }
 
-// sendWinningNotification 당첨자에게 push 알림 발송
+// sendWinningNotification 당첨자에게 push 알림 발송 (발송 큐에 등록)
 func (s *Service) sendWinningNotification(ctx context.Context, wc *WinningCheck, draw *lotto.LottoDraw) error {
 	if wc.UserID == nil {
 		return nil
@@ -86,6 +134,9 @@ func (s *Service) sendWinningNotification(ctx context.Context, wc *WinningCheck,
 	rankName := PrizeRankName(*wc.PrizeRank)
 	title := fmt.Sprintf("축하합니다! 로또 %s 당첨!", rankName)
 	body := fmt.Sprintf("%d회차 당첨번호와 %d개 일치! (%s)", draw.DrawNo, wc.MatchedCount, rankName)
+	if wc.TicketGameID != nil {
+		body = fmt.Sprintf("구매하신 %d회차 용지가 당첨번호와 %d개 일치! (%s)", draw.DrawNo, wc.MatchedCount, rankName)
+	}
 
 	data := map[string]string{
 		"type":        "winning_result",
@@ -93,58 +144,232 @@ func (s *Service) sendWinningNotification(ctx context.Context, wc *WinningCheck,
 		"prize_rank":  strconv.Itoa(*wc.PrizeRank),
 		"match_count": strconv.Itoa(wc.MatchedCount),
 	}
+	if wc.RecommendationID != nil {
+		data["recommendation_id"] = strconv.FormatInt(*wc.RecommendationID, 10)
+	}
+	if wc.TicketGameID != nil {
+		data["ticket_game_id"] = strconv.FormatInt(*wc.TicketGameID, 10)
+	}
 
 	dataJSON, _ := json.Marshal(data)
 	dataStr := string(dataJSON)
 
-	// 알림 기록 저장
-	pn := &PushNotification{
-		UserID: wc.UserID,
-		Type:   "winning_result",
-		Title:  title,
-		Body:   body,
-		Data:   &dataStr,
-		Status: "pending",
+	return s.enqueueNotification(ctx, &PushNotification{
+		UserID:     wc.UserID,
+		Type:       TypeWinningResult,
+		Title:      title,
+		Body:       body,
+		Data:       &dataStr,
+		CollapseID: fmt.Sprintf("winning-%d", draw.DrawNo),
+		ThreadID:   fmt.Sprintf("draw-%d", draw.DrawNo),
+	})
+}
+
+// enqueueNotification 알림 기록 저장 후 사용자의 활성 디바이스 토큰마다 발송 작업을 큐에 넣음
+// 사용자가 해당 유형이나 push 채널을 끈 경우 기록하지 않으며, 방해 금지 시간이면 끝난 뒤로 미룬다.
+// 실제 발송과 재시도는 Dispatcher가 처리한다.
+func (s *Service) enqueueNotification(ctx context.Context, pn *PushNotification) error {
+	prefs, err := s.GetPreferences(ctx, *pn.UserID)
+	if err != nil {
+		return err
+	}
+	ready, err := s.enqueueWithPrefs(ctx, pn, prefs, time.Now())
+	if err != nil {
+		return err
+	}
+	if ready {
+		s.dispatcher.Wake()
+	}
+	s.publishNotification(ctx, pn)
+	return nil
+}
+
+// enqueueWithPrefs 이미 조회한 알림 설정으로 enqueueNotification 처리 (전체 발송 배치용)
+// push 채널이 켜져 있지만 활성 디바이스 토큰이 없으면 이메일로 대신 보낸다 (email_fallback).
+// 바로 발송할 작업을 큐에 넣었으면 true를 반환하며, 디스패처를 깨우는 것은 호출자가 한다.
+func (s *Service) enqueueWithPrefs(ctx context.Context, pn *PushNotification, prefs *NotificationPreferences, now time.Time) (bool, error) {
+	emailOn := s.unsubscribe != nil
+	if !prefs.Allows(pn.Type) || (!prefs.PushEnabled && !(emailOn && prefs.EmailEnabled)) {
+		s.log.Debugf("user %d opted out of %s notifications, skipping", *pn.UserID, pn.Type)
+		return false, nil
+	}
+
+	if until := prefs.DeferUntil(pn.Type, now); !until.IsZero() {
+		if !pn.expiresAt.IsZero() && until.After(pn.expiresAt) {
+			s.log.Debugf("%s push for user %d would be deferred past %s, skipping", pn.Type, *pn.UserID, pn.expiresAt.Format(time.RFC3339))
+			return false, nil
+		}
+		pn.deferBy = until.Sub(now)
 	}
+
+	pn.Status = NotificationPending
 	if err := s.repo.SavePushNotification(ctx, pn); err != nil {
-		return fmt.Errorf("save push notification: %w", err)
+		return false, fmt.Errorf("save push notification: %w", err)
+	}
+
+	var queued int
+	if prefs.PushEnabled {
+		n, err := s.repo.EnqueueDeliveries(ctx, pn.ID, *pn.UserID, pn.deferBy)
+		if err != nil {
+			s.repo.UpdatePushStatus(ctx, pn.ID, NotificationFailed, strPtr("failed to enqueue deliveries"))
+			return false, fmt.Errorf("enqueue deliveries: %w", err)
+		}
+		queued = n
+	}
+	if emailOn && (prefs.EmailEnabled || (prefs.PushEnabled && prefs.EmailFallback && queued == 0)) {
+		ok, err := s.repo.EnqueueEmailDelivery(ctx, pn.ID, *pn.UserID, pn.deferBy)
+		if err != nil && queued == 0 {
+			s.repo.UpdatePushStatus(ctx, pn.ID, NotificationFailed, strPtr("failed to enqueue email"))
+			return false, fmt.Errorf("enqueue email delivery: %w", err)
+		}
+		if err != nil {
+			s.log.Errorf("failed to enqueue email for notification %d: %v", pn.ID, err)
+		} else if ok {
+			queued++
+		}
+	}
+	if queued == 0 {
+		s.log.Infof("no active device tokens or email for user %d, skipping %s", *pn.UserID, pn.Type)
+		s.repo.UpdatePushStatus(ctx, pn.ID, NotificationSent, nil)
+		return false, nil
 	}
 
-	// 디바이스 토큰 조회 후 발송
-	tokens, err := s.repo.GetActiveTokensByUserID(ctx, *wc.UserID)
+	if pn.deferBy > 0 {
+		s.log.Infof("%s notification %d for user %d deferred until %s (quiet hours)", pn.Type, pn.ID, *pn.UserID, now.Add(pn.deferBy).Format(time.RFC3339))
+		return false, nil
+	}
+	return true, nil
+}
+
+// UnsubscribeEmail 수신 거부 링크 처리: 이메일 알림과 이메일 대체 발송을 모두 끔
+func (s *Service) UnsubscribeEmail(ctx context.Context, token string) error {
+	if s.unsubscribe == nil {
+		return ErrInvalidUnsubscribeToken
+	}
+	userID, err := s.unsubscribe.Verify(token)
 	if err != nil {
-		s.repo.UpdatePushStatus(ctx, pn.ID, "failed", strPtr("failed to get device tokens"))
-		return fmt.Errorf("get device tokens: %w", err)
+		return err
 	}
 
-	if len(tokens) == 0 {
-		s.log.Infof("no active device tokens for user %d, skipping push", *wc.UserID)
-		s.repo.UpdatePushStatus(ctx, pn.ID, "sent", nil)
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !prefs.EmailEnabled && !prefs.EmailFallback {
 		return nil
 	}
+	prefs.EmailEnabled, prefs.EmailFallback = false, false
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return fmt.Errorf("save notification preferences: %w", err)
+	}
+	s.log.Infof("user %d unsubscribed from notification emails", userID)
+	return nil
+}
 
-	var lastErr error
-	for _, token := range tokens {
-		if err := s.push.Send(ctx, token.Token, title, body, data); err != nil {
-			s.log.Errorf("failed to send push to token %s: %v", token.Token, err)
-			lastErr = err
-			continue
-		}
+// GetPreferences 사용자 알림 설정 조회 (저장한 적 없으면 기본값)
+func (s *Service) GetPreferences(ctx context.Context, userID int64) (*NotificationPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences 사용자 알림 설정 변경 (보낸 항목만 반영)
+func (s *Service) UpdatePreferences(ctx context.Context, userID int64, req *UpdatePreferencesRequest) (*NotificationPreferences, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	req.apply(prefs, time.Now())
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("save notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// GetDeliveries 발송 기록과 상태별 건수 조회 (관리자)
+func (s *Service) GetDeliveries(ctx context.Context, status string, limit, offset int) (*DeliveryListResponse, error) {
+	deliveries, total, err := s.repo.GetDeliveries(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get deliveries: %w", err)
 	}
+	counts, err := s.repo.GetDeliveryStatusCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get delivery counts: %w", err)
+	}
+	if deliveries == nil {
+		deliveries = []PushDelivery{}
+	}
+	return &DeliveryListResponse{Deliveries: deliveries, TotalCount: total, StatusCounts: counts}, nil
+}
 
-	if lastErr != nil {
-		errMsg := lastErr.Error()
-		s.repo.UpdatePushStatus(ctx, pn.ID, "failed", &errMsg)
-		return lastErr
+// RequeueDeliveries dead 상태 발송 작업을 다시 대기열에 넣음 (관리자)
+func (s *Service) RequeueDeliveries(ctx context.Context, ids []int64) (int, error) {
+	notificationIDs, requeued, err := s.repo.RequeueDeadDeliveries(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("requeue deliveries: %w", err)
+	}
+	for _, id := range notificationIDs {
+		if err := s.repo.RefreshNotificationStatus(ctx, id); err != nil {
+			s.log.Errorf("failed to refresh notification %d status: %v", id, err)
+		}
+	}
+	if requeued > 0 {
+		s.log.Infof("requeued %d dead push deliveries", requeued)
+		s.dispatcher.Wake()
 	}
+	return requeued, nil
+}
+
+// SetWebPushPublicKey 브라우저 구독에 사용할 VAPID 공개키 설정
+func (s *Service) SetWebPushPublicKey(key string) {
+	s.webPushKey = key
+}
+
+// SetEmailChannel 이메일 알림 채널 활성화 (발송은 Dispatcher.SetEmailSender로 설정한 발송기가 처리)
+func (s *Service) SetEmailChannel(tokens *UnsubscribeTokens) {
+	s.unsubscribe = tokens
+}
+
+// WebPushPublicKey VAPID 공개키 조회 (Web Push 미설정 시 빈 값)
+func (s *Service) WebPushPublicKey() string {
+	return s.webPushKey
+}
 
-	s.repo.UpdatePushStatus(ctx, pn.ID, "sent", nil)
+// RegisterDeviceToken 디바이스 토큰 등록 (web이면 구독 정보 포함)
+// oldToken이 있으면 갱신 전 토큰을 대체하며, 정리된 토큰 수는 메트릭에 기록한다.
+func (s *Service) RegisterDeviceToken(ctx context.Context, userID int64, token, platform string, subscription *WebPushSubscription, oldToken string) error {
+	result, err := s.repo.UpsertDeviceToken(ctx, userID, token, platform, subscription, oldToken)
+	if err != nil {
+		return err
+	}
+	metrics.AddPrunedTokens(TokenReasonReplaced, result.Replaced)
+	metrics.AddPrunedTokens(TokenReasonReassigned, result.Reassigned)
+	if result.Reassigned > 0 {
+		s.log.Infof("device token of user %d was registered to other users, deactivated %d", userID, result.Reassigned)
+	}
 	return nil
 }
 
-// RegisterDeviceToken 디바이스 토큰 등록
-func (s *Service) RegisterDeviceToken(ctx context.Context, userID int64, token, platform string) error {
-	return s.repo.UpsertDeviceToken(ctx, userID, token, platform)
+// ExpireStaleTokens days일 이상 다시 등록되지 않은 토큰 비활성화
+// 앱은 실행할 때마다 토큰을 등록하므로, 오래 등록되지 않은 토큰은 앱이 삭제된 기기로 본다.
+func (s *Service) ExpireStaleTokens(ctx context.Context, days int) (int64, error) {
+	if days <= 0 {
+		return 0, nil
+	}
+	n, err := s.repo.ExpireStaleTokens(ctx, days)
+	if err != nil {
+		return 0, fmt.Errorf("expire stale tokens: %w", err)
+	}
+	metrics.AddPrunedTokens(TokenReasonExpired, n)
+	if n > 0 {
+		s.log.Infof("expired %d device tokens unused for %d days", n, days)
+	}
+	return n, nil
 }
 
 // DeactivateDeviceToken 디바이스 토큰 비활성화
@@ -152,9 +377,60 @@ func (s *Service) DeactivateDeviceToken(ctx context.Context, userID int64, token
 	return s.repo.DeactivateDeviceToken(ctx, userID, token)
 }
 
-// GetNotifications 알림 목록 조회
-func (s *Service) GetNotifications(ctx context.Context, userID int64, limit, offset int) ([]PushNotification, int, error) {
-	return s.repo.GetNotificationsByUserID(ctx, userID, limit, offset)
+// GetNotifications 알림 목록 조회 (filter: FilterInbox / FilterUnread / FilterArchived)
+func (s *Service) GetNotifications(ctx context.Context, userID int64, filter string, limit, offset int) ([]PushNotification, int, error) {
+	return s.repo.GetNotificationsByUserID(ctx, userID, filter, limit, offset)
+}
+
+// CountUnread 읽지 않은 알림 수 (앱 배지)
+func (s *Service) CountUnread(ctx context.Context, userID int64) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead 알림 읽음 처리 후 남은 읽지 않은 알림 수 반환
+func (s *Service) MarkRead(ctx context.Context, userID, id int64) (int, error) {
+	if err := s.repo.MarkNotificationRead(ctx, userID, id); err != nil {
+		return 0, err
+	}
+	return s.unreadChanged(ctx, userID)
+}
+
+// MarkAllRead 읽지 않은 알림 모두 읽음 처리 (beforeID > 0이면 그 이하만)
+func (s *Service) MarkAllRead(ctx context.Context, userID, beforeID int64) (int, int, error) {
+	updated, err := s.repo.MarkAllNotificationsRead(ctx, userID, beforeID)
+	if err != nil {
+		return 0, 0, err
+	}
+	unread, err := s.unreadChanged(ctx, userID)
+	return updated, unread, err
+}
+
+// ArchiveNotification 알림 보관 후 남은 읽지 않은 알림 수 반환
+func (s *Service) ArchiveNotification(ctx context.Context, userID, id int64) (int, error) {
+	if err := s.repo.ArchiveNotification(ctx, userID, id); err != nil {
+		return 0, err
+	}
+	return s.unreadChanged(ctx, userID)
+}
+
+// DeleteNotification 알림 삭제 후 남은 읽지 않은 알림 수 반환
+func (s *Service) DeleteNotification(ctx context.Context, userID, id int64) (int, error) {
+	if err := s.repo.DeleteNotification(ctx, userID, id); err != nil {
+		return 0, err
+	}
+	return s.unreadChanged(ctx, userID)
+}
+
+// unreadChanged 읽음 상태가 바뀐 뒤 배지 수를 다시 세고, 사용자의 다른 기기 스트림에도 전달
+func (s *Service) unreadChanged(ctx context.Context, userID int64) (int, error) {
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if data, err := json.Marshal(UnreadCountResponse{UnreadCount: unread}); err == nil {
+		s.publish(ctx, StreamEvent{Type: EventUnreadCount, UserID: userID, Data: data})
+	}
+	return unread, nil
 }
 
 // GetWinnings 당첨 결과 조회
@@ -165,3 +441,10 @@ func (s *Service) GetWinnings(ctx context.Context, userID int64, limit, offset i
 func strPtr(s string) *string {
 	return &s
 }
+
+func derefID(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -0,0 +1,322 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/example/LottoSmash/internal/logger"
+	"github.com/example/LottoSmash/internal/lotto"
+	"github.com/lib/pq"
+)
+
+// 실시간 스트림 이벤트 유형 (SSE event 필드)
+const (
+	EventNotification = "notification" // 새 알림 (id = push_notifications.id)
+	EventDrawResult   = "draw_result"  // 새 회차 당첨번호 (접속한 모든 사용자)
+	EventJobFinished  = "job_finished" // 대량 추천 작업 종료
+	EventUnreadCount  = "unread_count" // 읽음/보관/삭제로 배지 수 변경 (다른 기기 동기화)
+)
+
+const (
+	// MaxStreamsPerUser 사용자당 동시 스트림 연결 수 (탭/기기 여러 개)
+	MaxStreamsPerUser = 5
+
+	streamBuffer = 32 // 연결별 대기 이벤트 수 (가득 차면 느린 연결로 보고 끊음)
+
+	// StreamChannel LISTEN/NOTIFY 채널 이름
+	StreamChannel = "lottosmash_events"
+
+	// NOTIFY payload 상한은 8000바이트. 넘으면 data 없이 보내고 클라이언트가 다시 조회한다.
+	maxNotifyPayload = 7900
+)
+
+var (
+	ErrTooManyStreams = errors.New("too many open streams")
+	ErrStreamDisabled = errors.New("event stream is not enabled")
+)
+
+// StreamEvent 실시간 스트림으로 보내는 이벤트
+type StreamEvent struct {
+	ID     int64           `json:"id,omitempty"`      // 알림 이벤트만 사용 (Last-Event-ID 재전송 기준)
+	Type   string          `json:"type"`              // Event* 상수
+	UserID int64           `json:"user_id,omitempty"` // 0이면 접속한 모든 사용자에게
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// streamSub 스트림 연결 하나
+type streamSub struct {
+	userID int64
+	events chan StreamEvent
+	once   sync.Once
+}
+
+func (s *streamSub) close() {
+	s.once.Do(func() { close(s.events) })
+}
+
+// StreamPublisher 이벤트를 모든 서버 인스턴스에 전달하는 백플레인 (PGBackplane)
+type StreamPublisher interface {
+	Publish(ctx context.Context, payload []byte) error
+}
+
+// Hub 접속 중인 스트림 연결에 이벤트를 사용자별로 전달
+// 백플레인이 설정되어 있으면 발행한 이벤트는 NOTIFY를 거쳐 모든 인스턴스(자기 자신 포함)의 Hub로 전달된다.
+type Hub struct {
+	mu        sync.RWMutex
+	subs      map[int64]map[*streamSub]struct{}
+	log       *logger.Logger
+	backplane StreamPublisher
+}
+
+func NewHub(log *logger.Logger) *Hub {
+	return &Hub{subs: make(map[int64]map[*streamSub]struct{}), log: log}
+}
+
+// SetBackplane 여러 인스턴스 간 이벤트 전달 설정 (설정하지 않으면 이 인스턴스 연결에만 전달)
+func (h *Hub) SetBackplane(p StreamPublisher) {
+	h.backplane = p
+}
+
+// Subscribe 사용자 스트림 연결 등록
+func (h *Hub) Subscribe(userID int64) (*streamSub, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subs[userID]) >= MaxStreamsPerUser {
+		return nil, ErrTooManyStreams
+	}
+	sub := &streamSub{userID: userID, events: make(chan StreamEvent, streamBuffer)}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*streamSub]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe 스트림 연결 해제
+func (h *Hub) Unsubscribe(sub *streamSub) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if subs := h.subs[sub.userID]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.subs, sub.userID)
+		}
+	}
+	sub.close()
+}
+
+// Connections 접속 중인 연결 수
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	n := 0
+	for _, subs := range h.subs {
+		n += len(subs)
+	}
+	return n
+}
+
+// Publish 이벤트 발행 (백플레인 전송 실패 시 이 인스턴스 연결에만 전달)
+func (h *Hub) Publish(ctx context.Context, evt StreamEvent) {
+	if h.backplane == nil {
+		h.deliver(evt)
+		return
+	}
+
+	payload, err := json.Marshal(evt)
+	if err == nil && len(payload) > maxNotifyPayload {
+		evt.Data = nil
+		payload, err = json.Marshal(evt)
+	}
+	if err == nil {
+		err = h.backplane.Publish(ctx, payload)
+	}
+	if err != nil {
+		h.log.Errorf("failed to publish %s event through backplane: %v", evt.Type, err)
+		h.deliver(evt)
+	}
+}
+
+// deliver 이 인스턴스의 대상 연결에 이벤트 전달
+// 버퍼가 가득 찬 연결은 끊는다. 클라이언트는 Last-Event-ID로 재접속해 놓친 알림을 받는다.
+func (h *Hub) deliver(evt StreamEvent) {
+	h.mu.RLock()
+	var slow []*streamSub
+	send := func(subs map[*streamSub]struct{}) {
+		for sub := range subs {
+			select {
+			case sub.events <- evt:
+			default:
+				slow = append(slow, sub)
+			}
+		}
+	}
+	if evt.UserID == 0 {
+		for _, subs := range h.subs {
+			send(subs)
+		}
+	} else {
+		send(h.subs[evt.UserID])
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		h.log.Warnf("dropping slow event stream of user %d", sub.userID)
+		h.Unsubscribe(sub)
+	}
+}
+
+// ========================================
+// Postgres LISTEN/NOTIFY 백플레인
+// ========================================
+
+// PGBackplane Postgres LISTEN/NOTIFY로 여러 서버 인스턴스의 Hub를 연결
+type PGBackplane struct {
+	db  *sql.DB
+	dsn string
+	hub *Hub
+	log *logger.Logger
+}
+
+func NewPGBackplane(db *sql.DB, dsn string, hub *Hub, log *logger.Logger) *PGBackplane {
+	return &PGBackplane{db: db, dsn: dsn, hub: hub, log: log}
+}
+
+// Publish NOTIFY로 이벤트 전송
+func (b *PGBackplane) Publish(ctx context.Context, payload []byte) error {
+	_, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, StreamChannel, string(payload))
+	return err
+}
+
+// Start LISTEN 연결을 유지하며 받은 이벤트를 Hub로 전달. ctx가 취소될 때까지 블록된다.
+// 연결이 끊기면 pq.Listener가 다시 연결하며, 그 사이 놓친 알림은 클라이언트 재접속 시 재전송된다.
+func (b *PGBackplane) Start(ctx context.Context) {
+	listener := pq.NewListener(b.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventConnectionAttemptFailed, pq.ListenerEventDisconnected:
+			b.log.Warnf("event backplane connection lost: %v", err)
+		case pq.ListenerEventReconnected:
+			b.log.Infof("event backplane reconnected")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(StreamChannel); err != nil {
+		b.log.Errorf("failed to listen on %s: %v", StreamChannel, err)
+		return
+	}
+	b.log.Infof("event backplane listening on %s", StreamChannel)
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.log.Infof("event backplane stopping")
+			return
+		case n := <-listener.Notify:
+			if n == nil {
+				continue // 재연결됨
+			}
+			var evt StreamEvent
+			if err := json.Unmarshal([]byte(n.Extra), &evt); err != nil {
+				b.log.Warnf("invalid event on %s: %v", StreamChannel, err)
+				continue
+			}
+			b.hub.deliver(evt)
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
+
+// ========================================
+// Service
+// ========================================
+
+// SetStreamHub 실시간 스트림 활성화
+func (s *Service) SetStreamHub(hub *Hub) {
+	s.hub = hub
+}
+
+// Subscribe 사용자 스트림 연결 등록 (스트림이 꺼져 있으면 ErrStreamDisabled)
+func (s *Service) Subscribe(userID int64) (*streamSub, error) {
+	if s.hub == nil {
+		return nil, ErrStreamDisabled
+	}
+	return s.hub.Subscribe(userID)
+}
+
+// Unsubscribe 사용자 스트림 연결 해제
+func (s *Service) Unsubscribe(sub *streamSub) {
+	s.hub.Unsubscribe(sub)
+}
+
+// GetNotificationsSince 재접속 시 놓친 알림 조회 (afterID 이후, 오래된 순)
+func (s *Service) GetNotificationsSince(ctx context.Context, userID, afterID int64, limit int) ([]PushNotification, error) {
+	return s.repo.GetNotificationsSince(ctx, userID, afterID, limit)
+}
+
+// publish 스트림이 켜져 있으면 이벤트 발행
+func (s *Service) publish(ctx context.Context, evt StreamEvent) {
+	if s.hub != nil {
+		s.hub.Publish(ctx, evt)
+	}
+}
+
+// publishNotification 새 알림을 사용자 스트림으로 전달
+func (s *Service) publishNotification(ctx context.Context, pn *PushNotification) {
+	if s.hub == nil || pn.ID == 0 {
+		return
+	}
+	data, err := json.Marshal(pn)
+	if err != nil {
+		return
+	}
+	s.publish(ctx, StreamEvent{ID: pn.ID, Type: EventNotification, UserID: *pn.UserID, Data: data})
+}
+
+// PublishDrawResult 새 회차 당첨번호를 접속 중인 모든 사용자에게 전달 (회차당 한 번)
+func (s *Service) PublishDrawResult(ctx context.Context, drawNo int) error {
+	if s.hub == nil {
+		return nil
+	}
+	if time.Since(lotto.DrawSalesCutoff(drawNo)) > broadcastMaxAge {
+		return nil
+	}
+	draw, err := s.lottoSvc.GetDrawByNo(ctx, drawNo)
+	if err != nil {
+		return fmt.Errorf("get draw %d: %w", drawNo, err)
+	}
+
+	// 여러 서버의 스케줄러가 함께 호출해도 한 번만 발행
+	_, claimed, err := s.repo.ClaimBroadcast(ctx, EventDrawResult, drawNo, broadcastLease)
+	if err != nil || !claimed {
+		return err
+	}
+	data, err := json.Marshal(draw)
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, StreamEvent{Type: EventDrawResult, Data: data})
+	return s.repo.CompleteBroadcast(ctx, EventDrawResult, drawNo)
+}
+
+// NotifyJobFinished 대량 추천 작업 종료를 작업 소유자 스트림으로 전달 (lotto.JobNotifier 구현)
+func (s *Service) NotifyJobFinished(userID int64, job lotto.BulkJob) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.publish(ctx, StreamEvent{Type: EventJobFinished, UserID: userID, Data: data})
+}
@@ -0,0 +1,134 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	streamHeartbeat   = 25 * time.Second // 프록시 유휴 연결 종료 방지
+	streamRetryMs     = 3000             // 클라이언트 재접속 대기 (SSE retry)
+	streamReplayLimit = 100              // 재접속 시 재전송할 최대 알림 수
+
+	// EventResync 재전송 한도를 넘게 놓친 경우 목록을 다시 조회하라는 이벤트
+	EventResync = "resync"
+)
+
+// Stream GET /api/notifications/stream (Server-Sent Events)
+// 새 알림, 당첨번호 발표, 대량 작업 종료를 실시간으로 보낸다. 재접속 시 Last-Event-ID
+// (또는 last_event_id 쿼리) 이후의 알림을 push_notifications에서 먼저 재전송한다.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r)
+	if !ok {
+		h.errorResponse(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sub, err := h.service.Subscribe(userID)
+	switch {
+	case errors.Is(err, ErrStreamDisabled):
+		h.errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, ErrTooManyStreams):
+		h.errorResponse(w, http.StatusTooManyRequests, err.Error())
+		return
+	case err != nil:
+		h.errorResponse(w, http.StatusInternalServerError, "failed to open stream")
+		return
+	}
+	defer h.service.Unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// 서버 WriteTimeout 대신 쓰기마다 deadline을 연장
+	extend := func() { rc.SetWriteDeadline(time.Now().Add(2 * streamHeartbeat)) }
+	extend()
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMs)
+
+	// 구독 후 재전송하므로 그 사이 발행된 알림은 아래에서 ID로 걸러낸다.
+	lastID := lastEventID(r)
+	if lastID > 0 {
+		missed, err := h.service.GetNotificationsSince(r.Context(), userID, lastID, streamReplayLimit)
+		if err != nil {
+			return
+		}
+		for i := range missed {
+			data, _ := json.Marshal(&missed[i])
+			writeStreamEvent(w, StreamEvent{ID: missed[i].ID, Type: EventNotification, Data: data})
+			lastID = missed[i].ID
+		}
+		if len(missed) == streamReplayLimit {
+			writeStreamEvent(w, StreamEvent{Type: EventResync, Data: json.RawMessage(`{}`)})
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			extend()
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case evt, ok := <-sub.events:
+			if !ok {
+				return // 느린 연결로 끊김, 클라이언트가 재접속
+			}
+			if evt.Type == EventNotification && evt.ID <= lastID {
+				continue
+			}
+			extend()
+			if err := writeStreamEvent(w, evt); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeStreamEvent SSE 형식으로 이벤트 기록 (알림 이벤트만 id를 붙여 Last-Event-ID 기준으로 사용)
+func writeStreamEvent(w io.Writer, evt StreamEvent) error {
+	if evt.ID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", evt.ID); err != nil {
+			return err
+		}
+	}
+	data := evt.Data
+	if len(data) == 0 {
+		data = json.RawMessage(`{}`)
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Type, data)
+	return err
+}
+
+// lastEventID 재접속 기준 알림 ID (EventSource는 Last-Event-ID 헤더, 그 외 클라이언트는 쿼리)
+func lastEventID(r *http.Request) int64 {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
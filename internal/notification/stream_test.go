@@ -0,0 +1,201 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/example/LottoSmash/internal/auth"
+	"github.com/example/LottoSmash/internal/logger"
+)
+
+func newTestHub(t *testing.T) *Hub {
+	t.Helper()
+	lg, err := logger.New(t.TempDir(), "debug")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { lg.Close() })
+	return NewHub(lg)
+}
+
+func receive(t *testing.T, sub *streamSub) (StreamEvent, bool) {
+	t.Helper()
+	select {
+	case evt, ok := <-sub.events:
+		return evt, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return StreamEvent{}, false
+	}
+}
+
+func TestHubFanOut(t *testing.T) {
+	hub := newTestHub(t)
+	a1, _ := hub.Subscribe(1)
+	a2, _ := hub.Subscribe(1)
+	b, _ := hub.Subscribe(2)
+
+	hub.Publish(context.Background(), StreamEvent{ID: 10, Type: EventNotification, UserID: 1})
+	for _, sub := range []*streamSub{a1, a2} {
+		if evt, _ := receive(t, sub); evt.ID != 10 {
+			t.Errorf("user 1 stream got %+v", evt)
+		}
+	}
+	if len(b.events) != 0 {
+		t.Error("user 2 must not receive user 1 events")
+	}
+
+	// UserID 0은 모든 연결에 전달
+	hub.Publish(context.Background(), StreamEvent{Type: EventDrawResult})
+	for _, sub := range []*streamSub{a1, a2, b} {
+		if evt, _ := receive(t, sub); evt.Type != EventDrawResult {
+			t.Errorf("got %+v, want draw result", evt)
+		}
+	}
+
+	hub.Unsubscribe(a2)
+	if _, ok := <-a2.events; ok {
+		t.Error("unsubscribed stream should be closed")
+	}
+	if hub.Connections() != 2 {
+		t.Errorf("connections = %d, want 2", hub.Connections())
+	}
+}
+
+func TestHubLimitsAndSlowStreams(t *testing.T) {
+	hub := newTestHub(t)
+	var subs []*streamSub
+	for i := 0; i < MaxStreamsPerUser; i++ {
+		sub, err := hub.Subscribe(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subs = append(subs, sub)
+	}
+	if _, err := hub.Subscribe(1); err != ErrTooManyStreams {
+		t.Errorf("err = %v, want ErrTooManyStreams", err)
+	}
+
+	// 버퍼를 넘기면 끊김
+	for i := 0; i <= streamBuffer; i++ {
+		hub.Publish(context.Background(), StreamEvent{Type: EventJobFinished, UserID: 1})
+	}
+	if hub.Connections() != 0 {
+		t.Errorf("slow streams should be dropped, %d left", hub.Connections())
+	}
+	for range subs[0].events {
+	}
+}
+
+// captureBackplane 발행한 payload를 기록하고 그대로 Hub로 돌려주는 백플레인
+type captureBackplane struct {
+	mu       sync.Mutex
+	hub      *Hub
+	payloads [][]byte
+}
+
+func (c *captureBackplane) Publish(_ context.Context, payload []byte) error {
+	c.mu.Lock()
+	c.payloads = append(c.payloads, payload)
+	c.mu.Unlock()
+
+	var evt StreamEvent
+	if err := json.Unmarshal(payload, &evt); err != nil {
+		return err
+	}
+	c.hub.deliver(evt)
+	return nil
+}
+
+func TestHubBackplanePayloadLimit(t *testing.T) {
+	hub := newTestHub(t)
+	bp := &captureBackplane{hub: hub}
+	hub.SetBackplane(bp)
+	sub, _ := hub.Subscribe(1)
+
+	big, _ := json.Marshal(map[string]string{"body": strings.Repeat("가", maxNotifyPayload)})
+	hub.Publish(context.Background(), StreamEvent{ID: 5, Type: EventNotification, UserID: 1, Data: big})
+
+	evt, _ := receive(t, sub)
+	if evt.ID != 5 || evt.Data != nil {
+		t.Errorf("oversized event = id %d, %d bytes of data", evt.ID, len(evt.Data))
+	}
+	if len(bp.payloads) != 1 || len(bp.payloads[0]) > maxNotifyPayload {
+		t.Errorf("payloads = %d, size %d", len(bp.payloads), len(bp.payloads[0]))
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	hub := newTestHub(t)
+	h := NewHandler(&Service{hub: hub, log: hub.log})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), auth.UserIDKey, int64(7))
+		h.Stream(w, r.WithContext(ctx))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() string {
+		if !lines.Scan() {
+			t.Fatalf("stream ended: %v", lines.Err())
+		}
+		return lines.Text()
+	}
+	if l := next(); l != "retry: 3000" {
+		t.Fatalf("first line = %q", l)
+	}
+	next()
+
+	for hub.Connections() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	hub.Publish(context.Background(), StreamEvent{Type: EventJobFinished, UserID: 8, Data: json.RawMessage(`{"other":true}`)})
+	hub.Publish(context.Background(), StreamEvent{ID: 42, Type: EventNotification, UserID: 7, Data: json.RawMessage(`{"id":42}`)})
+
+	want := []string{"id: 42", "event: notification", `data: {"id":42}`}
+	for _, w := range want {
+		if l := next(); l != w {
+			t.Errorf("line = %q, want %q", l, w)
+		}
+	}
+
+	cancel()
+	for hub.Connections() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLastEventID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/notifications/stream?last_event_id=12", nil)
+	if id := lastEventID(r); id != 12 {
+		t.Errorf("query id = %d", id)
+	}
+	r.Header.Set("Last-Event-ID", "15")
+	if id := lastEventID(r); id != 15 {
+		t.Errorf("header id = %d", id)
+	}
+	r.Header.Set("Last-Event-ID", "abc")
+	if id := lastEventID(r); id != 0 {
+		t.Errorf("invalid id = %d", id)
+	}
+}
@@ -0,0 +1,377 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	webPushTTL         = 24 * time.Hour // 브라우저가 오프라인일 때 push 서비스가 보관하는 기간
+	webPushJWTLifetime = 12 * time.Hour // VAPID 토큰 유효기간 (최대 24시간)
+	webPushRecordSize  = 4096
+	webPushMaxPayload  = 3993 // 4096 - 헤더(86) - 태그(16) - 구분자(1)
+)
+
+// WebPushSubscription 브라우저 PushSubscription.toJSON() 결과
+type WebPushSubscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// webPushHosts 구독 endpoint로 허용하는 브라우저 push 서비스 호스트 (하위 도메인 포함)
+var webPushHosts = []string{
+	"fcm.googleapis.com",        // Chrome, Edge(Chromium), Opera
+	"push.services.mozilla.com", // Firefox
+	"notify.windows.com",        // Edge(레거시) WNS
+	"push.apple.com",            // Safari
+}
+
+// isWebPushHost 알려진 push 서비스의 기본 포트 https 호스트인지 확인
+func isWebPushHost(u *url.URL) bool {
+	if u.Scheme != "https" || (u.Port() != "" && u.Port() != "443") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range webPushHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 구독 정보 검증 (알려진 push 서비스 https endpoint, P-256 공개키 65바이트, auth 비밀값 16바이트)
+func (s *WebPushSubscription) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("subscription endpoint must be an https URL")
+	}
+	if !isWebPushHost(u) {
+		return errors.New("subscription endpoint must belong to a known push service")
+	}
+	if key, err := decodeBase64URL(s.Keys.P256dh); err != nil || len(key) != 65 {
+		return errors.New("subscription keys.p256dh must be a base64url P-256 public key")
+	}
+	if secret, err := decodeBase64URL(s.Keys.Auth); err != nil || len(secret) != 16 {
+		return errors.New("subscription keys.auth must be a base64url 16-byte secret")
+	}
+	return nil
+}
+
+// GenerateVAPIDKeys VAPID 키 쌍 생성 (base64url 인코딩된 공개키 65바이트, 개인키 32바이트)
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(key.PublicKey().Bytes()), enc.EncodeToString(key.Bytes()), nil
+}
+
+// WebPushSender Web Push 프로토콜 발송기
+// 페이로드는 RFC 8291(aes128gcm)로 암호화하고, VAPID(RFC 8292) 토큰으로 발신자를 인증한다.
+type WebPushSender struct {
+	publicKey string // base64url, Authorization 헤더의 k 값
+	key       *ecdsa.PrivateKey
+	subject   string // mailto: 또는 https: 연락처
+	client    *http.Client
+	now       func() time.Time
+	allowHost func(*url.URL) bool // 발송 직전 endpoint 재검증 (등록 이후 허용 목록이 바뀐 구독 차단)
+
+	mu     sync.Mutex
+	tokens map[string]vapidToken // push 서비스 origin별 VAPID 토큰
+}
+
+type vapidToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// NewWebPushSender VAPID 키(base64url)로 발송기 생성
+func NewWebPushSender(publicKey, privateKey, subject string) (*WebPushSender, error) {
+	if subject == "" {
+		return nil, errors.New("webpush subject is required (mailto: or https: contact)")
+	}
+	raw, err := decodeBase64URL(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode vapid private key: %w", err)
+	}
+	priv, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse vapid private key: %w", err)
+	}
+	pub := priv.PublicKey().Bytes()
+	if publicKey != base64.RawURLEncoding.EncodeToString(pub) {
+		return nil, errors.New("vapid public key does not match private key")
+	}
+
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+
+	return &WebPushSender{
+		publicKey: publicKey,
+		key:       key,
+		subject:   subject,
+		client:    newWebPushClient(),
+		now:       time.Now,
+		allowHost: isWebPushHost,
+		tokens:    make(map[string]vapidToken),
+	}, nil
+}
+
+// newWebPushClient push 서비스 전용 HTTP 클라이언트
+// 사용자가 등록한 endpoint로 요청하므로 리다이렉트를 따르지 않고, 내부망 주소로는 연결하지 않는다.
+func newWebPushClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("webpush endpoint resolves to non-public address %s", host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublicIP 루프백/사설/링크로컬/미지정 주소가 아닌지 확인
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// PublicKey 브라우저 구독(applicationServerKey)에 사용할 공개키
+func (s *WebPushSender) PublicKey() string {
+	return s.publicKey
+}
+
+// Send 구독 endpoint로 암호화된 알림 발송
+func (s *WebPushSender) Send(ctx context.Context, token DeviceToken, msg PushMessage) error {
+	sub := token.Subscription
+	if sub == nil {
+		return &PushError{Provider: "webpush", Code: "NoSubscription", Message: "device token has no web push subscription", Err: ErrInvalidToken}
+	}
+
+	notification := map[string]interface{}{
+		"title": msg.Title,
+		"body":  msg.Body,
+		"data":  msg.Data,
+		"tag":   msg.CollapseID,
+	}
+	if msg.Badge != nil {
+		notification["badge_count"] = *msg.Badge // 서비스 워커에서 navigator.setAppBadge로 표시
+	}
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("marshal webpush payload: %w", err)
+	}
+	body, err := encryptWebPush(payload, sub)
+	if err != nil {
+		return &PushError{Provider: "webpush", Code: "EncryptionFailed", Message: err.Error(), Err: ErrInvalidPayload}
+	}
+
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil {
+		return &PushError{Provider: "webpush", Code: "BadEndpoint", Message: err.Error(), Err: ErrInvalidToken}
+	}
+	if !s.allowHost(endpoint) {
+		return &PushError{Provider: "webpush", Code: "BadEndpoint", Message: "endpoint is not a known push service", Err: ErrInvalidToken}
+	}
+	vapid, err := s.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", vapid, s.publicKey))
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", fmt.Sprintf("%d", int(webPushTTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+	if msg.CollapseID != "" {
+		req.Header.Set("Topic", msg.CollapseID)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webpush send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	pushErr := &PushError{
+		Provider:   "webpush",
+		StatusCode: resp.StatusCode,
+		Code:       http.StatusText(resp.StatusCode),
+		Message:    strings.TrimSpace(string(raw)),
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		// 구독이 만료되었거나 사용자가 해지함
+		pushErr.Err = ErrTokenUnregistered
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		// 헤더/페이로드 문제 (구독이 잘못된 경우는 404/410으로 응답)
+		pushErr.Err = ErrInvalidPayload
+	}
+	return pushErr
+}
+
+// vapidToken push 서비스 origin별 VAPID JWT (만료 1시간 전까지 재사용)
+func (s *WebPushSender) vapidToken(audience string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if t, ok := s.tokens[audience]; ok && now.Add(time.Hour).Before(t.expiresAt) {
+		return t.value, nil
+	}
+
+	expiresAt := now.Add(webPushJWTLifetime)
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": audience,
+		"exp": expiresAt.Unix(),
+		"sub": s.subject,
+	}).SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("sign vapid token: %w", err)
+	}
+
+	s.tokens[audience] = vapidToken{value: signed, expiresAt: expiresAt}
+	return signed, nil
+}
+
+// encryptWebPush RFC 8291 페이로드 암호화 (단일 aes128gcm 레코드)
+// 본문 = salt(16) || rs(4) || idlen(1) || 서버 임시 공개키(65) || 암호문
+func encryptWebPush(plaintext []byte, sub *WebPushSubscription) ([]byte, error) {
+	if len(plaintext) > webPushMaxPayload {
+		return nil, fmt.Errorf("payload too large: %d bytes", len(plaintext))
+	}
+	uaPublicRaw, err := decodeBase64URL(sub.Keys.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("decode p256dh: %w", err)
+	}
+	authSecret, err := decodeBase64URL(sub.Keys.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("decode auth: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, fmt.Errorf("parse p256dh: %w", err)
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encryptWebPushWith(plaintext, uaPublic, authSecret, asPrivate, salt)
+}
+
+// encryptWebPushWith 임시 키와 salt를 지정한 암호화 (RFC 8291 예제 검증용)
+func encryptWebPushWith(plaintext []byte, uaPublic *ecdh.PublicKey, authSecret []byte, asPrivate *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	// IKM = HKDF(auth_secret, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public)
+	keyInfo := "WebPush: info\x00" + string(uaPublic.Bytes()) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// 마지막 레코드 구분자 0x02
+	record := append(append([]byte{}, plaintext...), 0x02)
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// decodeBase64URL 패딩 유무와 관계없이 base64url 디코딩
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,253 @@
+package notification
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := decodeBase64URL(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// RFC 8291 Appendix A 예제
+func TestEncryptWebPushRFC8291Vector(t *testing.T) {
+	asPrivate, err := ecdh.P256().NewPrivateKey(mustDecode(t, "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(mustDecode(t, "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := encryptWebPushWith([]byte("When I grow up, I want to be a watermelon"), uaPublic,
+		mustDecode(t, "BTBZMqHH6r4Tts7J_aSIgg"), asPrivate, mustDecode(t, "DGv6ra1nlYgDCS1FRnbzlw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if got := base64.RawURLEncoding.EncodeToString(body); got != want {
+		t.Errorf("body = %s\nwant   %s", got, want)
+	}
+}
+
+// decryptWebPush 브라우저 측 복호화 (테스트용)
+func decryptWebPush(t *testing.T, body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) []byte {
+	t.Helper()
+	salt, rs, idLen := body[:16], binary.BigEndian.Uint32(body[16:20]), int(body[20])
+	if rs != webPushRecordSize || idLen != 65 {
+		t.Fatalf("header rs=%d idlen=%d", rs, idLen)
+	}
+	asPublic, err := ecdh.P256().NewPublicKey(body[21 : 21+idLen])
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, _ := uaPrivate.ECDH(asPublic)
+	keyInfo := "WebPush: info\x00" + string(uaPrivate.PublicKey().Bytes()) + string(asPublic.Bytes())
+	ikm, _ := hkdf.Key(sha256.New, secret, authSecret, keyInfo, 32)
+	cek, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if plain[len(plain)-1] != 0x02 {
+		t.Fatalf("missing last record delimiter")
+	}
+	return plain[:len(plain)-1]
+}
+
+func TestWebPushSenderSend(t *testing.T) {
+	publicKey, privateKey, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := NewWebPushSender(publicKey, privateKey, "mailto:test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uaPrivate, _ := ecdh.P256().GenerateKey(rand.Reader)
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+
+	var received []byte
+	var header http.Header
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		received, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	sender.client = srv.Client()
+	sender.allowHost = func(u *url.URL) bool { return u.Host == strings.TrimPrefix(srv.URL, "https://") }
+
+	sub := &WebPushSubscription{Endpoint: "https://fcm.googleapis.com/fcm/send/abc"}
+	sub.Keys.P256dh = base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes())
+	sub.Keys.Auth = base64.RawURLEncoding.EncodeToString(authSecret)
+	if err := sub.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	sub.Endpoint = srv.URL + "/push/abc"
+
+	msg := PushMessage{Title: "당첨!", Body: "3개 일치", Data: map[string]string{"draw_no": "1200"}, CollapseID: "winning-1200"}
+	if err := sender.Send(context.Background(), DeviceToken{Token: sub.Endpoint, Platform: PlatformWeb, Subscription: sub}, msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	if header.Get("Content-Encoding") != "aes128gcm" || header.Get("Topic") != "winning-1200" || header.Get("TTL") == "" {
+		t.Errorf("headers = %v", header)
+	}
+
+	// Authorization: vapid t=<jwt>, k=<공개키>
+	var vapidJWT, vapidKey string
+	for _, part := range strings.Split(strings.TrimPrefix(header.Get("Authorization"), "vapid "), ", ") {
+		if v, ok := strings.CutPrefix(part, "t="); ok {
+			vapidJWT = v
+		} else if v, ok := strings.CutPrefix(part, "k="); ok {
+			vapidKey = v
+		}
+	}
+	if vapidKey != publicKey {
+		t.Errorf("k = %s, want %s", vapidKey, publicKey)
+	}
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(vapidJWT, claims, func(*jwt.Token) (interface{}, error) {
+		return &sender.key.PublicKey, nil
+	}); err != nil {
+		t.Fatalf("vapid jwt: %v", err)
+	}
+	if claims["aud"] != srv.URL || claims["sub"] != "mailto:test@example.com" {
+		t.Errorf("claims = %v", claims)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(decryptWebPush(t, received, uaPrivate, authSecret), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["title"] != "당첨!" || payload["tag"] != "winning-1200" {
+		t.Errorf("payload = %v", payload)
+	}
+
+	gone := *sub
+	gone.Endpoint = srv.URL + "/gone"
+	err = sender.Send(context.Background(), DeviceToken{Platform: PlatformWeb, Subscription: &gone}, msg)
+	if !errors.Is(err, ErrTokenUnregistered) {
+		t.Errorf("gone: err = %v, want ErrTokenUnregistered", err)
+	}
+}
+
+func TestWebPushSenderRejectsInternalEndpoints(t *testing.T) {
+	publicKey, privateKey, _ := GenerateVAPIDKeys()
+	sender, err := NewWebPushSender(publicKey, privateKey, "mailto:test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uaPrivate, _ := ecdh.P256().GenerateKey(rand.Reader)
+	sub := &WebPushSubscription{Endpoint: "https://169.254.169.254/latest/meta-data"}
+	sub.Keys.P256dh = base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes())
+	sub.Keys.Auth = base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+
+	// 허용 목록 밖 endpoint는 요청 없이 잘못된 토큰으로 처리
+	err = sender.Send(context.Background(), DeviceToken{Platform: PlatformWeb, Subscription: sub}, PushMessage{Title: "t"})
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("metadata endpoint: err = %v, want ErrInvalidToken", err)
+	}
+
+	// 허용 호스트가 내부 주소로 해석되어도 연결하지 않음
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached loopback server")
+	}))
+	defer srv.Close()
+	if _, err := newWebPushClient().Get(srv.URL); err == nil || !strings.Contains(err.Error(), "non-public address") {
+		t.Errorf("loopback dial: err = %v", err)
+	}
+
+	if err := sender.client.CheckRedirect(nil, nil); !errors.Is(err, http.ErrUseLastResponse) {
+		t.Errorf("redirects should not be followed: %v", err)
+	}
+}
+
+func TestNewWebPushSenderKeyMismatch(t *testing.T) {
+	publicKey, _, _ := GenerateVAPIDKeys()
+	_, privateKey, _ := GenerateVAPIDKeys()
+	if _, err := NewWebPushSender(publicKey, privateKey, "mailto:a@b.c"); err == nil {
+		t.Error("mismatched keys should fail")
+	}
+}
+
+func TestValidateRegisterTokenRequest(t *testing.T) {
+	sub := &WebPushSubscription{Endpoint: "https://fcm.googleapis.com/fcm/send/abc"}
+	sub.Keys.P256dh = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	sub.Keys.Auth = "BTBZMqHH6r4Tts7J_aSIgg=="
+
+	req := RegisterTokenRequest{Platform: PlatformWeb, Subscription: sub}
+	if err := validateRegisterTokenRequest(&req); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if req.Token != sub.Endpoint {
+		t.Errorf("token = %q, want endpoint", req.Token)
+	}
+
+	req = RegisterTokenRequest{Token: "fcm-token"}
+	if err := validateRegisterTokenRequest(&req); err != nil || req.Platform != PlatformAndroid {
+		t.Errorf("default platform: err = %v, platform = %q", err, req.Platform)
+	}
+
+	bad := *sub
+	bad.Endpoint = "http://fcm.googleapis.com/fcm/send/abc"
+	unknown := *sub
+	unknown.Endpoint = "https://push.example.com/abc"
+	metadata := *sub
+	metadata.Endpoint = "https://169.254.169.254/latest/meta-data"
+	lookalike := *sub
+	lookalike.Endpoint = "https://fcm.googleapis.com.attacker.example/abc"
+	otherPort := *sub
+	otherPort.Endpoint = "https://fcm.googleapis.com:8443/fcm/send/abc"
+	invalid := []RegisterTokenRequest{
+		{Platform: PlatformWeb},
+		{Platform: PlatformWeb, Subscription: &bad},
+		{Platform: PlatformWeb, Subscription: &unknown},
+		{Platform: PlatformWeb, Subscription: &metadata},
+		{Platform: PlatformWeb, Subscription: &lookalike},
+		{Platform: PlatformWeb, Subscription: &otherPort},
+		{Platform: PlatformIOS},
+		{Platform: "blackberry", Token: "x"},
+	}
+	for i, r := range invalid {
+		if err := validateRegisterTokenRequest(&r); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/example/LottoSmash/internal/config"
@@ -10,12 +11,18 @@ import (
 	"github.com/example/LottoSmash/internal/notification"
 )
 
+// presetLeadTime 판매 마감 몇 시간 전부터 자동 생성 프리셋을 실행할지 (토요일 12시)
+const presetLeadTime = 8 * time.Hour
+
 type Scheduler struct {
 	tz       *time.Location
 	log      *logger.Logger
 	lottoSvc *lotto.Service
 	notifSvc *notification.Service
 	quit     chan struct{}
+
+	tokenExpiryDays int
+	drawNotif       config.DrawNotificationConfig
 }
 
 func New(cfg config.Config, log *logger.Logger, lottoSvc *lotto.Service, notifSvc *notification.Service) (*Scheduler, error) {
@@ -29,12 +36,17 @@ func New(cfg config.Config, log *logger.Logger, lottoSvc *lotto.Service, notifSv
 		lottoSvc: lottoSvc,
 		notifSvc: notifSvc,
 		quit:     make(chan struct{}),
+
+		tokenExpiryDays: cfg.PushQueue.TokenExpiryDays,
+		drawNotif:       cfg.DrawNotifications,
 	}, nil
 }
 
 func (s *Scheduler) Start(ctx context.Context) {
 	go s.loopDaily(ctx)
 	go s.loopWeekly(ctx)
+	go s.loopPresets(ctx)
+	go s.loopReminder(ctx)
 	go s.loopMonthly(ctx)
 	go s.loopYearly(ctx)
 }
@@ -53,7 +65,7 @@ func (s *Scheduler) loopDaily(ctx context.Context) {
 			if now.Hour() == 6 && now.Minute() == 0 && now.Day() != lastDay {
 				lastDay = now.Day()
 				s.log.Infof("running daily job at %s", now)
-				go s.runDaily(now)
+				go s.runDaily(ctx, now)
 			}
 		}
 	}
@@ -81,6 +93,62 @@ func (s *Scheduler) loopWeekly(ctx context.Context) {
 	}
 }
 
+func (s *Scheduler) loopPresets(ctx context.Context) {
+	if s.lottoSvc == nil {
+		return
+	}
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	var running atomic.Bool
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Infof("preset scheduler stopping")
+			return
+		case <-ticker.C:
+			now := time.Now().In(s.tz)
+			// 토요일 12시부터 판매 마감(20시) 전까지 매 틱 실행
+			// (남은 프리셋만 조회하고 프리셋별 행 잠금으로 중복을 막으므로, 실패/중단된 프리셋은 다음 틱에 재시도됨)
+			drawNo := lotto.NextDrawNo(now)
+			cutoff := lotto.DrawSalesCutoff(drawNo)
+			if !now.Before(cutoff.Add(-presetLeadTime)) && now.Before(cutoff) && running.CompareAndSwap(false, true) {
+				go func() {
+					defer running.Store(false)
+					s.runPresets(ctx, drawNo)
+				}()
+			}
+		}
+	}
+}
+
+func (s *Scheduler) loopReminder(ctx context.Context) {
+	if !s.drawNotif.ReminderEnabled {
+		return
+	}
+	before := time.Duration(s.drawNotif.ReminderMinutesBefore) * time.Minute
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	var lastDrawNo int
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Infof("reminder scheduler stopping")
+			return
+		case <-ticker.C:
+			now := time.Now().In(s.tz)
+			// 판매 마감(토요일 20시) reminderMinutesBefore분 전부터 마감 전까지 한 번 발송
+			// (서버가 늦게 떠도 마감 전이면 발송, 중복은 회차별 선점으로 막음)
+			drawNo := lotto.NextDrawNo(now)
+			cutoff := lotto.DrawSalesCutoff(drawNo)
+			if !now.Before(cutoff.Add(-before)) && now.Before(cutoff) && drawNo != lastDrawNo {
+				lastDrawNo = drawNo
+				s.log.Infof("running draw %d reminder job at %s", drawNo, now)
+				go s.runReminder(ctx, drawNo)
+			}
+		}
+	}
+}
+
 func (s *Scheduler) loopMonthly(ctx context.Context) {
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
@@ -125,8 +193,15 @@ func (s *Scheduler) loopYearly(ctx context.Context) {
 	}
 }
 
-func (s *Scheduler) runDaily(t time.Time) {
+func (s *Scheduler) runDaily(ctx context.Context, t time.Time) {
 	s.log.Infof("daily job executed at %s", t)
+
+	// 오래 등록되지 않은 디바이스 토큰 정리
+	if s.notifSvc != nil {
+		if _, err := s.notifSvc.ExpireStaleTokens(ctx, s.tokenExpiryDays); err != nil {
+			s.log.Errorf("failed to expire stale device tokens: %v", err)
+		}
+	}
 }
 
 func (s *Scheduler) runWeekly(ctx context.Context) {
@@ -158,12 +233,44 @@ func (s *Scheduler) runWeekly(ctx context.Context) {
 			if err := s.notifSvc.ProcessNewDraw(ctx, latestDrawNo); err != nil {
 				s.log.Errorf("failed to process winning checks for draw %d: %v", latestDrawNo, err)
 			}
+			// 접속 중인 클라이언트에 당첨번호 전달 (실시간 스트림)
+			if err := s.notifSvc.PublishDrawResult(ctx, latestDrawNo); err != nil {
+				s.log.Errorf("failed to publish draw %d result event: %v", latestDrawNo, err)
+			}
+			// 당첨번호 발표 알림 (배치는 워커 풀에서 처리되어 바로 반환)
+			if s.drawNotif.BroadcastEnabled {
+				if err := s.notifSvc.BroadcastDrawPublished(ctx, latestDrawNo); err != nil {
+					s.log.Errorf("failed to broadcast draw %d: %v", latestDrawNo, err)
+				}
+			}
 		}
 	}
 
 	s.log.Infof("weekly lotto job completed")
 }
 
+func (s *Scheduler) runPresets(ctx context.Context, drawNo int) {
+	if s.lottoSvc == nil {
+		s.log.Infof("lotto service not initialized, skipping preset job")
+		return
+	}
+
+	if _, err := s.lottoSvc.RunAutoPresets(ctx, drawNo); err != nil {
+		s.log.Errorf("failed to run auto presets: %v", err)
+	}
+}
+
+func (s *Scheduler) runReminder(ctx context.Context, drawNo int) {
+	if s.notifSvc == nil {
+		s.log.Infof("notification service not initialized, skipping reminder job")
+		return
+	}
+
+	if err := s.notifSvc.SendDrawReminders(ctx, drawNo); err != nil {
+		s.log.Errorf("failed to send draw %d reminders: %v", drawNo, err)
+	}
+}
+
 func (s *Scheduler) runMonthly(t time.Time) {
 	s.log.Infof("monthly job executed at %s", t)
 }
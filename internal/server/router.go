@@ -31,13 +31,18 @@ type Dependencies struct {
 }
 
 func NewRouter(deps Dependencies) http.Handler {
-	r := chi.NewRouter()
+	root := chi.NewRouter()
 
-	r.Use(middleware.TxID())
-	r.Use(middleware.Recover(deps.Logger))
-	r.Use(middleware.Logging(deps.Logger))
-	r.Use(middleware.ConcurrencyLimit(deps.ConfigMgr.Config().Concurrency.MaxConcurrentRequests))
-	r.Use(middleware.Timeout(deps.ConfigMgr))
+	root.Use(middleware.TxID())
+	root.Use(middleware.Recover(deps.Logger))
+	root.Use(middleware.Logging(deps.Logger))
+
+	// 일반 요청: 동시 요청 제한 + 요청 타임아웃
+	// 연결을 계속 유지하는 스트림은 root에 직접 연결하여 두 미들웨어에서 제외한다.
+	r := root.With(
+		middleware.ConcurrencyLimit(deps.ConfigMgr.Config().Concurrency.MaxConcurrentRequests),
+		middleware.Timeout(deps.ConfigMgr),
+	)
 
 	// health
 	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
@@ -103,17 +108,75 @@ func NewRouter(deps Dependencies) http.Handler {
 				r.Get("/stats/analysis", lottoHandler.GetAnalysisStats)
 				r.Get("/stats/analysis/history", lottoHandler.GetAnalysisStatsHistory)
 				r.Get("/stats/analysis/{drawNo}", lottoHandler.GetAnalysisStatsByDrawNo)
+				r.Get("/stats/decay", lottoHandler.GetDecayStats)
 
 				// 추천 기능
 				r.Get("/methods", lottoHandler.GetMethods)
 				r.Get("/combine-methods", lottoHandler.GetCombineMethods)
-				r.Post("/recommend", lottoHandler.RecommendNumbers)
+				r.With(authMiddleware.OptionalAuth).Post("/recommend", lottoHandler.RecommendNumbers)
+				r.Post("/score", lottoHandler.ScoreTickets)
+				r.Post("/replay", lottoHandler.ReplayTickets)
+
+				// 사용자 추천 기록, 전략 프리셋, 구매 용지, 대량 작업 (인증 필요)
+				r.Group(func(r chi.Router) {
+					r.Use(authMiddleware.RequireAuth)
+					r.Get("/recommendations", lottoHandler.GetRecommendationHistory)
+					r.Get("/presets", lottoHandler.GetPresets)
+					r.Post("/presets", lottoHandler.CreatePreset)
+					r.Get("/presets/{id}", lottoHandler.GetPreset)
+					r.Put("/presets/{id}", lottoHandler.UpdatePreset)
+					r.Delete("/presets/{id}", lottoHandler.DeletePreset)
+					r.Post("/presets/{id}/run", lottoHandler.RunPreset)
+
+					// 구매 용지 지갑
+					r.Get("/tickets", lottoHandler.GetTickets)
+					r.Post("/tickets", lottoHandler.CreateTicket)
+					r.Post("/tickets/qr", lottoHandler.ImportTicketQR)
+					r.Delete("/tickets/{id}", lottoHandler.DeleteTicket)
+
+					// 대량 추천 작업
+					r.Get("/jobs", lottoHandler.GetBulkJobs)
+					r.Post("/jobs", lottoHandler.SubmitBulkJob)
+					r.Get("/jobs/{id}", lottoHandler.GetBulkJob)
+					r.Get("/jobs/{id}/result", lottoHandler.GetBulkJobResult)
+					r.Post("/jobs/{id}/cancel", lottoHandler.CancelBulkJob)
+
+					// 배정된 A/B 실험 변형
+					r.Get("/experiment", lottoHandler.GetMyExperiment)
+				})
 			})
 
 			// admin lotto routes (protected)
 			r.Route("/api/admin/lotto", func(r chi.Router) {
 				r.Use(authMiddleware.RequireAuth)
 				r.Post("/sync", lottoHandler.TriggerSync)
+
+				// 관리자 전용 (config admin.userIds)
+				r.Group(func(r chi.Router) {
+					r.Use(authMiddleware.RequireAdmin)
+
+					// ML 모델 관리
+					r.Post("/ml/train", lottoHandler.TrainMLModel)
+					r.Get("/ml/models", lottoHandler.GetMLModels)
+					r.Post("/ml/models/{version}/activate", lottoHandler.ActivateMLModel)
+
+					// 분석 기법/조합 방법 레지스트리 관리
+					r.Get("/methods", lottoHandler.GetMethodRegistry)
+					r.Post("/methods/reload", lottoHandler.ReloadMethodRegistry)
+					r.Post("/methods/{kind}", lottoHandler.CreateMethod)
+					r.Put("/methods/{kind}/order", lottoHandler.ReorderMethods)
+					r.Put("/methods/{kind}/{code}", lottoHandler.UpdateMethod)
+					r.Delete("/methods/{kind}/{code}", lottoHandler.DeleteMethod)
+
+					// 추천 A/B 실험
+					r.Get("/experiments", lottoHandler.GetExperiments)
+					r.Post("/experiments", lottoHandler.CreateExperiment)
+					r.Get("/experiments/{id}", lottoHandler.GetExperiment)
+					r.Put("/experiments/{id}", lottoHandler.UpdateExperiment)
+					r.Post("/experiments/{id}/start", lottoHandler.StartExperiment)
+					r.Post("/experiments/{id}/stop", lottoHandler.StopExperiment)
+					r.Get("/experiments/{id}/report", lottoHandler.GetExperimentReport)
+				})
 			})
 		}
 
@@ -125,13 +188,37 @@ func NewRouter(deps Dependencies) http.Handler {
 				r.Use(authMiddleware.RequireAuth)
 				r.Post("/device-token", notifHandler.RegisterDeviceToken)
 				r.Delete("/device-token", notifHandler.DeleteDeviceToken)
+				r.Get("/webpush/public-key", notifHandler.GetWebPushKey)
+				r.Get("/preferences", notifHandler.GetPreferences)
+				r.Put("/preferences", notifHandler.UpdatePreferences)
 				r.Get("/", notifHandler.GetNotifications)
 				r.Get("/winnings", notifHandler.GetWinnings)
+
+				// 알림함 읽음/보관 상태
+				r.Get("/unread-count", notifHandler.GetUnreadCount)
+				r.Post("/read-all", notifHandler.MarkAllRead)
+				r.Post("/{id}/read", notifHandler.MarkRead)
+				r.Post("/{id}/archive", notifHandler.ArchiveNotification)
+				r.Delete("/{id}", notifHandler.DeleteNotification)
+			})
+
+			// 실시간 알림 스트림 (SSE, access_token 쿼리 허용, 요청 타임아웃/동시 요청 제한 제외)
+			root.With(authMiddleware.RequireAuthStream).Get("/api/notifications/stream", notifHandler.Stream)
+
+			// 이메일 수신 거부 (서명 토큰으로 인증, public)
+			r.Get("/api/notifications/email/unsubscribe", notifHandler.EmailUnsubscribe)
+			r.Post("/api/notifications/email/unsubscribe", notifHandler.EmailUnsubscribe)
+
+			// admin notification routes (관리자 전용, config admin.userIds)
+			r.Route("/api/admin/notifications", func(r chi.Router) {
+				r.Use(authMiddleware.RequireAdmin)
+				r.Get("/deliveries", notifHandler.GetDeliveries)
+				r.Post("/deliveries/requeue", notifHandler.RequeueDeliveries)
 			})
 		}
 	}
 
-	return r
+	return root
 }
 
 func setupAuth(deps Dependencies) *auth.Handler {
@@ -176,5 +263,7 @@ func setupAuthMiddleware(deps Dependencies) *auth.Middleware {
 	}
 	jwtManager := auth.NewJWTManager(jwtConfig)
 
-	return auth.NewMiddleware(jwtManager)
+	middleware := auth.NewMiddleware(jwtManager)
+	middleware.SetAdminUserIDs(cfg.Admin.UserIDs)
+	return middleware
 }
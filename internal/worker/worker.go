@@ -2,6 +2,8 @@ package worker
 
 import (
     "context"
+    "errors"
+    "fmt"
     "time"
 
     "github.com/example/LottoSmash/internal/logger"
@@ -12,13 +14,18 @@ type JobType int
 
 const (
     JobTypeExample JobType = iota
+    JobTypeTask    // Task 함수 실행 (비동기 작업)
 )
 
+// TaskFunc 작업 본문. ctx는 Job.Ctx 취소 또는 워커 종료 시 취소된다.
+type TaskFunc func(ctx context.Context) (interface{}, error)
+
 type Job struct {
     Type   JobType
     TxID   string
     Ctx    context.Context
     Input  interface{}
+    Task   TaskFunc // JobTypeTask 전용
     Result chan Result
 }
 
@@ -56,6 +63,12 @@ func handleMainJob(ctx context.Context, log *logger.Logger, pools *Pools, job Jo
         tx = txid.NewID()
     }
     log.Debugf("handling main job type=%d tx=%s", job.Type, tx)
+
+    if job.Type == JobTypeTask {
+        handleTaskJob(ctx, log, job, tx)
+        return
+    }
+
     // simple example: echo input with small delay
     select {
     case <-ctx.Done():
@@ -68,6 +81,38 @@ func handleMainJob(ctx context.Context, log *logger.Logger, pools *Pools, job Jo
     }
 }
 
+// handleTaskJob Task 실행 후 결과 전달 (panic은 오류로 변환)
+func handleTaskJob(ctx context.Context, log *logger.Logger, job Job, tx string) {
+    jobCtx := job.Ctx
+    if jobCtx == nil {
+        jobCtx = context.Background()
+    }
+    taskCtx, cancel := context.WithCancel(jobCtx)
+    defer cancel()
+    stop := context.AfterFunc(ctx, cancel)
+    defer stop()
+
+    var res Result
+    func() {
+        defer func() {
+            if p := recover(); p != nil {
+                log.Errorf("task job panic tx=%s: %v", tx, p)
+                res = Result{Err: fmt.Errorf("task panic: %v", p)}
+            }
+        }()
+        if job.Task == nil {
+            res = Result{Err: errors.New("task job without task function")}
+            return
+        }
+        data, err := job.Task(taskCtx)
+        res = Result{Data: data, Err: err}
+    }()
+
+    if job.Result != nil {
+        job.Result <- res
+    }
+}
+
 func StartDBWorkers(ctx context.Context, count int, pools *Pools, log *logger.Logger) {
     for i := 0; i < count; i++ {
         go func(id int) {
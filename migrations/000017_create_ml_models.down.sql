@@ -0,0 +1,2 @@
+DELETE FROM analysis_methods WHERE code = 'ML_MODEL';
+DROP TABLE IF EXISTS lotto_ml_models;
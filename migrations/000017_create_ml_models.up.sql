@@ -0,0 +1,21 @@
+-- 머신러닝 예측 모델 저장 테이블 (버전별)
+CREATE TABLE IF NOT EXISTS lotto_ml_models (
+    id                  SERIAL PRIMARY KEY,
+    version             INTEGER NOT NULL UNIQUE,
+    algorithm           VARCHAR(30) NOT NULL,           -- LOGISTIC 등
+    trained_draw_no     INTEGER NOT NULL,               -- 학습에 사용된 마지막 회차 (마지막 샘플의 정답 회차)
+    sample_count        INTEGER NOT NULL DEFAULT 0,     -- 학습 샘플 수 (회차 x 번호)
+    log_loss            DOUBLE PRECISION,               -- 검증 구간 log loss
+    baseline_log_loss   DOUBLE PRECISION,               -- 균등 확률(6/45) 기준 log loss
+    model_data          JSONB NOT NULL,                 -- 직렬화된 모델 (가중치, 정규화 파라미터)
+    is_active           BOOLEAN NOT NULL DEFAULT false,
+    created_at          TIMESTAMP DEFAULT NOW()
+);
+
+-- 활성 모델은 하나만 허용
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ml_models_active ON lotto_ml_models(is_active) WHERE is_active = true;
+
+-- 분석 방법 등록
+INSERT INTO analysis_methods (code, name, description, category, sort_order) VALUES
+('ML_MODEL', '머신러닝 예측', '통합 분석 통계를 특징으로 학습한 모델의 다음 회차 출현 확률 기반', 'ml', 11)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,7 @@
+-- 018_create_decay_stats.down.sql
+-- 시간 감쇠 가중 빈도 통계 테이블 삭제
+
+DELETE FROM analysis_methods WHERE code = 'DECAY_FREQUENCY';
+
+DROP INDEX IF EXISTS idx_decay_stats_draw;
+DROP TABLE IF EXISTS lotto_decay_stats;
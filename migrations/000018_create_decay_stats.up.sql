@@ -0,0 +1,23 @@
+-- 018_create_decay_stats.sql
+-- 시간 감쇠 가중 빈도 통계 테이블 (반감기별, 회차별, 번호별)
+-- weighted_count(n) = weighted_count(n-1) * 0.5^(1/half_life) + appeared(n)
+
+CREATE TABLE IF NOT EXISTS lotto_decay_stats (
+    half_life       INTEGER NOT NULL,                    -- 반감기 (회차 수)
+    draw_no         INTEGER NOT NULL,                    -- 회차 번호
+    number          INTEGER NOT NULL CHECK (number BETWEEN 1 AND 45),
+    weighted_count  DOUBLE PRECISION NOT NULL DEFAULT 0, -- 감쇠 가중 출현 횟수
+    weight_sum      DOUBLE PRECISION NOT NULL DEFAULT 0, -- 회차 가중치 합계
+    score           DOUBLE PRECISION NOT NULL DEFAULT 0, -- weighted_count / (weight_sum * 6)
+    appeared        BOOLEAN NOT NULL DEFAULT false,      -- 해당 회차 출현 여부
+    calculated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (half_life, draw_no, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_decay_stats_draw ON lotto_decay_stats(draw_no);
+
+-- 분석 방법 등록
+INSERT INTO analysis_methods (code, name, description, category, sort_order) VALUES
+('DECAY_FREQUENCY', '시간 감쇠 빈도', '최근 회차일수록 큰 가중치를 주는 지수 감쇠 출현 빈도 (반감기 지정 가능)', 'frequency', 12)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,6 @@
+-- 019_create_recommend_presets.down.sql
+-- 추천 전략 프리셋 테이블 삭제
+
+DROP INDEX IF EXISTS idx_recommend_presets_auto;
+DROP INDEX IF EXISTS idx_recommend_presets_user;
+DROP TABLE IF EXISTS lotto_recommend_presets;
@@ -0,0 +1,15 @@
+-- 019_create_recommend_presets.sql
+-- 사용자별 추천 전략 프리셋 (저장된 추천 요청, 주간 자동 생성 설정)
+CREATE TABLE IF NOT EXISTS lotto_recommend_presets (
+    id                      BIGSERIAL PRIMARY KEY,
+    user_id                 BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name                    VARCHAR(100) NOT NULL,
+    request                 JSONB NOT NULL,                 -- 저장된 추천 요청 (RecommendRequest)
+    auto_generate           BOOLEAN NOT NULL DEFAULT false, -- 매주 추첨 전 자동 생성 여부
+    last_generated_draw_no  INTEGER,                        -- 마지막 자동 생성 대상 회차
+    created_at              TIMESTAMP DEFAULT NOW(),
+    updated_at              TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recommend_presets_user ON lotto_recommend_presets(user_id);
+CREATE INDEX IF NOT EXISTS idx_recommend_presets_auto ON lotto_recommend_presets(auto_generate) WHERE auto_generate = true;
@@ -0,0 +1,6 @@
+-- 020_add_recommendation_draw_no.down.sql
+-- 추천 기록 대상 회차 제거
+
+DROP INDEX IF EXISTS idx_winning_checks_recommendation;
+DROP INDEX IF EXISTS idx_recommendations_draw;
+ALTER TABLE lotto_recommendations DROP COLUMN IF EXISTS draw_no;
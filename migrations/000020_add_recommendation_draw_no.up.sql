@@ -0,0 +1,25 @@
+-- 020_add_recommendation_draw_no.sql
+-- 추천 기록에 대상 회차 추가 (생성 시점 기준, 토요일 20:00 KST 판매 마감 이후면 다음 회차)
+-- 당첨 확인은 대상 회차로만 수행하고 추천당 한 번만 저장한다.
+
+ALTER TABLE lotto_recommendations ADD COLUMN IF NOT EXISTS draw_no INTEGER;
+
+-- 기존 추천 기록 대상 회차 채우기 (created_at은 DB 세션 시간대 기준)
+UPDATE lotto_recommendations
+SET draw_no = GREATEST(1,
+    FLOOR(EXTRACT(EPOCH FROM (created_at::timestamptz - TIMESTAMPTZ '2002-12-07 20:00:00+09')) / 604800)::INTEGER + 2)
+WHERE draw_no IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_draw ON lotto_recommendations(draw_no);
+
+-- 대상 회차가 아닌 회차로 확인된 결과 제거
+DELETE FROM winning_checks wc
+USING lotto_recommendations r
+WHERE wc.recommendation_id = r.id AND wc.draw_no <> r.draw_no;
+
+-- 같은 추천이 중복 확인된 결과는 가장 먼저 저장된 것만 유지
+DELETE FROM winning_checks wc
+USING winning_checks dup
+WHERE wc.recommendation_id = dup.recommendation_id AND wc.id > dup.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_winning_checks_recommendation ON winning_checks(recommendation_id);
@@ -0,0 +1,11 @@
+-- 021_create_ticket_wallet.down.sql
+-- 구매 용지 테이블 삭제
+
+DELETE FROM winning_checks WHERE ticket_game_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_winning_checks_ticket_game;
+ALTER TABLE winning_checks DROP CONSTRAINT IF EXISTS chk_winning_checks_target;
+ALTER TABLE winning_checks DROP COLUMN IF EXISTS ticket_game_id;
+ALTER TABLE winning_checks ALTER COLUMN recommendation_id SET NOT NULL;
+
+DROP TABLE IF EXISTS lotto_ticket_games;
+DROP TABLE IF EXISTS lotto_tickets;
@@ -0,0 +1,33 @@
+-- 021_create_ticket_wallet.sql
+-- 사용자가 실제 구매한 로또 용지 (수동 입력 / QR 가져오기)
+
+CREATE TABLE IF NOT EXISTS lotto_tickets (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    draw_no     INTEGER NOT NULL,
+    source      VARCHAR(10) NOT NULL DEFAULT 'MANUAL',  -- MANUAL, QR
+    qr_url      TEXT,                                   -- QR 가져오기 시 원본 URL
+    created_at  TIMESTAMP DEFAULT NOW()
+);
+
+-- 용지별 게임 (최대 5게임, A~E)
+CREATE TABLE IF NOT EXISTS lotto_ticket_games (
+    id          BIGSERIAL PRIMARY KEY,
+    ticket_id   BIGINT NOT NULL REFERENCES lotto_tickets(id) ON DELETE CASCADE,
+    slot        CHAR(1) NOT NULL,
+    numbers     INTEGER[] NOT NULL,
+    mode        VARCHAR(10) NOT NULL DEFAULT 'MANUAL',  -- MANUAL, AUTO, SEMI_AUTO
+    UNIQUE (ticket_id, slot)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lotto_tickets_user ON lotto_tickets(user_id, draw_no DESC);
+CREATE INDEX IF NOT EXISTS idx_lotto_tickets_draw ON lotto_tickets(draw_no);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lotto_tickets_qr ON lotto_tickets(user_id, qr_url) WHERE qr_url IS NOT NULL;
+
+-- 당첨 확인 대상: 추천 기록 또는 구매 게임 중 하나
+ALTER TABLE winning_checks ALTER COLUMN recommendation_id DROP NOT NULL;
+ALTER TABLE winning_checks ADD COLUMN IF NOT EXISTS ticket_game_id BIGINT REFERENCES lotto_ticket_games(id) ON DELETE CASCADE;
+ALTER TABLE winning_checks ADD CONSTRAINT chk_winning_checks_target
+    CHECK ((recommendation_id IS NULL) <> (ticket_game_id IS NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_winning_checks_ticket_game ON winning_checks(ticket_game_id);
@@ -0,0 +1,7 @@
+-- 022_create_combine_methods.down.sql
+-- 조합 방법 테이블 및 기본 파라미터 컬럼 삭제
+
+ALTER TABLE analysis_methods DROP COLUMN IF EXISTS default_params;
+
+DROP INDEX IF EXISTS idx_combine_methods_sort;
+DROP TABLE IF EXISTS combine_methods;
@@ -0,0 +1,29 @@
+-- 022_create_combine_methods.sql
+-- 조합 방법 메타데이터 테이블 (기존 하드코딩 목록 이전) 및 기법별 기본 파라미터
+
+CREATE TABLE IF NOT EXISTS combine_methods (
+    id              SERIAL PRIMARY KEY,
+    code            VARCHAR(30) NOT NULL UNIQUE,
+    name            VARCHAR(50) NOT NULL,
+    description     TEXT,
+    is_active       BOOLEAN DEFAULT true,
+    sort_order      INTEGER DEFAULT 0,
+    default_params  JSONB NOT NULL DEFAULT '{}',         -- 요청에서 생략한 파라미터의 기본값
+    created_at      TIMESTAMP DEFAULT NOW(),
+    updated_at      TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_combine_methods_sort ON combine_methods(sort_order);
+
+INSERT INTO combine_methods (code, name, description, sort_order, default_params) VALUES
+('SIMPLE_AVG', '단순 평균', '선택한 기법들의 확률을 단순 평균하여 조합', 1, '{}'),
+('WEIGHTED_AVG', '가중 평균', '각 기법별 가중치를 직접 지정하여 평균', 2, '{}'),
+('BAYESIAN_COMBINE', '베이지안 결합', '베이지안 확률 결합으로 두 확률을 보수적으로 조합', 3, '{}'),
+('GEOMETRIC_MEAN', '기하 평균', '확률의 기하 평균으로 낮은 확률에 더 민감하게 반응', 4, '{}'),
+('MIN_MAX', '최대/최소 기반', '낙관적(최대) 또는 보수적(최소) 확률 선택', 5, '{"min_max_mode": "MAX"}')
+ON CONFLICT (code) DO NOTHING;
+
+-- 분석 방법 기본 파라미터
+ALTER TABLE analysis_methods ADD COLUMN IF NOT EXISTS default_params JSONB NOT NULL DEFAULT '{}';
+
+UPDATE analysis_methods SET default_params = '{"half_life": 52}' WHERE code = 'DECAY_FREQUENCY';
@@ -0,0 +1,9 @@
+-- 023_create_recommend_experiments.down.sql
+-- 추천 A/B 실험 테이블 및 배정 컬럼 삭제
+
+DROP INDEX IF EXISTS idx_recommendations_experiment;
+ALTER TABLE lotto_recommendations DROP COLUMN IF EXISTS variant_code;
+ALTER TABLE lotto_recommendations DROP COLUMN IF EXISTS experiment_id;
+
+DROP INDEX IF EXISTS idx_recommend_experiments_running;
+DROP TABLE IF EXISTS recommend_experiments;
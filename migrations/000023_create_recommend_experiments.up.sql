@@ -0,0 +1,23 @@
+-- 023_create_recommend_experiments.sql
+-- 추천 A/B 실험 및 추천 기록별 배정 변형
+
+CREATE TABLE IF NOT EXISTS recommend_experiments (
+    id          BIGSERIAL PRIMARY KEY,
+    key         VARCHAR(50) NOT NULL UNIQUE,                -- 변형 배정 해시에 사용
+    name        VARCHAR(100) NOT NULL,
+    description TEXT,
+    status      VARCHAR(20) NOT NULL DEFAULT 'DRAFT',       -- DRAFT, RUNNING, STOPPED
+    variants    JSONB NOT NULL,                             -- [{code, name, weight, config}] (첫 번째가 대조군)
+    started_at  TIMESTAMP,
+    stopped_at  TIMESTAMP,
+    created_at  TIMESTAMP DEFAULT NOW(),
+    updated_at  TIMESTAMP DEFAULT NOW()
+);
+
+-- 진행 중 실험은 하나만
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recommend_experiments_running ON recommend_experiments(status) WHERE status = 'RUNNING';
+
+ALTER TABLE lotto_recommendations ADD COLUMN IF NOT EXISTS experiment_id BIGINT REFERENCES recommend_experiments(id) ON DELETE SET NULL;
+ALTER TABLE lotto_recommendations ADD COLUMN IF NOT EXISTS variant_code VARCHAR(30);
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_experiment ON lotto_recommendations(experiment_id, variant_code) WHERE experiment_id IS NOT NULL;
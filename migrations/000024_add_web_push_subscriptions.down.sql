@@ -0,0 +1,4 @@
+-- 024_add_web_push_subscriptions.down.sql
+-- Web Push 구독 정보 컬럼 삭제
+
+ALTER TABLE device_tokens DROP COLUMN IF EXISTS subscription;
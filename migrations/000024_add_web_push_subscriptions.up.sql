@@ -0,0 +1,4 @@
+-- 024_add_web_push_subscriptions.sql
+-- 브라우저 Web Push 구독 정보 (platform = 'web'이면 token에 endpoint, subscription에 PushSubscription JSON 저장)
+
+ALTER TABLE device_tokens ADD COLUMN IF NOT EXISTS subscription JSONB;
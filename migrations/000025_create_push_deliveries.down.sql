@@ -0,0 +1,9 @@
+-- 025_create_push_deliveries.down.sql
+-- 푸시 발송 큐 삭제
+
+ALTER TABLE push_notifications DROP COLUMN IF EXISTS thread_id;
+ALTER TABLE push_notifications DROP COLUMN IF EXISTS collapse_id;
+
+DROP INDEX IF EXISTS idx_push_deliveries_status;
+DROP INDEX IF EXISTS idx_push_deliveries_due;
+DROP TABLE IF EXISTS push_deliveries;
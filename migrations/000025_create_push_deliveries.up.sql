@@ -0,0 +1,24 @@
+-- 025_create_push_deliveries.sql
+-- 푸시 발송 큐: 알림(push_notifications)별·디바이스 토큰별 발송 상태
+-- status: pending(대기/재시도 대기) → sending(발송 중, next_attempt_at까지 점유) → sent | dead(최대 시도 초과 또는 영구 오류)
+
+CREATE TABLE IF NOT EXISTS push_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    notification_id  BIGINT NOT NULL REFERENCES push_notifications(id) ON DELETE CASCADE,
+    device_token_id  BIGINT NOT NULL REFERENCES device_tokens(id) ON DELETE CASCADE,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts         INT NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error       TEXT,
+    created_at       TIMESTAMP DEFAULT NOW(),
+    updated_at       TIMESTAMP DEFAULT NOW(),
+    sent_at          TIMESTAMP,
+    UNIQUE(notification_id, device_token_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_deliveries_due ON push_deliveries(next_attempt_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX IF NOT EXISTS idx_push_deliveries_status ON push_deliveries(status, updated_at DESC);
+
+-- 알림 묶음 키 (회차별 collapse/thread ID)
+ALTER TABLE push_notifications ADD COLUMN IF NOT EXISTS collapse_id VARCHAR(64);
+ALTER TABLE push_notifications ADD COLUMN IF NOT EXISTS thread_id VARCHAR(64);
@@ -0,0 +1,7 @@
+-- 026_add_device_token_pruning.down.sql
+-- 디바이스 토큰 정리 컬럼 삭제
+
+DROP INDEX IF EXISTS idx_device_tokens_token;
+DROP INDEX IF EXISTS idx_device_tokens_last_seen;
+ALTER TABLE device_tokens DROP COLUMN IF EXISTS deactivated_reason;
+ALTER TABLE device_tokens DROP COLUMN IF EXISTS last_seen_at;
@@ -0,0 +1,10 @@
+-- 026_add_device_token_pruning.sql
+-- 디바이스 토큰 자동 정리: 마지막 등록(앱 실행) 시각과 비활성화 사유
+
+ALTER TABLE device_tokens ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP DEFAULT NOW();
+ALTER TABLE device_tokens ADD COLUMN IF NOT EXISTS deactivated_reason VARCHAR(20);  -- user, unregistered, invalid, expired, replaced, reassigned
+
+UPDATE device_tokens SET last_seen_at = updated_at WHERE last_seen_at IS NULL OR last_seen_at > updated_at;
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_last_seen ON device_tokens(last_seen_at) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_device_tokens_token ON device_tokens(token) WHERE is_active = true;
@@ -0,0 +1,5 @@
+-- 027_create_notification_preferences.down.sql
+-- 알림 설정 테이블 삭제
+
+ALTER TABLE push_notifications DROP COLUMN IF EXISTS deliver_after;
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,21 @@
+-- 027_create_notification_preferences.sql
+-- 사용자별 알림 설정: 유형별 수신 동의, 채널, 방해 금지 시간(사용자 시간대 기준)
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id                 BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    winning_result          BOOLEAN NOT NULL DEFAULT true,
+    draw_published          BOOLEAN NOT NULL DEFAULT true,
+    weekly_reminder         BOOLEAN NOT NULL DEFAULT true,
+    marketing               BOOLEAN NOT NULL DEFAULT false,   -- 광고성 정보는 명시적 동의 필요
+    marketing_consented_at  TIMESTAMP,                        -- 광고성 정보 수신 동의 일시
+    push_enabled            BOOLEAN NOT NULL DEFAULT true,
+    email_enabled           BOOLEAN NOT NULL DEFAULT false,
+    quiet_hours_enabled     BOOLEAN NOT NULL DEFAULT false,
+    quiet_start             VARCHAR(5) NOT NULL DEFAULT '22:00',  -- HH:MM
+    quiet_end               VARCHAR(5) NOT NULL DEFAULT '08:00',
+    timezone                VARCHAR(64) NOT NULL DEFAULT 'Asia/Seoul',
+    updated_at              TIMESTAMP DEFAULT NOW()
+);
+
+-- 방해 금지 시간에 생성된 알림은 이 시각 이후 발송
+ALTER TABLE push_notifications ADD COLUMN IF NOT EXISTS deliver_after TIMESTAMP;
@@ -0,0 +1,6 @@
+-- 028_create_notification_broadcasts.down.sql
+-- 전체 발송 기록 삭제
+
+DROP INDEX IF EXISTS idx_recommendations_user_draw;
+DROP INDEX IF EXISTS idx_device_tokens_active_user;
+DROP TABLE IF EXISTS notification_broadcasts;
@@ -0,0 +1,16 @@
+-- 028_create_notification_broadcasts.sql
+-- 회차별 전체 발송(당첨번호 발표, 판매 마감 리마인더) 기록
+-- (type, draw_no)당 한 번만 발송되도록 먼저 행을 선점한다.
+
+CREATE TABLE IF NOT EXISTS notification_broadcasts (
+    id            BIGSERIAL PRIMARY KEY,
+    type          VARCHAR(50) NOT NULL,
+    draw_no       INT NOT NULL,
+    recipients    INT NOT NULL DEFAULT 0,   -- 알림을 생성한 사용자 수
+    created_at    TIMESTAMP DEFAULT NOW(),
+    completed_at  TIMESTAMP,
+    UNIQUE(type, draw_no)
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_active_user ON device_tokens(user_id) WHERE is_active = true;
+CREATE INDEX IF NOT EXISTS idx_recommendations_user_draw ON lotto_recommendations(user_id, draw_no);
@@ -0,0 +1,5 @@
+-- 029_create_email_deliveries.down.sql
+-- 이메일 발송 큐 삭제
+
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS email_fallback;
+DROP TABLE IF EXISTS email_deliveries;
@@ -0,0 +1,23 @@
+-- 029_create_email_deliveries.sql
+-- 이메일 알림 발송 큐: 알림(push_notifications)별 이메일 발송 상태 (push_deliveries와 같은 상태 흐름)
+-- status: pending → sending(next_attempt_at까지 점유) → sent | dead
+
+CREATE TABLE IF NOT EXISTS email_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    notification_id  BIGINT NOT NULL UNIQUE REFERENCES push_notifications(id) ON DELETE CASCADE,
+    user_id          BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email            VARCHAR(255) NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts         INT NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error       TEXT,
+    created_at       TIMESTAMP DEFAULT NOW(),
+    updated_at       TIMESTAMP DEFAULT NOW(),
+    sent_at          TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_due ON email_deliveries(next_attempt_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_user ON email_deliveries(user_id, created_at DESC);
+
+-- 활성 디바이스 토큰이 없는 사용자에게 이메일로 대신 보낼지 (수신 거부 링크로 끌 수 있음)
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS email_fallback BOOLEAN NOT NULL DEFAULT true;
@@ -0,0 +1,6 @@
+-- 030_add_notification_read_state.down.sql
+-- 알림 읽음/보관 상태 삭제
+
+DROP INDEX IF EXISTS idx_push_notifications_unread;
+ALTER TABLE push_notifications DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE push_notifications DROP COLUMN IF EXISTS read_at;
@@ -0,0 +1,13 @@
+-- 030_add_notification_read_state.sql
+-- 알림함 읽음/보관 상태: 읽지 않은 알림 수(앱 배지)와 알림 탭의 읽음 표시에 사용
+-- read_at: 읽은 시각 (NULL이면 읽지 않음), archived_at: 보관 시각 (보관한 알림은 기본 목록과 배지에서 제외)
+
+ALTER TABLE push_notifications ADD COLUMN IF NOT EXISTS read_at TIMESTAMP;
+ALTER TABLE push_notifications ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+-- 기존 알림은 읽은 것으로 처리 (배포 직후 지난 알림 전체가 배지에 잡히지 않도록)
+UPDATE push_notifications SET read_at = COALESCE(sent_at, created_at) WHERE read_at IS NULL;
+
+-- 배지 수 조회 (푸시 발송마다 계산)
+CREATE INDEX IF NOT EXISTS idx_push_notifications_unread
+    ON push_notifications(user_id) WHERE read_at IS NULL AND archived_at IS NULL;
@@ -0,0 +1,4 @@
+-- 031_add_recommendation_holder_index.down.sql
+-- 대상 회차 추천 보유자 색인 갱신용 인덱스 삭제
+
+DROP INDEX IF EXISTS idx_recommendations_draw_id;
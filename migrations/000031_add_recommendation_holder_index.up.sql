@@ -0,0 +1,5 @@
+-- 031_add_recommendation_holder_index.sql
+-- 대상 회차 추천 보유자 색인 갱신용 인덱스
+-- 추천 요청마다 회차 전체를 다시 읽지 않고 마지막으로 읽은 추천 ID 이후만 조회한다.
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_draw_id ON lotto_recommendations(draw_no, id);
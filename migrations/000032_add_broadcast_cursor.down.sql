@@ -0,0 +1,5 @@
+-- 032_add_broadcast_cursor.down.sql
+-- 회차별 전체 발송 재개 정보 삭제
+
+ALTER TABLE notification_broadcasts DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE notification_broadcasts DROP COLUMN IF EXISTS last_user_id;
@@ -0,0 +1,7 @@
+-- 032_add_broadcast_cursor.sql
+-- 회차별 전체 발송 재개: 중간에 중단(서버 종료 등)된 발송을 다음 선점 때 이어서 보낸다.
+-- last_user_id: 앞에서부터 연속으로 처리 완료한 마지막 사용자 ID
+-- claimed_at: 선점/진행 기록 시각 (NULL이거나 오래되면 다른 선점 시도가 이어받음)
+
+ALTER TABLE notification_broadcasts ADD COLUMN IF NOT EXISTS last_user_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE notification_broadcasts ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP DEFAULT NOW();
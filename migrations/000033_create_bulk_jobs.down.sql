@@ -0,0 +1,4 @@
+-- 033_create_bulk_jobs.down.sql
+-- 대량 추천 작업 테이블 삭제
+
+DROP TABLE IF EXISTS lotto_bulk_jobs;
@@ -0,0 +1,23 @@
+-- 033_create_bulk_jobs.sql
+-- 대량 추천 작업 상태와 결과
+-- 작업은 제출한 서버의 워커 풀에서 실행되지만, 상태/결과 조회와 취소 요청은 어느 서버에서든 가능하다.
+-- updated_at: 진행 기록 시각 (오래 갱신되지 않은 미종료 작업은 실행 서버가 중단된 것으로 보고 실패 처리)
+
+CREATE TABLE IF NOT EXISTS lotto_bulk_jobs (
+    id                VARCHAR(64) PRIMARY KEY,
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type              VARCHAR(20) NOT NULL,
+    status            VARCHAR(20) NOT NULL,             -- QUEUED / RUNNING / COMPLETED / FAILED / CANCELLED
+    total             INT NOT NULL DEFAULT 0,
+    completed         INT NOT NULL DEFAULT 0,
+    error             TEXT,
+    result            JSONB,                            -- 완료된 작업 결과 (BulkJobResult)
+    cancel_requested  BOOLEAN NOT NULL DEFAULT false,   -- 다른 서버에서 받은 취소 요청
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    started_at        TIMESTAMP,
+    finished_at       TIMESTAMP,
+    updated_at        TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bulk_jobs_user ON lotto_bulk_jobs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bulk_jobs_active ON lotto_bulk_jobs(updated_at) WHERE finished_at IS NULL;